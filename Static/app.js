@@ -5,7 +5,8 @@
 // ARCHITECTURE:
 // - All Kabaddi scoring calculations, player status management, revivals, 
 //   all-out detection, do-or-die logic, super tackles, and bonus points are
-//   handled on the BACKEND (Go server in internal/handlers/matches.go)
+//   handled on the BACKEND (Go server in internal/scoring), using the
+//   rule set of the event the match belongs to
 // 
 // - This frontend is responsible ONLY for:
 //   1. Displaying the UI (teams, scores, players, raid info)
@@ -34,12 +35,30 @@ let bonusTaken = false;
 let emptyRaidCountA = 0;
 let emptyRaidCountB = 0;
 let isDoOrDieRaid = false;
+let matchRules = null; // Rule set chosen by the server for this match
+let matchClock = null; // Server match clock (see models.MatchClock)
+let clockTicker = null;
+let matchSubstitutionsUsed = null; // { teamA, teamB } from the server
+let matchTieBreak = null; // Knockout tie-break state (see models.TieBreakState)
+let matchReview = null; // Raid review in progress (see models.ReviewState)
+let matchOutQueue = { teamA: [], teamB: [] }; // Out players in revival order (see models.OutQueues)
 let currentRaidNumber = 1;
 let tossWinner = null; // 'teamA' | 'teamB'
 let tossDecision = 'raid'; // 'raid' | 'defend'
 let firstRaidingTeam = 'teamA';
 let requireServerRosterHydration = false;
 let serverRosterHydrated = false;
+let stateVersion = 0; // Version of the last match state received from the server
+const PROTOCOL_VERSION = 2; // Scorer socket protocol (see models.WSEnvelope)
+const PENDING_COMMANDS_KEY = 'pendingScorerCommands';
+let pendingCommands = {}; // commandId -> command sent but not yet acknowledged, in issue order
+let internalRetries = {}; // commandId -> times resent after an internal server error
+const MAX_INTERNAL_RETRIES = 5;
+let scorerStopped = false; // locked out or taken over; do not reconnect
+let reconnectDelay = 1000;
+const scorerPageParams = new URLSearchParams(window.location.search);
+const scorerRole = scorerPageParams.get('role') || 'chief'; // chief | assistant | mirror (see models.ScorerRole*)
+const scorerDevice = scorerPageParams.get('device') || '';
 let teamACaptainId = '';
 let teamAViceCaptainId = '';
 let teamBCaptainId = '';
@@ -128,6 +147,187 @@ if (!isAuthenticated()) {
     window.location.href = `/login?returnUrl=${currentUrl}`;
 }
 
+function newCommandId() {
+    if (window.crypto && crypto.randomUUID) return crypto.randomUUID();
+    return `${Date.now().toString(36)}-${Math.random().toString(36).slice(2, 10)}`;
+}
+
+function savePendingCommands() {
+    try { localStorage.setItem(`${PENDING_COMMANDS_KEY}:${matchId}`, JSON.stringify(pendingCommands)); } catch (e) { /* ignore */ }
+}
+
+// Commands can be issued once a match has been joined. While the socket is down
+// they wait in pendingCommands and go to the server as one batch on reconnect.
+function scorerCanQueue() {
+    return socket !== null && !scorerStopped;
+}
+
+function isScorerOnline() {
+    return socket !== null && socket.readyState === WebSocket.OPEN;
+}
+
+function updateQueuedStatus() {
+    const queued = Object.keys(pendingCommands).length;
+    if (isScorerOnline()) {
+        setConnectionStatus('Connected');
+    } else if (!scorerStopped) {
+        setConnectionStatus(queued ? `Offline · ${queued} queued` : 'Offline');
+    }
+}
+
+// Wrap a scorer command in the protocol envelope with an id, the state version it
+// builds on and when it was issued, then send it. The command stays pending until
+// the server acknowledges it, so it can be resent safely.
+function sendScorerCommand(command) {
+    if (scorerRole === 'mirror') {
+        alert('This is a read-only scorer mirror.');
+        return;
+    }
+    const { type, data, ...fields } = command;
+    let msgType = type;
+    let payload = fields;
+    if (!type && command.raidType) msgType = 'raid';
+    if (type === 'lobbyTouch') payload = data;
+    if (type === 'initialState') payload = { data };
+    const envelope = { v: PROTOCOL_VERSION, type: msgType, id: newCommandId(), baseVersion: stateVersion, at: Date.now(), payload };
+    pendingCommands[envelope.id] = envelope;
+    savePendingCommands();
+    if (isScorerOnline()) {
+        socket.send(JSON.stringify(envelope));
+        return;
+    }
+    // Offline: move on to the next raid so the scorer can keep going; the server's
+    // state replaces this guess once the queue is synced
+    if (msgType === 'raid' || msgType === 'lobbyTouch') {
+        currentRaidNumber++;
+        nextRaid();
+    }
+    updateQueuedStatus();
+}
+
+// Open the scorer session, offering every protocol version this page speaks
+function sendJoin() {
+    const payload = { matchId, versions: [PROTOCOL_VERSION], role: scorerRole };
+    if (scorerDevice) payload.device = scorerDevice;
+    socket.send(JSON.stringify({ v: PROTOCOL_VERSION, type: 'join', payload }));
+}
+
+// Send unacknowledged commands as one batch after (re)joining. The server applies
+// them in order, skips any it already applied and answers with batchResult.
+function resendPendingCommands() {
+    try {
+        const stored = localStorage.getItem(`${PENDING_COMMANDS_KEY}:${matchId}`);
+        if (stored) pendingCommands = { ...JSON.parse(stored), ...pendingCommands };
+    } catch (e) { /* ignore */ }
+    const commands = Object.values(pendingCommands);
+    if (commands.length === 0) return;
+    if (scorerRole !== 'chief') {
+        // Assistants cannot batch; each command is proposed again and the server
+        // ignores proposals it already holds
+        commands.forEach(command => socket.send(JSON.stringify(command)));
+        return;
+    }
+    socket.send(JSON.stringify({ v: PROTOCOL_VERSION, type: 'batch', id: newCommandId(), payload: { commands } }));
+}
+
+// Settle the commands the server answered for; any without a result stay queued
+function handleBatchResult(payload) {
+    const rejected = [];
+    (payload.results || []).forEach(result => {
+        settleCommand(result.id);
+        if (result.status === 'rejected') rejected.push(`${result.type}: ${result.reason}`);
+    });
+    if (payload.state && payload.state.data) applyServerState(payload.state.data);
+    updateQueuedStatus();
+    if (rejected.length) {
+        alert(`Some actions recorded offline were not accepted:\n${rejected.join('\n')}`);
+    }
+}
+
+// Join the match on a fresh socket and sync anything queued meanwhile
+function onScorerSocketOpen() {
+    reconnectDelay = 1000;
+    sendJoin();
+    resendPendingCommands();
+    updateQueuedStatus();
+}
+
+function scheduleScorerReconnect() {
+    if (scorerStopped || !matchId) return;
+    setTimeout(() => {
+        socket = null;
+        setupWebSocket();
+        if (socket) socket.onopen = onScorerSocketOpen;
+    }, reconnectDelay);
+    reconnectDelay = Math.min(reconnectDelay * 2, 30000);
+}
+
+// Ask the chief to confirm or reject an assistant's proposed command
+function handleProposal(proposal) {
+    if (scorerRole !== 'chief') return;
+    const by = (proposal.proposedBy && (proposal.proposedBy.device || proposal.proposedBy.userId)) || 'assistant';
+    const ok = confirm(`Assistant scorer (${by}) proposes: ${describeProposal(proposal)}\n\nConfirm?`);
+    socket.send(JSON.stringify({
+        v: PROTOCOL_VERSION,
+        type: ok ? 'confirmProposal' : 'rejectProposal',
+        id: newCommandId(),
+        payload: { proposalId: proposal.id }
+    }));
+}
+
+function describeProposal(proposal) {
+    const payload = (proposal.command && proposal.command.payload) || {};
+    if (proposal.type === 'raid' && payload.raidType) return `raid (${payload.raidType})`;
+    return proposal.type;
+}
+
+function handleProposalResolved(payload) {
+    if (!pendingCommands[payload.proposalId]) return;
+    settleCommand(payload.proposalId);
+    updateQueuedStatus();
+    if (payload.status === 'rejected') {
+        alert(`The chief scorer rejected your proposal${payload.reason ? `: ${payload.reason}` : ''}.`);
+    }
+}
+
+// Show who else is on the scorer side
+function showScorerRoom(room) {
+    const el = document.getElementById('scorer-room');
+    if (!el) return;
+    const counts = { chief: 0, assistant: 0, mirror: 0 };
+    (room.connections || []).forEach(conn => { counts[conn.role] = (counts[conn.role] || 0) + 1; });
+    const proposals = (room.proposals || []).length;
+    el.textContent = `You: ${scorerRole} · Chief ${counts.chief} · Assistants ${counts.assistant} · Mirrors ${counts.mirror}` +
+        (proposals ? ` · ${proposals} pending proposal(s)` : '') +
+        (room.handoff ? ' · handoff in progress' : '');
+}
+
+// Nothing was applied when the server fails internally, such as when it cannot
+// load the match rules for initialState, so the command is kept and sent again
+// after a pause. Returns false once the command has been retried enough.
+function retryAfterInternalError(commandId) {
+    const command = pendingCommands[commandId];
+    if (!command) return false;
+    const attempts = (internalRetries[commandId] || 0) + 1;
+    if (attempts > MAX_INTERNAL_RETRIES) {
+        delete internalRetries[commandId];
+        return false;
+    }
+    internalRetries[commandId] = attempts;
+    setConnectionStatus('Retrying…');
+    setTimeout(() => {
+        if (pendingCommands[commandId] && isScorerOnline()) socket.send(JSON.stringify(command));
+    }, 1000 * attempts);
+    return true;
+}
+
+function settleCommand(commandId) {
+    if (!commandId || !pendingCommands[commandId]) return;
+    delete pendingCommands[commandId];
+    delete internalRetries[commandId];
+    savePendingCommands();
+}
+
 function setupWebSocket() {
     if (socket !== null) {
         console.log("WebSocket already exists");
@@ -158,37 +358,19 @@ function setupWebSocket() {
             if (msg.type === 'scorerTakeover') {
                 const takeoverMsg = msg.message || 'Continued on other device';
                 const redirectUrl = msg.redirectUrl || '/organizer/dashboard';
+                scorerStopped = true;
                 alert(takeoverMsg);
                 window.location.href = redirectUrl;
                 return;
             }
-            // Server requests client to initialize game state on first connection
-            if (msg.type === 'requestInit') {
-                const initialState = {
-                    type: 'initialState',
-                    data: {
-                        teamA: { name: teamA.name, score: teamA.score },
-                        teamB: { name: teamB.name, score: teamB.score },
-                        teamACaptainId: teamACaptainId,
-                        teamAViceCaptainId: teamAViceCaptainId,
-                        teamBCaptainId: teamBCaptainId,
-                        teamBViceCaptainId: teamBViceCaptainId,
-                        playerStats: playerStats,
-                        teamAPlayerIds: teamA.players.map(p => p.id),
-                        teamBPlayerIds: teamB.players.map(p => p.id),
-                        raidNumber: currentRaidNumber,
-                        emptyRaidCounts: { teamA: emptyRaidCountA, teamB: emptyRaidCountB },
-                        tossWinner: tossWinner,
-                        tossDecision: tossDecision,
-                        firstRaidingTeam: firstRaidingTeam
-                    }
-                };
-                socket.send(JSON.stringify(initialState));
+            if (msg.v >= PROTOCOL_VERSION) {
+                handleServerMessage(msg);
                 return;
             }
             if (msg.error) {
                 const errText = String(msg.error || '');
                 if (errText.toLowerCase().includes('already being scored')) {
+                    scorerStopped = true;
                     setConnectionStatus('Locked');
                     showScorerLockNotice('This match is locked by other device.');
                     try { socket.close(); } catch (e) { /* ignore */ }
@@ -197,40 +379,7 @@ function setupWebSocket() {
                 alert(`Server error: ${msg.error}`);
                 return;
             }
-            if (msg.data) {
-                // Backend sends complete calculated state - just update UI
-                if (msg.data.teamA) teamA.score = msg.data.teamA.score;
-                if (msg.data.teamB) teamB.score = msg.data.teamB.score;
-                if (msg.data.playerStats) {
-                    playerStats = msg.data.playerStats;
-                    const hydrated = hydrateTeamsFromServerState(msg.data);
-                    if (requireServerRosterHydration && hydrated) {
-                        requireServerRosterHydration = false;
-                    }
-                    // sync the per-player `status` into the team player objects
-                    syncPlayerStatusesFromPlayerStats();
-                }
-                if (msg.data.raidNumber) currentRaidNumber = msg.data.raidNumber;
-
-                // Update empty raid counts from backend (server is source of truth)
-                if (msg.data.emptyRaidCounts) {
-                    emptyRaidCountA = msg.data.emptyRaidCounts.teamA;
-                    emptyRaidCountB = msg.data.emptyRaidCounts.teamB;
-                }
-
-                if (msg.data.tossWinner) tossWinner = msg.data.tossWinner;
-                if (msg.data.tossDecision) tossDecision = msg.data.tossDecision;
-                if (msg.data.firstRaidingTeam) firstRaidingTeam = msg.data.firstRaidingTeam;
-                teamACaptainId = msg.data.teamACaptainId || msg.data.TeamACaptainID || teamACaptainId;
-                teamAViceCaptainId = msg.data.teamAViceCaptainId || msg.data.TeamAViceCaptainID || teamAViceCaptainId;
-                teamBCaptainId = msg.data.teamBCaptainId || msg.data.TeamBCaptainID || teamBCaptainId;
-                teamBViceCaptainId = msg.data.teamBViceCaptainId || msg.data.TeamBViceCaptainID || teamBViceCaptainId;
-
-                updateDisplay();
-                updateRaidInfoUI();
-                updateTossInfoUI();
-                nextRaid(); // Reset UI selections for the next raid
-            }
+            if (msg.data) applyServerState(msg.data);
         } catch (e) {
             console.error('Invalid WS message', e);
         }
@@ -242,8 +391,142 @@ function setupWebSocket() {
     
     socket.onclose = () => {
         console.log("WebSocket connection closed.");
-        setConnectionStatus('Disconnected');
+        if (scorerStopped) {
+            setConnectionStatus('Disconnected');
+            return;
+        }
+        updateQueuedStatus();
+        scheduleScorerReconnect();
+    };
+}
+
+// Server requests client to initialize game state on first connection
+function sendInitialState() {
+    const initialState = {
+        type: 'initialState',
+        data: {
+            teamA: { name: teamA.name, score: teamA.score },
+            teamB: { name: teamB.name, score: teamB.score },
+            teamACaptainId: teamACaptainId,
+            teamAViceCaptainId: teamAViceCaptainId,
+            teamBCaptainId: teamBCaptainId,
+            teamBViceCaptainId: teamBViceCaptainId,
+            playerStats: playerStats,
+            teamAPlayerIds: teamA.players.map(p => p.id),
+            teamBPlayerIds: teamB.players.map(p => p.id),
+            raidNumber: currentRaidNumber,
+            emptyRaidCounts: { teamA: emptyRaidCountA, teamB: emptyRaidCountB },
+            tossWinner: tossWinner,
+            tossDecision: tossDecision,
+            firstRaidingTeam: firstRaidingTeam
+        }
     };
+    sendScorerCommand(initialState);
+}
+
+// Handle a message in the typed protocol envelope
+function handleServerMessage(msg) {
+    const payload = msg.payload || {};
+    switch (msg.type) {
+        case 'welcome':
+            console.log('Scorer protocol', payload.version);
+            break;
+        case 'state':
+            if (payload.data) applyServerState(payload.data);
+            break;
+        case 'requestInit':
+            sendInitialState();
+            break;
+        case 'ack':
+            settleCommand(msg.id);
+            updateQueuedStatus();
+            break;
+        case 'batchResult':
+            handleBatchResult(payload);
+            break;
+        case 'proposal':
+            handleProposal(payload);
+            break;
+        case 'proposalResolved':
+            handleProposalResolved(payload);
+            break;
+        case 'scorerRoom':
+            showScorerRoom(payload);
+            break;
+        case 'scorerHandoff':
+            // The lock went to another scorer; this page stops scoring
+            scorerStopped = true;
+            setConnectionStatus('Handed off');
+            alert('Scoring has been handed to another scorer.');
+            window.location.href = '/organizer/dashboard';
+            break;
+        case 'conflict':
+            // Someone else changed the match first; show their state and let the scorer redo the action
+            settleCommand(msg.id);
+            if (payload.state && payload.state.data) applyServerState(payload.state.data);
+            alert('The match changed before your last action reached the server. Check the score and try again.');
+            break;
+        case 'error':
+            if (payload.code === 'internal' && retryAfterInternalError(msg.id)) break;
+            settleCommand(msg.id);
+            if (payload.code === 'scorer_locked') {
+                scorerStopped = true;
+                setConnectionStatus('Locked');
+                showScorerLockNotice('This match is locked by other device.');
+                try { socket.close(); } catch (e) { /* ignore */ }
+                break;
+            }
+            alert(`Server error: ${payload.message}`);
+            break;
+    }
+}
+
+// Update the UI from the complete match state calculated by the backend
+function applyServerState(data) {
+    // Backend sends complete calculated state - just update UI
+    if (data.teamA) teamA.score = data.teamA.score;
+    if (data.teamB) teamB.score = data.teamB.score;
+    if (data.playerStats) {
+        playerStats = data.playerStats;
+        const hydrated = hydrateTeamsFromServerState(data);
+        if (requireServerRosterHydration && hydrated) {
+            requireServerRosterHydration = false;
+        }
+        // sync the per-player `status` into the team player objects
+        syncPlayerStatusesFromPlayerStats();
+    }
+    if (data.raidNumber) currentRaidNumber = data.raidNumber;
+    if (typeof data.version === 'number') stateVersion = data.version;
+
+    // Update empty raid counts from backend (server is source of truth)
+    if (data.emptyRaidCounts) {
+        emptyRaidCountA = data.emptyRaidCounts.teamA;
+        emptyRaidCountB = data.emptyRaidCounts.teamB;
+    }
+
+    if (data.tossWinner) tossWinner = data.tossWinner;
+    if (data.tossDecision) tossDecision = data.tossDecision;
+    if (data.firstRaidingTeam) firstRaidingTeam = data.firstRaidingTeam;
+    if (data.rules && data.rules.squadSize) matchRules = data.rules;
+    if (data.substitutionsUsed) matchSubstitutionsUsed = data.substitutionsUsed;
+    matchOutQueue = data.outQueue || { teamA: [], teamB: [] };
+    matchTieBreak = data.tieBreak && data.tieBreak.phase ? data.tieBreak : null;
+    updateTieBreakUI();
+    matchReview = data.review && data.review.status ? data.review : null;
+    updateReviewUI();
+    if (data.clock) {
+        matchClock = data.clock;
+        updateClockUI();
+    }
+    teamACaptainId = data.teamACaptainId || data.TeamACaptainID || teamACaptainId;
+    teamAViceCaptainId = data.teamAViceCaptainId || data.TeamAViceCaptainID || teamAViceCaptainId;
+    teamBCaptainId = data.teamBCaptainId || data.TeamBCaptainID || teamBCaptainId;
+    teamBViceCaptainId = data.teamBViceCaptainId || data.TeamBViceCaptainID || teamBViceCaptainId;
+
+    updateDisplay();
+    updateRaidInfoUI();
+    updateTossInfoUI();
+    nextRaid(); // Reset UI selections for the next raid
 }
 
 // UI helpers for match status
@@ -318,9 +601,16 @@ function getDefendingTeam() {
 }
 
 function getRaidingTeam() {
-    const firstTeam = firstRaidingTeam === 'teamB' ? teamB : teamA;
+    let firstTeam = firstRaidingTeam === 'teamB' ? teamB : teamA;
+    let raidsIntoHalf = currentRaidNumber - 1;
+    // The other team opens the second half (mirrors scoring.ExpectedRaidingTeam)
+    const secondHalfStart = matchClock && matchClock.secondHalfStartRaid;
+    if (secondHalfStart && currentRaidNumber >= secondHalfStart) {
+        firstTeam = firstTeam === teamA ? teamB : teamA;
+        raidsIntoHalf = currentRaidNumber - secondHalfStart;
+    }
     const secondTeam = firstTeam === teamA ? teamB : teamA;
-    return currentRaidNumber % 2 !== 0 ? firstTeam : secondTeam;
+    return raidsIntoHalf % 2 === 0 ? firstTeam : secondTeam;
 }
 
 
@@ -343,8 +633,8 @@ function handleLobbyTouch(player, isRaiderTouchingLobby) {
         }
     };
 
-    if (socket.readyState === WebSocket.OPEN) {
-        socket.send(JSON.stringify(lobbyPayload));
+    if (scorerCanQueue()) {
+        sendScorerCommand(lobbyPayload);
     } else {
         alert('Socket not connected');
     }
@@ -422,10 +712,14 @@ async function endGame() {
         return;
     }
 
+    const tieBreakWinner = matchTieBreak && matchTieBreak.phase === 'decided' ? matchTieBreak.winner : '';
     if (teamA.score > teamB.score) {
         message = `${teamA.name || 'Team A'} wins`;
     } else if (teamA.score < teamB.score) {
         message = `${teamB.name || 'Team B'} wins`;
+    } else if (tieBreakWinner) {
+        const winnerName = tieBreakWinner === 'A' ? (teamA.name || 'Team A') : (teamB.name || 'Team B');
+        message = `${winnerName} wins the tie-break ${matchTieBreak.score.teamA}-${matchTieBreak.score.teamB}`;
     } else {
         message = "It was a tie";
     }
@@ -448,11 +742,14 @@ async function endGame() {
 
     const queryParams = new URLSearchParams(window.location.search);
 
-    if (teamA.score === teamB.score) {
+    if (teamA.score === teamB.score && !tieBreakWinner) {
         try {
             const tieValidation = await validateNoTieForKnockoutStage(queryParams, token);
             if (tieValidation?.blocked) {
-                alert(tieValidation.message || 'Tie is not allowed at this knockout stage.');
+                game = true;
+                if (confirm(`${tieValidation.message || 'Tie is not allowed at this knockout stage.'}\n\nStart the tie-break (five raids each, then a golden raid)?`)) {
+                    startTieBreak();
+                }
                 return;
             }
         } catch (validationError) {
@@ -623,8 +920,8 @@ function raidSuccessful() {
         bonusTaken: bonusTaken
     };
 
-    if (socket.readyState === WebSocket.OPEN) {
-        socket.send(JSON.stringify(payload));
+    if (scorerCanQueue()) {
+        sendScorerCommand(payload);
     } else {
         alert('Socket not connected');
     }
@@ -644,8 +941,8 @@ function defenseSuccessful() {
         bonusTaken: bonusTaken
     };
     
-    if (socket.readyState === WebSocket.OPEN) {
-        socket.send(JSON.stringify(payload));
+    if (scorerCanQueue()) {
+        sendScorerCommand(payload);
     }
 }
 
@@ -663,11 +960,77 @@ function emptyRaid() {
         bonusTaken: bonusTaken
     };
     
-    if (socket.readyState === WebSocket.OPEN) {
-        socket.send(JSON.stringify(payload));
+    if (scorerCanQueue()) {
+        sendScorerCommand(payload);
+    }
+}
+
+function undoRaid() {
+    // Backend rebuilds the match from its raid log and broadcasts the corrected state
+    if (scorerCanQueue()) {
+        sendScorerCommand({ type: "undo" });
+    } else {
+        alert('Socket not connected');
+    }
+}
+
+function redoRaid() {
+    if (scorerCanQueue()) {
+        sendScorerCommand({ type: "redo" });
+    } else {
+        alert('Socket not connected');
+    }
+}
+
+function sendClockCommand(action, team) {
+    // Server owns the clock; it answers with the updated match state
+    const payload = { type: "clock", action: action };
+    if (team) payload.team = team;
+    if (scorerCanQueue()) {
+        sendScorerCommand(payload);
+    } else {
+        alert('Socket not connected');
+    }
+}
+
+function formatClock(totalSeconds) {
+    const secs = Math.max(0, Math.floor(totalSeconds));
+    const m = Math.floor(secs / 60);
+    const s = secs % 60;
+    return `${String(m).padStart(2, '0')}:${String(s).padStart(2, '0')}`;
+}
+
+function clockRemainingSeconds(clock) {
+    if (!clock || !clock.halfDurationSec) return 0;
+    let elapsed = clock.elapsedSec || 0;
+    if (clock.runningSince) {
+        elapsed += Math.max(0, Math.floor(Date.now() / 1000) - clock.runningSince);
     }
+    return clock.halfDurationSec - elapsed;
 }
 
+function updateClockUI() {
+    const el = document.getElementById('match-clock');
+    if (!el) return;
+    const clock = matchClock || { status: 'notStarted' };
+    const labels = {
+        notStarted: 'Not started',
+        running: 'Running',
+        paused: 'Paused',
+        timeout: `Timeout (${clock.timeoutTeam === 'B' ? teamB.name : teamA.name})`,
+        halfTime: 'Half time',
+        fullTime: 'Full time'
+    };
+    const half = clock.half ? `Half ${clock.half}` : '';
+    el.innerHTML = `<strong>${formatClock(clockRemainingSeconds(clock))}</strong> ${half} | ${labels[clock.status] || clock.status || ''}`;
+
+    if (clock.status === 'running' && !clockTicker) {
+        clockTicker = setInterval(updateClockUI, 1000);
+    } else if (clock.status !== 'running' && clockTicker) {
+        clearInterval(clockTicker);
+        clockTicker = null;
+    }
+}
 
 /**
  * UI Rendering and Updates
@@ -706,6 +1069,11 @@ function renderPlayers() {
 
             if (player.status === "in") {
                 btn.classList.add("btn-outline-primary");
+            } else if (player.status === "bench") {
+                // Substitutes sit out of raids until brought on
+                btn.classList.add("btn-outline-secondary");
+                btn.disabled = true;
+                btn.style.opacity = "0.8";
             } else {
                 btn.classList.add("btn-secondary");
                 btn.style.textDecoration = "line-through";
@@ -726,7 +1094,12 @@ function renderPlayers() {
                   btn.setAttribute("aria-pressed", "false");
               }
 
-            btn.textContent = getPlayerDisplayName(player, teamSide);
+            const statusLabels = { bench: " (bench)", suspended: " (yellow card)", sentOff: " (sent off)" };
+            let label = statusLabels[player.status] || "";
+            const queuePos = ((teamSide === 'A' ? matchOutQueue.teamA : matchOutQueue.teamB) || [])
+                .findIndex(entry => entry.playerId === player.id);
+            if (player.status === "out" && queuePos >= 0) label = ` (next in #${queuePos + 1})`;
+            btn.textContent = getPlayerDisplayName(player, teamSide) + label;
             btn.onclick = () => handlePlayerClick(player.id);
 
             container.appendChild(btn);
@@ -735,6 +1108,178 @@ function renderPlayers() {
 
     render(teamA, "teamA-players");
     render(teamB, "teamB-players");
+    renderSubstitutionPanel();
+    renderCardPanel();
+}
+
+// Fill the substitution selects with the chosen team's on-mat and bench players
+function renderSubstitutionPanel() {
+    const teamSelect = document.getElementById('sub-team');
+    const outSelect = document.getElementById('sub-out');
+    const inSelect = document.getElementById('sub-in');
+    if (!teamSelect || !outSelect || !inSelect) return;
+
+    const team = teamSelect.value === 'B' ? teamB : teamA;
+    const fill = (select, status) => {
+        const previous = select.value;
+        select.innerHTML = "";
+        team.players.filter(p => p.status === status).forEach(p => {
+            const opt = document.createElement("option");
+            opt.value = p.id;
+            opt.textContent = p.name;
+            select.appendChild(opt);
+        });
+        if ([...select.options].some(o => o.value === previous)) select.value = previous;
+    };
+    fill(outSelect, "in");
+    fill(inSelect, "bench");
+
+    const used = document.getElementById('sub-used');
+    if (used) {
+        const counts = matchSubstitutionsUsed || {};
+        const limit = matchRules && matchRules.substitutionsPerTeam !== undefined ? matchRules.substitutionsPerTeam : '-';
+        used.textContent = `Used: ${teamSelect.value === 'B' ? (counts.teamB || 0) : (counts.teamA || 0)} / ${limit}`;
+    }
+}
+
+// Fill the card player select with every rostered player
+function renderCardPanel() {
+    const select = document.getElementById('card-player');
+    if (!select) return;
+    const previous = select.value;
+    select.innerHTML = "";
+    [teamA, teamB].forEach(team => {
+        team.players.filter(p => p.status !== "sentOff").forEach(p => {
+            const opt = document.createElement("option");
+            opt.value = p.id;
+            opt.textContent = `${p.name} (${team.name})`;
+            select.appendChild(opt);
+        });
+    });
+    if ([...select.options].some(o => o.value === previous)) select.value = previous;
+}
+
+function sendTechnicalPoint(team) {
+    const reason = (document.getElementById('penalty-reason') || {}).value || '';
+    sendRefereeCommand({ type: "technicalPoint", team: team, points: 1, reason: reason });
+}
+
+function sendCard(card) {
+    const playerId = document.getElementById('card-player').value;
+    if (!playerId) {
+        alert('Pick a player to show the card to');
+        return;
+    }
+    const reason = (document.getElementById('penalty-reason') || {}).value || '';
+    // Suspension length comes from the match rules
+    sendRefereeCommand({ type: "card", playerId: playerId, card: card, reason: reason });
+}
+
+function sendRefereeCommand(payload) {
+    if (scorerCanQueue()) {
+        sendScorerCommand(payload);
+    } else {
+        alert('Socket not connected');
+    }
+}
+
+function startTieBreak() {
+    // Server checks the scores are level and regulation time is over
+    sendRefereeCommand({ type: "tieBreak" });
+}
+
+function updateTieBreakUI() {
+    const el = document.getElementById('tie-break-status');
+    if (!el) return;
+    if (!matchTieBreak) {
+        el.style.display = 'none';
+        return;
+    }
+    const labels = { shootout: 'Tie-break shootout', goldenRaid: 'Golden raid', decided: 'Tie-break decided' };
+    const score = matchTieBreak.score || {};
+    const raids = matchTieBreak.raidsTaken || {};
+    let text = `${labels[matchTieBreak.phase] || matchTieBreak.phase}: ${teamA.name || 'Team A'} ${score.teamA || 0} - ${score.teamB || 0} ${teamB.name || 'Team B'}`;
+    if (matchTieBreak.phase === 'shootout') {
+        text += ` (raids ${raids.teamA || 0}/5 - ${raids.teamB || 0}/5)`;
+    }
+    el.textContent = text;
+    el.style.display = 'block';
+}
+
+function requestReview() {
+    const team = document.getElementById('review-team').value;
+    const raidNumber = parseInt(document.getElementById('review-raid').value, 10);
+    if (!raidNumber) {
+        alert('Enter the raid number to review');
+        return;
+    }
+    sendRefereeCommand({ type: "review", action: "request", team: team, raidNumber: raidNumber });
+}
+
+function sendReviewVerdict(verdict) {
+    const payload = { type: "review", action: "verdict", verdict: verdict };
+    if (verdict === 'overturned') {
+        const raiderId = document.getElementById('correction-raider').value;
+        if (!raiderId) {
+            alert('Pick the raider for the corrected raid');
+            return;
+        }
+        const raidType = document.getElementById('correction-type').value;
+        payload.correction = {
+            raidType: raidType,
+            raiderId: raiderId,
+            defenderIds: raidType === 'empty' ? [] : [...document.getElementById('correction-defenders').selectedOptions].map(o => o.value),
+            bonusTaken: document.getElementById('correction-bonus').checked
+        };
+    }
+    sendRefereeCommand(payload);
+}
+
+// Show the review controls that fit the current state; raids are frozen while a review runs
+function updateReviewUI() {
+    const requestBox = document.getElementById('review-request');
+    const verdictBox = document.getElementById('review-verdict');
+    if (!requestBox || !verdictBox) return;
+    if (!matchReview) {
+        requestBox.style.display = 'flex';
+        verdictBox.style.display = 'none';
+        const raidInput = document.getElementById('review-raid');
+        // Only the most recent raid can be reviewed
+        if (raidInput && currentRaidNumber > 1) raidInput.value = currentRaidNumber - 1;
+        return;
+    }
+    requestBox.style.display = 'none';
+    verdictBox.style.display = 'flex';
+    const reviewingTeam = matchReview.team === 'B' ? teamB : teamA;
+    document.getElementById('review-status').textContent = `Review in progress: raid ${matchReview.raidNumber} (${reviewingTeam.name})`;
+
+    const players = [...teamA.players, ...teamB.players];
+    ['correction-raider', 'correction-defenders'].forEach(id => {
+        const select = document.getElementById(id);
+        if (select.options.length === players.length) return;
+        select.innerHTML = "";
+        players.forEach(p => {
+            const opt = document.createElement("option");
+            opt.value = p.id;
+            opt.textContent = p.name;
+            select.appendChild(opt);
+        });
+    });
+}
+
+function sendSubstitution() {
+    const outId = document.getElementById('sub-out').value;
+    const inId = document.getElementById('sub-in').value;
+    if (!outId || !inId) {
+        alert('Pick a player on the mat and a substitute');
+        return;
+    }
+    // Server validates the swap and answers with the updated match state
+    if (scorerCanQueue()) {
+        sendScorerCommand({ type: "substitution", outPlayerId: outId, inPlayerId: inId });
+    } else {
+        alert('Socket not connected');
+    }
 }
 
 // Sync statuses from the authoritative playerStats map (received from backend server)
@@ -780,7 +1325,8 @@ function updateRaidInfoUI() {
 
     const raidingTeam = getRaidingTeam();
     const emptyCount = raidingTeam.name === teamA.name ? emptyRaidCountA : emptyRaidCountB;
-    const raidType = emptyCount === 2 ? "🔴 Do or Die Raid" : "Normal Raid";
+    const doOrDieAfter = matchRules ? matchRules.doOrDieEmptyRaids : 2;
+    const raidType = doOrDieAfter > 0 && emptyCount >= doOrDieAfter ? "🔴 Do or Die Raid" : "Normal Raid";
 
     raidElement.innerHTML = `Raid: <strong>${currentRaidNumber}</strong> | Turn: <strong>${raidingTeam.name}</strong> | Status: <strong>${raidType}</strong>`;
 }
@@ -996,7 +1542,8 @@ document.addEventListener("DOMContentLoaded", async () => {
                     setupWebSocket();
                     if (socket) {
                         socket.onopen = () => {
-                            socket.send(JSON.stringify({ type: 'join', matchId }));
+                            sendJoin();
+                            resendPendingCommands();
                             setConnectionStatus('Connected');
                             console.log('Joined match:', matchId);
                         };
@@ -1017,7 +1564,8 @@ document.addEventListener("DOMContentLoaded", async () => {
                     setupWebSocket();
                     if (socket) {
                         socket.onopen = () => {
-                            socket.send(JSON.stringify({ type: 'join', matchId }));
+                            sendJoin();
+                            resendPendingCommands();
                             setConnectionStatus('Connected');
                             console.log('Auto-joined match:', matchId);
                         };
@@ -1044,7 +1592,8 @@ document.addEventListener("DOMContentLoaded", async () => {
                     setupWebSocket();
                     if (socket) {
                         socket.onopen = () => {
-                            socket.send(JSON.stringify({ type: 'join', matchId }));
+                            sendJoin();
+                            resendPendingCommands();
                             setConnectionStatus('Connected');
                             console.log('Auto-joined stored match:', matchId);
                         };
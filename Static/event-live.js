@@ -0,0 +1,64 @@
+// Live channel for a tournament or championship: one socket carries score ticks for
+// every ongoing fixture, fixture status changes and standings/bracket updates.
+// Live scores are kept in liveScores by fixture ID and written into any element
+// with a matching data-live-score attribute.
+const liveScores = {};
+
+function renderLiveScore(fixtureId) {
+    const tick = liveScores[fixtureId];
+    if (!tick) return '';
+    return `${tick.teamA?.score ?? 0} - ${tick.teamB?.score ?? 0}`;
+}
+
+function applyScoreTick(tick) {
+    const prev = liveScores[tick.fixtureId];
+    if (prev && prev.matchId === tick.matchId && prev.version > tick.version) return;
+    liveScores[tick.fixtureId] = tick;
+    document.querySelectorAll(`[data-live-score="${tick.fixtureId}"]`).forEach(el => {
+        el.textContent = renderLiveScore(tick.fixtureId);
+    });
+}
+
+// connectEventChannel joins the channel and reconnects with backoff when it drops.
+// onChange runs whenever fixtures, standings or the bracket may have changed.
+function connectEventChannel(eventType, eventId, onChange) {
+    let delay = 1000;
+    let stopped = false;
+    const open = () => {
+        const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
+        const ws = new WebSocket(`${proto}//${location.host}/ws/event`);
+        ws.onopen = () => {
+            delay = 1000;
+            ws.send(JSON.stringify({ type: 'join', eventType, eventId }));
+        };
+        ws.onmessage = (event) => {
+            let msg;
+            try { msg = JSON.parse(event.data); } catch (e) { return; }
+            if (msg.error) {
+                // Unknown tournament or championship; retrying will not help
+                stopped = true;
+                return;
+            }
+            switch (msg.type) {
+                case 'eventSnapshot':
+                    (msg.live || []).forEach(applyScoreTick);
+                    break;
+                case 'scoreTick':
+                    applyScoreTick(msg);
+                    break;
+                case 'fixtureStatus':
+                case 'standings':
+                case 'bracket':
+                    if (msg.type === 'fixtureStatus' && msg.status !== 'ongoing') delete liveScores[msg.fixtureId];
+                    onChange(msg);
+                    break;
+            }
+        };
+        ws.onclose = () => {
+            if (stopped) return;
+            setTimeout(open, delay);
+            delay = Math.min(delay * 2, 30000);
+        };
+    };
+    open();
+}
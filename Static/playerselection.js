@@ -2,11 +2,15 @@ let team1Players = [];
 let team2Players = [];
 let selectedTeam1 = [];
 let selectedTeam2 = [];
+// Squad and bench sizes come from the rule set; 7 + 5 until the server says otherwise
+let squadSize = 7;
+let benchSize = 5;
 
 document.addEventListener("DOMContentLoaded", async () => {
   const params = new URLSearchParams(window.location.search);
   const team1Id = params.get("team1_id");
   const team2Id = params.get("team2_id");
+  const matchId = params.get("match_id");
 
   if (!team1Id || !team2Id) return;
 
@@ -16,7 +20,9 @@ document.addEventListener("DOMContentLoaded", async () => {
       'Authorization': `Bearer ${token}`
     }
   };
-  
+
+  await loadRuleLimits(matchId, fetchOptions);
+
   const [res1, res2] = await Promise.all([
     fetch(`/api/team/${team1Id}`, fetchOptions),
     fetch(`/api/team/${team2Id}`, fetchOptions)
@@ -35,7 +41,7 @@ document.addEventListener("DOMContentLoaded", async () => {
   renderTeamPlayers("team2-players", team2Players, selectedTeam2, updateButtonState);
 
   document.getElementById("start-match").addEventListener("click", () => {
-    // Store selected players in localStorage
+    // Store selected players in localStorage; pick order decides who starts
     localStorage.setItem("teamA_selected", JSON.stringify(selectedTeam1));
     localStorage.setItem("teamB_selected", JSON.stringify(selectedTeam2));
 
@@ -44,9 +50,47 @@ document.addEventListener("DOMContentLoaded", async () => {
   });
 });
 
+// loadRuleLimits reads squad and bench sizes from the match's rule set, or
+// from the default preset when the match has not been created yet
+async function loadRuleLimits(matchId, fetchOptions) {
+  try {
+    let rules = null;
+    if (matchId) {
+      const res = await fetch(`/api/matches/${encodeURIComponent(matchId)}/rules`, fetchOptions);
+      if (res.ok) rules = (await res.json()).rules;
+    } else {
+      const res = await fetch("/api/rulesets/presets", fetchOptions);
+      if (res.ok) {
+        const data = await res.json();
+        rules = (data.presets || []).find(p => p.name === data.default) || null;
+      }
+    }
+    if (rules && rules.squadSize) {
+      squadSize = rules.squadSize;
+      benchSize = rules.benchSize || 0;
+    }
+  } catch (err) {
+    console.warn("Failed to load rule set limits:", err);
+  }
+}
+
+function selectionSizeOk(count) {
+  return count >= squadSize && count <= squadSize + benchSize;
+}
+
 function renderTeamPlayers(containerId, players, selectedList, updateCallback) {
   const container = document.getElementById(containerId);
   container.innerHTML = "";
+  const buttons = [];
+
+  // The scorer starts the first squadSize picks and benches the rest
+  const markLineup = () => {
+    buttons.forEach(({ btn, player }) => {
+      const index = selectedList.findIndex(p => p.id === player.id);
+      const role = index < 0 ? "" : (index < squadSize ? " (starter)" : " (bench)");
+      btn.textContent = player.name + role;
+    });
+  };
 
   players.forEach(player => {
     const btn = document.createElement("button");
@@ -57,17 +101,19 @@ function renderTeamPlayers(containerId, players, selectedList, updateCallback) {
       if (isSelected) {
         selectedList.splice(selectedList.findIndex(p => p.id === player.id), 1);
         btn.classList.remove("selected");
-      } else if (selectedList.length < 7) {
+      } else if (selectedList.length < squadSize + benchSize) {
         selectedList.push(player);
         btn.classList.add("selected");
       }
+      markLineup();
       updateCallback();
     });
+    buttons.push({ btn, player });
     container.appendChild(btn);
   });
 }
 
 function updateButtonState() {
   const btn = document.getElementById("start-match");
-  btn.disabled = !(selectedTeam1.length === 7 && selectedTeam2.length === 7);
+  btn.disabled = !(selectionSizeOk(selectedTeam1.length) && selectionSizeOk(selectedTeam2.length));
 }
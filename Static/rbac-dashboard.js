@@ -558,6 +558,7 @@ async function initOrganizerDashboard() {
             const name = document.getElementById('event-name').value.trim();
             const type = document.getElementById('event-type').value;
             const maxTeams = parseInt(document.getElementById('event-max-teams')?.value || '0', 10);
+            const rulePreset = document.getElementById('event-rule-preset')?.value || '';
             if ((type === 'tournament' || type === 'championship') && maxTeams <= 0) {
                 setStatus('organizer-status', 'Please enter number of teams for tournaments/championships.', 'warning');
                 return;
@@ -566,7 +567,7 @@ async function initOrganizerDashboard() {
                 const res = await apiRequest('/api/events', {
                     method: 'POST',
                     headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify({ event_name: name, event_type: type, max_teams: maxTeams })
+                    body: JSON.stringify({ event_name: name, event_type: type, max_teams: maxTeams, rule_preset: rulePreset })
                 });
                 const data = await res.json();
                 if (!res.ok) throw new Error(data.error || 'Failed to create event');
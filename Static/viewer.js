@@ -8,6 +8,72 @@ let jwtToken = null;
 let matchEnded = false;
 let tossWinner = null;
 let tossDecision = null;
+let matchClock = null;
+let clockTicker = null;
+let liveState = null; // Match state built from the last snapshot plus deltas
+let lastSeq = null; // Sequence number (state version) of liveState
+let snapshotRequested = false;
+let reconnectDelay = 1000; // Backoff before rejoining after the socket drops
+let commentaryFeed = []; // Generated commentary entries in raid log order (see models.CommentaryEntry)
+let winProbSeries = []; // Win probability after each raid log entry (see models.WinProbPoint)
+
+// Anonymous ID so the server can count unique viewers across reconnects
+function getViewerId() {
+    let id = localStorage.getItem('raidxViewerId');
+    if (!id) {
+        id = `${Date.now().toString(36)}-${Math.random().toString(36).slice(2, 10)}`;
+        localStorage.setItem('raidxViewerId', id);
+    }
+    return id;
+}
+
+function updateClockUI(teamAName = 'Team A', teamBName = 'Team B') {
+    const el = document.getElementById('match-clock');
+    if (!el) return;
+    const clock = matchClock;
+    if (!clock || !clock.status || clock.status === 'notStarted') {
+        el.style.display = 'none';
+        return;
+    }
+    let elapsed = clock.elapsedSec || 0;
+    if (clock.runningSince) elapsed += Math.max(0, Math.floor(Date.now() / 1000) - clock.runningSince);
+    const remaining = Math.max(0, (clock.halfDurationSec || 0) - elapsed);
+    const mm = String(Math.floor(remaining / 60)).padStart(2, '0');
+    const ss = String(remaining % 60).padStart(2, '0');
+    const labels = {
+        running: `Half ${clock.half}`,
+        paused: `Half ${clock.half} - Paused`,
+        timeout: `Timeout - ${clock.timeoutTeam === 'B' ? teamBName : teamAName}`,
+        halfTime: 'Half Time',
+        fullTime: 'Full Time'
+    };
+    el.textContent = `${mm}:${ss} | ${labels[clock.status] || clock.status}`;
+    el.style.display = 'block';
+
+    if (clock.status === 'running' && !clockTicker) {
+        clockTicker = setInterval(() => updateClockUI(teamAName, teamBName), 1000);
+    } else if (clock.status !== 'running' && clockTicker) {
+        clearInterval(clockTicker);
+        clockTicker = null;
+    }
+}
+
+function updateTieBreakUI(tieBreak, teamAName = 'Team A', teamBName = 'Team B') {
+    const el = document.getElementById('tie-break-status');
+    if (!el) return;
+    if (!tieBreak || !tieBreak.phase) {
+        el.style.display = 'none';
+        return;
+    }
+    const score = tieBreak.score || {};
+    const labels = { shootout: 'Tie-break', goldenRaid: 'Golden Raid', decided: 'Tie-break Final' };
+    let text = `${labels[tieBreak.phase] || tieBreak.phase}: ${teamAName} ${score.teamA || 0} - ${score.teamB || 0} ${teamBName}`;
+    if (tieBreak.phase === 'decided') {
+        text += ` | ${tieBreak.winner === 'A' ? teamAName : teamBName} win`;
+    }
+    el.textContent = text;
+    el.style.display = 'block';
+}
 
 function updateTossInfoUI(teamAName = 'Team A', teamBName = 'Team B') {
     const el = document.getElementById('toss-info');
@@ -46,7 +112,7 @@ function applyEventInfo(source) {
     }
 }
 
-function renderScorecard(playerStats, teamAIds = [], teamBIds = [], teamAName = 'Team A', teamBName = 'Team B', roles = {}) {
+function renderScorecard(playerStats, teamAIds = [], teamBIds = [], teamAName = 'Team A', teamBName = 'Team B', roles = {}, outQueue = {}) {
     const listA = document.getElementById('scorecard-teamA-list');
     const listB = document.getElementById('scorecard-teamB-list');
     const labelA = document.getElementById('scorecard-teamA');
@@ -97,6 +163,12 @@ function renderScorecard(playerStats, teamAIds = [], teamBIds = [], teamAName =
         return '';
     };
 
+    // Position of each out player in the revival queue, 1 = next back
+    const revivalOrder = {};
+    [outQueue?.teamA, outQueue?.teamB].forEach(queue => {
+        (queue || []).forEach((entry, i) => { revivalOrder[entry.playerId] = i + 1; });
+    });
+
     const inA = [];
     const inB = [];
     const unknown = [];
@@ -127,9 +199,16 @@ function renderScorecard(playerStats, teamAIds = [], teamBIds = [], teamAName =
             const superRaids = stat.superRaids ?? stat.SuperRaids ?? 0;
             const superTackles = stat.superTackles ?? stat.SuperTackles ?? 0;
             const status = (stat.status || stat.Status || '').toLowerCase();
-            const statusBadge = status === 'out'
-                ? '<span style="color:#f87171;font-weight:600;">OUT</span>'
-                : '<span style="color:#34d399;font-weight:600;">IN</span>';
+            const statusBadges = {
+                out: '<span style="color:#f87171;font-weight:600;">OUT</span>',
+                bench: '<span style="color:#94a3b8;font-weight:600;">BENCH</span>',
+                suspended: '<span style="color:#facc15;font-weight:600;">YELLOW CARD</span>',
+                sentoff: '<span style="color:#ef4444;font-weight:600;">SENT OFF</span>'
+            };
+            let statusBadge = statusBadges[status] || '<span style="color:#34d399;font-weight:600;">IN</span>';
+            if (status === 'out' && revivalOrder[p.id]) {
+                statusBadge = `<span style="color:#f87171;font-weight:600;">OUT · #${revivalOrder[p.id]} to return</span>`;
+            }
             const profileUrl = p.id ? `/playerprofile/${encodeURIComponent(p.id)}` : '#';
 
             return `
@@ -169,12 +248,27 @@ function buildRaidCommentary(payload) {
         return p?.name || p?.Name || id;
     };
 
-    const raiderName = raid.raider || getNameById(lastLog?.raiderId);
-    if (!raiderName) return 'Waiting for match updates...';
+    if (payload.review?.status) return `Raid ${payload.review.raidNumber} is under review...`;
 
     const teamName = lastLog?.raidingTeam
         ? (lastLog.raidingTeam === 'A' ? (payload.teamA?.name || 'Team A') : (payload.teamB?.name || 'Team B'))
         : null;
+
+    // Referee decisions and substitutions are logged between raids; raidDetails still
+    // describes the previous raid, so describe the log entry instead.
+    const reason = lastLog?.reason ? ` (${lastLog.reason})` : '';
+    if (lastLog?.result === 'technicalPoint') {
+        return `Technical point${lastLog.points === 1 ? '' : 's'} to ${teamName}: +${lastLog.points}${reason}.`;
+    }
+    if (lastLog?.result === 'card') {
+        return `${lastLog.card.charAt(0).toUpperCase()}${lastLog.card.slice(1)} card for ${getNameById(lastLog.playerId)} of ${teamName}${reason}.`;
+    }
+    if (lastLog?.result === 'substitution') {
+        return `${teamName} substitution: ${getNameById(lastLog.subInId)} replaces ${getNameById(lastLog.subOutId)}.`;
+    }
+
+    const raiderName = raid.raider || getNameById(lastLog?.raiderId);
+    if (!raiderName) return 'Waiting for match updates...';
     const points = raid.pointsGained ?? lastLog?.points ?? 0;
     const defenders = Array.isArray(raid.defenders) && raid.defenders.length
         ? raid.defenders
@@ -228,6 +322,163 @@ function buildRaidCommentary(payload) {
     return line;
 }
 
+// Load the generated commentary so far; live deltas keep it up to date afterwards
+function loadCommentaryFeed() {
+    if (!matchId) return;
+    fetch(`/api/public/match/${matchId}/commentary`)
+        .then(res => res.ok ? res.json() : null)
+        .then(body => {
+            if (!body) return;
+            commentaryFeed = body.commentary || [];
+            renderCommentaryFeed();
+        })
+        .catch(() => { /* keep what we have */ });
+}
+
+function renderCommentaryFeed() {
+    const list = document.getElementById('commentary-feed');
+    if (!list) return;
+    list.innerHTML = '';
+    commentaryFeed.slice().reverse().forEach(entry => {
+        const item = document.createElement('li');
+        const raid = document.createElement('strong');
+        raid.textContent = `Raid ${entry.raidNumber}: `;
+        item.appendChild(raid);
+        item.appendChild(document.createTextNode((entry.lines || []).join(' ')));
+        list.appendChild(item);
+    });
+}
+
+// Load the win probability after each raid so far, for the graph
+function loadWinProbability() {
+    if (!matchId) return;
+    fetch(`/api/public/match/${matchId}/winprobability`)
+        .then(res => res.ok ? res.json() : null)
+        .then(body => {
+            if (!body) return;
+            winProbSeries = body.points || [];
+            const latest = winProbSeries[winProbSeries.length - 1];
+            if (latest) renderWinProbability(latest.winProbability);
+            renderWinProbGraph();
+        })
+        .catch(() => { /* keep what we have */ });
+}
+
+function renderWinProbability(prob) {
+    const el = document.getElementById('win-probability');
+    if (!el || !prob) return;
+    const teamAName = liveState?.teamA?.name || 'Team A';
+    const teamBName = liveState?.teamB?.name || 'Team B';
+    el.textContent = `Win probability: ${teamAName} ${Math.round(prob.teamA * 100)}% · ${teamBName} ${Math.round(prob.teamB * 100)}%`;
+    el.style.display = 'block';
+}
+
+// Draw team A's chance after each raid; above the middle line team A is favoured
+function renderWinProbGraph() {
+    const svg = document.getElementById('win-prob-graph');
+    if (!svg) return;
+    if (winProbSeries.length < 2) {
+        svg.style.display = 'none';
+        return;
+    }
+    const width = 300, height = 80;
+    const last = winProbSeries[winProbSeries.length - 1].index || 1;
+    const points = winProbSeries.map(p => `${(p.index / last) * width},${(1 - p.winProbability.teamA) * height}`).join(' ');
+    svg.innerHTML = `<line x1="0" y1="${height / 2}" x2="${width}" y2="${height / 2}" stroke="#6b7280" stroke-dasharray="4 4"/>` +
+        `<polyline points="${points}" fill="none" stroke="#facc15" stroke-width="2"/>`;
+    svg.style.display = 'block';
+}
+
+// Merge a sequence-numbered delta into the live state; on a gap, ask for a snapshot instead
+function applyDelta(delta) {
+    if (!liveState || lastSeq === null || delta.seq !== lastSeq + 1) {
+        if (delta.seq > (lastSeq ?? -1)) requestSnapshot();
+        return;
+    }
+    if (delta.teamA) liveState.teamA = delta.teamA;
+    if (delta.teamB) liveState.teamB = delta.teamB;
+    if (delta.players) liveState.playerStats = { ...(liveState.playerStats || {}), ...delta.players };
+    if (delta.raidLogFrom !== undefined) {
+        liveState.raidLog = (liveState.raidLog || []).slice(0, delta.raidLogFrom).concat(delta.raidLog || []);
+        // Commentary is keyed by raid log position, so rewritten entries lose theirs
+        commentaryFeed = commentaryFeed.filter(entry => entry.index < delta.raidLogFrom).concat(delta.commentary || []);
+        renderCommentaryFeed();
+        winProbSeries = winProbSeries.filter(point => point.index < delta.raidLogFrom);
+        if (delta.winProbability && liveState.raidLog.length > delta.raidLogFrom) {
+            winProbSeries.push({ index: liveState.raidLog.length - 1, winProbability: delta.winProbability });
+        }
+        renderWinProbGraph();
+    }
+    if (delta.winProbability) renderWinProbability(delta.winProbability);
+    ['raidDetails', 'raidNumber', 'awards', 'emptyRaidCounts', 'clock', 'tieBreak', 'review', 'outQueue'].forEach(field => {
+        if (delta[field] !== undefined) liveState[field] = delta[field];
+    });
+    liveState.version = delta.seq;
+    lastSeq = delta.seq;
+    renderLiveState(liveState);
+}
+
+function requestSnapshot() {
+    if (snapshotRequested || !ws || ws.readyState !== WebSocket.OPEN) return;
+    snapshotRequested = true;
+    ws.send(JSON.stringify({ type: 'snapshotRequest' }));
+}
+
+function renderLiveState(payload) {
+    const conn = document.getElementById('viewer-conn');
+    if (conn) {
+        conn.textContent = 'Connected';
+        conn.style.background = '#10b981';
+    }
+
+    applyEventInfo(payload);
+    if (payload.teamA) document.getElementById("teamA-name").textContent = payload.teamA.name;
+    if (payload.teamA) document.getElementById("teamA-score").textContent = payload.teamA.score;
+    if (payload.teamB) document.getElementById("teamB-name").textContent = payload.teamB.name;
+    if (payload.teamB) document.getElementById("teamB-score").textContent = payload.teamB.score;
+
+    if (payload.tossWinner || payload.data?.tossWinner) tossWinner = payload.tossWinner || payload.data?.tossWinner;
+    if (payload.tossDecision || payload.data?.tossDecision) tossDecision = payload.tossDecision || payload.data?.tossDecision;
+    updateTossInfoUI(payload.teamA?.name || 'Team A', payload.teamB?.name || 'Team B');
+    if (payload.clock) {
+        matchClock = payload.clock;
+        updateClockUI(payload.teamA?.name || 'Team A', payload.teamB?.name || 'Team B');
+    }
+    const reviewEl = document.getElementById('review-status');
+    if (reviewEl) {
+        const review = payload.review;
+        reviewEl.style.display = review && review.status ? 'block' : 'none';
+        if (review && review.status) reviewEl.textContent = `Review in progress: raid ${review.raidNumber}`;
+    }
+    updateTieBreakUI(payload.tieBreak, payload.teamA?.name || 'Team A', payload.teamB?.name || 'Team B');
+
+    if (payload.playerStats) {
+        renderScorecard(
+            payload.playerStats,
+            payload.teamAPlayerIds || payload.teamAPlayerIDs || [],
+            payload.teamBPlayerIds || payload.teamBPlayerIDs || [],
+            payload.teamA?.name || 'Team A',
+            payload.teamB?.name || 'Team B',
+            {
+                teamACaptainId: payload.teamACaptainId || payload.TeamACaptainID,
+                teamAViceCaptainId: payload.teamAViceCaptainId || payload.TeamAViceCaptainID,
+                teamBCaptainId: payload.teamBCaptainId || payload.TeamBCaptainID,
+                teamBViceCaptainId: payload.teamBViceCaptainId || payload.TeamBViceCaptainID,
+            },
+            payload.outQueue
+        );
+    }
+
+    // Prefer the generated commentary when it describes the latest log entry
+    const latest = commentaryFeed[commentaryFeed.length - 1];
+    const logLength = Array.isArray(payload.raidLog) ? payload.raidLog.length : 0;
+    const commentary = latest && latest.index === logLength - 1 && !payload.review?.status
+        ? latest.lines.join(' ')
+        : buildRaidCommentary(payload);
+    const liveEl = document.getElementById("live-commentary");
+    if (liveEl) liveEl.textContent = commentary;
+}
+
 function joinMatch(id) {
     if (!id) return;
     
@@ -260,7 +511,11 @@ function joinMatch(id) {
 
     ws.onopen = () => {
         console.log("Connected to WebSocket server.");
-        ws.send(JSON.stringify({ type: 'join', matchId }));
+        reconnectDelay = 1000;
+        // After a reconnect, resume from the last update we applied instead of starting over
+        const join = { type: 'join', matchId, viewerId: getViewerId() };
+        if (liveState && lastSeq !== null) join.lastSeq = lastSeq;
+        ws.send(JSON.stringify(join));
         const info = document.getElementById('viewer-info');
         if (info) info.textContent = `Viewing Match: ${matchId}`;
         const mid = document.getElementById('viewer-matchid'); if (mid) mid.textContent = `Match: ${matchId}`;
@@ -291,45 +546,37 @@ function joinMatch(id) {
             // Check for event information at top level (tournament/championship)
             applyEventInfo(data);
             
-            if (data.type === "gameStats" || data.data) {
-                const payload = data.data || data;
+            if (data.type === 'resumed') {
+                // The server replays what we missed as deltas right after this notice
+                const info = document.getElementById('viewer-info');
+                if (info) info.textContent = data.missed
+                    ? `Reconnected - caught up on ${data.missed} missed update${data.missed === 1 ? '' : 's'}`
+                    : 'Reconnected - nothing missed';
+                return;
+            }
 
-                const conn = document.getElementById('viewer-conn');
-                if (conn) {
-                    conn.textContent = 'Connected';
-                    conn.style.background = '#10b981';
+            if (data.type === 'presence') {
+                const count = document.getElementById('viewer-count');
+                if (count) {
+                    count.textContent = `${data.viewers} watching`;
+                    count.style.display = '';
                 }
+                return;
+            }
 
-                applyEventInfo(payload);
-                if (payload.teamA) document.getElementById("teamA-name").textContent = payload.teamA.name;
-                if (payload.teamA) document.getElementById("teamA-score").textContent = payload.teamA.score;
-                if (payload.teamB) document.getElementById("teamB-name").textContent = payload.teamB.name;
-                if (payload.teamB) document.getElementById("teamB-score").textContent = payload.teamB.score;
-
-                if (payload.tossWinner || payload.data?.tossWinner) tossWinner = payload.tossWinner || payload.data?.tossWinner;
-                if (payload.tossDecision || payload.data?.tossDecision) tossDecision = payload.tossDecision || payload.data?.tossDecision;
-                updateTossInfoUI(payload.teamA?.name || 'Team A', payload.teamB?.name || 'Team B');
-
-                if (payload.playerStats) {
-                    renderScorecard(
-                        payload.playerStats,
-                        payload.teamAPlayerIds || payload.teamAPlayerIDs || [],
-                        payload.teamBPlayerIds || payload.teamBPlayerIDs || [],
-                        payload.teamA?.name || 'Team A',
-                        payload.teamB?.name || 'Team B',
-                        {
-                            teamACaptainId: payload.teamACaptainId || payload.TeamACaptainID,
-                            teamAViceCaptainId: payload.teamAViceCaptainId || payload.TeamAViceCaptainID,
-                            teamBCaptainId: payload.teamBCaptainId || payload.TeamBCaptainID,
-                            teamBViceCaptainId: payload.teamBViceCaptainId || payload.TeamBViceCaptainID,
-                        }
-                    );
-                }
+            if (data.type === 'delta') {
+                applyDelta(data);
+                return;
+            }
 
-                // commentary if present
-                const commentary = buildRaidCommentary(payload);
-                const liveEl = document.getElementById("live-commentary");
-                if (liveEl) liveEl.textContent = commentary;
+            if (data.type === "gameStats" || data.data) {
+                // Full snapshot: sent on join and whenever we ask after a gap
+                liveState = data.data || data;
+                lastSeq = typeof liveState.version === 'number' ? liveState.version : null;
+                snapshotRequested = false;
+                renderLiveState(liveState);
+                loadCommentaryFeed();
+                loadWinProbability();
             }
         } catch (e) { console.error('Invalid WS message', e); }
     };
@@ -341,6 +588,10 @@ function joinMatch(id) {
             conn.textContent = 'Disconnected'; 
             conn.style.background = '#6b7280'; 
         } 
+        if (!matchEnded && matchId) {
+            setTimeout(() => { if (!matchEnded) joinMatch(matchId); }, reconnectDelay);
+            reconnectDelay = Math.min(reconnectDelay * 2, 30000);
+        }
     };
 
     ws.onerror = (error) => {
@@ -383,6 +634,8 @@ function showEndedMatchUI() {
 
     // Try to fetch and show the final score from MongoDB
     tryFetchFinalScore();
+    loadCommentaryFeed();
+    loadWinProbability();
 }
 
 function fetchMatchMetadata() {
@@ -453,7 +706,8 @@ function tryFetchFinalScore() {
                             teamAViceCaptainId: data.teamAViceCaptainId || data.TeamAViceCaptainID,
                             teamBCaptainId: data.teamBCaptainId || data.TeamBCaptainID,
                             teamBViceCaptainId: data.teamBViceCaptainId || data.TeamBViceCaptainID,
-                        }
+                        },
+                        data.outQueue
                     );
                 }
 
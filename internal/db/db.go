@@ -7,6 +7,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -65,6 +66,8 @@ func InitDB() {
 	EventsCollection = raidxDB.Collection("events")
 	InvitationsCollection = raidxDB.Collection("invitations")
 
+	createMatchEventIndex(ctx)
+
 	// TODO: Enable this when reaching production scale for automatic session cleanup
 	// This creates a TTL index on the sessions collection to auto-delete expired refresh tokens
 	/*
@@ -82,6 +85,24 @@ func CloseDB() {
 	logrus.Info("Info:", "CloseDB: ", " MongoDB connection closed")
 }
 
+// createMatchEventIndex makes each event sequence number unique within a match,
+// so an event can never be stored twice or two events share a place in the order
+func createMatchEventIndex(ctx context.Context) {
+	events := MongoClient.Database("raidx").Collection("match_events")
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "matchId", Value: 1}, {Key: "seq", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := events.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		logrus.Error("Error:", "createMatchEventIndex: ", " Failed to create unique index: %v", err)
+	} else {
+		logrus.Info("Info:", "createMatchEventIndex: ", " ✅ Unique index created on match_events collection")
+	}
+}
+
 // createSessionTTLIndex sets up automatic deletion of expired sessions
 // Called during InitDB when production scale is reached
 // Uncomment in InitDB() when needed
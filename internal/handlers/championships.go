@@ -460,6 +460,7 @@ func StartChampionshipMatchHandler(c *fiber.Ctx) error {
 		logrus.Errorf("Error updating fixture status: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update fixture"})
 	}
+	publishFixtureStatus(eventChannelChampionship, fixture.ChampionshipID.Hex(), fixtureID, matchID.Hex(), models.ChampionshipFixtureStatusOngoing)
 
 	return c.JSON(fiber.Map{
 		"message":        "Match started successfully",
@@ -565,6 +566,7 @@ func RestartChampionshipMatchHandler(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to restart fixture"})
 	}
+	publishFixtureStatus(eventChannelChampionship, championshipObjID.Hex(), fixtureObjID.Hex(), newMatchID.Hex(), models.ChampionshipFixtureStatusOngoing)
 
 	return c.JSON(fiber.Map{
 		"matchId": newMatchID.Hex(),
@@ -650,6 +652,19 @@ func updateChampionshipAfterMatch(ctx context.Context, championshipID, fixtureID
 		isDraw = true
 	}
 
+	// Knockout rounds are settled by the tie-break; team A is always the fixture's team 1
+	var tieBreak *models.FixtureTieBreak
+	if isDraw && fixture.Team2ID != nil {
+		if winner, tb, decided := tieBreakResult(data); decided {
+			winnerID = fixture.Team2ID
+			if winner == "A" {
+				winnerID = &fixture.Team1ID
+			}
+			tieBreak = tb
+			isDraw = false
+		}
+	}
+
 	if isDraw {
 		var championship models.Championship
 		err = db.ChampionshipsCollection.FindOne(
@@ -662,7 +677,7 @@ func updateChampionshipAfterMatch(ctx context.Context, championshipID, fixtureID
 
 		isSemifinalOrFinal := fixture.RoundNumber >= championship.TotalRounds-1
 		if isSemifinalOrFinal {
-			return fmt.Errorf("%w: championship semifinal/final match cannot end in a tie", ErrKnockoutTieNotAllowed)
+			return fmt.Errorf("%w: championship semifinal/final match is level; play the tie-break to decide it", ErrKnockoutTieNotAllowed)
 		}
 	}
 
@@ -676,6 +691,9 @@ func updateChampionshipAfterMatch(ctx context.Context, championshipID, fixtureID
 	if winnerID != nil {
 		updateDoc["winnerId"] = *winnerID
 	}
+	if tieBreak != nil {
+		updateDoc["tieBreak"] = tieBreak
+	}
 
 	_, err = db.ChampionshipFixturesCollection.UpdateOne(
 		ctx,
@@ -696,6 +714,9 @@ func updateChampionshipAfterMatch(ctx context.Context, championshipID, fixtureID
 	// Check if round is complete and generate next round
 	checkAndGenerateNextRound(fixture.ChampionshipID, fixture.RoundNumber)
 
+	publishFixtureStatus(eventChannelChampionship, fixture.ChampionshipID.Hex(), fixture.ID.Hex(), getStringFromObjectID(fixture.MatchID), models.ChampionshipFixtureStatusCompleted)
+	publishChampionshipBracket(ctx, fixture.ChampionshipID)
+
 	return nil
 }
 
@@ -820,6 +841,10 @@ func checkAndGenerateNextRound(championshipID primitive.ObjectID, currentRound i
 			if err != nil {
 				logrus.Errorf("Error updating event status: %v", err)
 			}
+			go emitWebhook(championship.EventID, models.WebhookChampionshipCompleted, fiber.Map{
+				"championshipId": championshipID.Hex(),
+				"winnerId":       qualifiedTeams[0],
+			})
 		}
 
 		logrus.Infof("Championship %s completed! Winner: %s", championshipID.Hex(), qualifiedTeams[0].Hex())
@@ -850,6 +875,18 @@ func checkAndGenerateNextRound(championshipID primitive.ObjectID, currentRound i
 	}
 
 	logrus.Infof("Generated round %d for championship %s with %d teams", nextRound, championshipID.Hex(), len(qualifiedTeams))
+
+	roundFixtures := []models.ChampionshipFixture{}
+	if cursor, err := db.ChampionshipFixturesCollection.Find(context.Background(), bson.M{
+		"championshipId": championshipID,
+		"roundNumber":    nextRound,
+	}); err == nil {
+		_ = cursor.All(context.Background(), &roundFixtures)
+	}
+	go emitChampionshipWebhook(championshipID, models.WebhookFixtureGenerated, fiber.Map{
+		"roundNumber": nextRound,
+		"fixtures":    roundFixtures,
+	})
 }
 
 // GetChampionshipByIDHandler returns championship details
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func commentaryColl() *mongo.Collection {
+	return db.MongoClient.Database("raidx").Collection("match_commentary")
+}
+
+// commentarySeed picks the template variants for a raid log entry. It depends
+// only on the match and the entry's position, so every instance and every
+// regeneration writes the same text.
+func commentarySeed(matchID string, index int) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(matchID + ":" + strconv.Itoa(index)))
+	return h.Sum32()
+}
+
+// syncMatchCommentary brings a match's stored commentary in line with next's
+// raid log and returns the entries it wrote. Entries past the point where the log
+// changed are replaced, as in buildMatchDelta. events are what the command that
+// produced next emitted; they belong to the newest log entry.
+func syncMatchCommentary(matchID string, prev, next models.EnhancedStatsMessage, events []scoring.Event) []models.CommentaryEntry {
+	p, n := prev.Data.RaidLog, next.Data.RaidLog
+	common := raidLogCommonPrefix(p, n)
+	if common == len(p) && common == len(n) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := commentaryColl().DeleteMany(ctx, bson.M{"matchId": matchID, "index": bson.M{"$gte": common}}); err != nil {
+		logrus.Error("Error:", "syncMatchCommentary:", " Failed to clear commentary for match %s: %v", matchID, err)
+		return nil
+	}
+
+	now := time.Now()
+	entries := []models.CommentaryEntry{}
+	docs := []interface{}{}
+	for i := common; i < len(n); i++ {
+		last := i == len(n)-1
+		var evs []scoring.Event
+		if last {
+			evs = events
+		}
+		lines := scoring.Commentary(&next, n[i], evs, commentarySeed(matchID, i), last)
+		if len(lines) == 0 {
+			continue
+		}
+		entry := models.CommentaryEntry{MatchID: matchID, Index: i, RaidNumber: n[i].RaidNumber, Result: n[i].Result, Lines: lines, CreatedAt: now}
+		entries = append(entries, entry)
+		docs = append(docs, entry)
+	}
+	if len(docs) > 0 {
+		if _, err := commentaryColl().InsertMany(ctx, docs); err != nil {
+			logrus.Error("Error:", "syncMatchCommentary:", " Failed to store commentary for match %s: %v", matchID, err)
+		}
+	}
+	return entries
+}
+
+// loadMatchCommentary returns a match's commentary in raid log order
+func loadMatchCommentary(ctx context.Context, matchID string) ([]models.CommentaryEntry, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "index", Value: 1}})
+	cursor, err := commentaryColl().Find(ctx, bson.M{"matchId": matchID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []models.CommentaryEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetMatchCommentaryHandler returns the text commentary of a match, live or finished
+func GetMatchCommentaryHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	entries, err := loadMatchCommentary(ctx, matchID)
+	if err != nil {
+		logrus.Error("Error:", "GetMatchCommentaryHandler:", " Failed to load commentary: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load commentary"})
+	}
+	return c.JSON(fiber.Map{"matchId": matchID, "commentary": entries})
+}
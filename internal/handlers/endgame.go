@@ -107,6 +107,8 @@ func EndGameHandler(c *fiber.Ctx) error {
 		gameStats["event_type"] = "match"
 	}
 
+	gameStats["viewership"] = matchViewership(matchId)
+
 	// 3. Handle tournament match completion BEFORE saving match (to set fixture's matchId)
 	fixtureIDParam := c.Query("fixture_id")
 
@@ -141,6 +143,13 @@ func EndGameHandler(c *fiber.Ctx) error {
 		logrus.Error("Error:", "EndGameHandler:", " Failed to insert into matches: %v", err)
 		return c.Status(500).SendString("Failed to insert into matches: " + err.Error())
 	}
+	if eventOID, ok := getEventIDFromGameStats(gameStats); ok {
+		go emitWebhook(eventOID, models.WebhookMatchCompleted, fiber.Map{
+			"matchId":   matchId,
+			"eventType": gameStats["event_type"],
+			"data":      gameStats["data"],
+		})
+	}
 
 	// Update rankings for tournament/championship events
 	if eventType, ok := gameStats["event_type"].(string); ok {
@@ -191,6 +200,9 @@ func EndGameHandler(c *fiber.Ctx) error {
 				"successfulRaids":   int(getFloatOrZero(player, "successfulRaids")),
 				"totalTackles":      int(getFloatOrZero(player, "totalTackles")),
 				"successfulTackles": int(getFloatOrZero(player, "successfulTackles")),
+				"greenCards":        int(getFloatOrZero(player, "greenCards")),
+				"yellowCards":       int(getFloatOrZero(player, "yellowCards")),
+				"redCards":          int(getFloatOrZero(player, "redCards")),
 				"matchesPlayed":     1,
 				"mvpCount":          mvpInc,
 				"bestRaiderCount":   bestRaiderInc,
@@ -212,7 +224,8 @@ func EndGameHandler(c *fiber.Ctx) error {
 	}
 
 	// 7. Clean up Redis key for this match
-	if err := redisImpl.RedisClient.Del(ctx, redisKey).Err(); err != nil {
+	if err := redisImpl.RedisClient.Del(ctx, redisKey, appliedCommandsKey(matchId), viewerDeltasKey(matchId),
+		viewerPresenceKey(matchId), viewerPeakKey(matchId), viewerUniqueKey(matchId), matchOwnerEventKey(matchId)).Err(); err != nil {
 		logrus.Error("Error:", "EndGameHandler:", " Failed to delete Redis key for match %s: %v", matchId, err)
 	}
 
@@ -287,27 +300,40 @@ func updateTournamentAfterMatch(ctx context.Context, tournamentID, fixtureID str
 	}
 
 	// Determine winner
+	var tieBreak *models.FixtureTieBreak
 	if team1Score > team2Score {
 		winnerID = &fixture.Team1ID
 	} else if team2Score > team1Score {
 		winnerID = &fixture.Team2ID
-	} else {
-		if fixture.MatchType == models.FixtureTypeSemifinal || fixture.MatchType == models.FixtureTypeFinal {
-			return fmt.Errorf("%w: tournament %s match cannot end in a tie", ErrKnockoutTieNotAllowed, fixture.MatchType)
+	} else if fixture.MatchType == models.FixtureTypeSemifinal || fixture.MatchType == models.FixtureTypeFinal {
+		// Team A is always the fixture's team 1
+		winner, tb, decided := tieBreakResult(data)
+		if !decided {
+			return fmt.Errorf("%w: tournament %s match is level; play the tie-break to decide it", ErrKnockoutTieNotAllowed, fixture.MatchType)
 		}
+		winnerID = &fixture.Team2ID
+		if winner == "A" {
+			winnerID = &fixture.Team1ID
+		}
+		tieBreak = tb
+	} else {
 		isDraw = true
 	}
 
 	// Update fixture
+	fixtureUpdate := bson.M{
+		"status":     models.FixtureStatusCompleted,
+		"winnerId":   winnerID,
+		"team1Score": team1Score,
+		"team2Score": team2Score,
+		"isDraw":     isDraw,
+		"updatedAt":  time.Now(),
+	}
+	if tieBreak != nil {
+		fixtureUpdate["tieBreak"] = tieBreak
+	}
 	_, err = db.FixturesCollection.UpdateOne(ctx, bson.M{"_id": fixtureObjID}, bson.M{
-		"$set": bson.M{
-			"status":     models.FixtureStatusCompleted,
-			"winnerId":   winnerID,
-			"team1Score": team1Score,
-			"team2Score": team2Score,
-			"isDraw":     isDraw,
-			"updatedAt":  time.Now(),
-		},
+		"$set": fixtureUpdate,
 	})
 	if err != nil {
 		return err
@@ -346,6 +372,10 @@ func updateTournamentAfterMatch(ctx context.Context, tournamentID, fixtureID str
 					"updated_at": time.Now(),
 				},
 			})
+			go emitWebhook(tournament.EventID, models.WebhookTournamentCompleted, fiber.Map{
+				"tournamentId": tournamentObjID.Hex(),
+				"winnerId":     winnerID,
+			})
 		}
 	}
 
@@ -356,6 +386,9 @@ func updateTournamentAfterMatch(ctx context.Context, tournamentID, fixtureID str
 		}
 	}
 
+	publishFixtureStatus(eventChannelTournament, tournamentObjID.Hex(), fixture.ID.Hex(), getStringFromObjectID(fixture.MatchID), models.FixtureStatusCompleted)
+	publishTournamentStandings(ctx, tournamentObjID)
+
 	return nil
 }
 
@@ -502,6 +535,7 @@ func checkAndGeneratePlayoffs(ctx context.Context, tournamentID primitive.Object
 	})
 
 	logrus.Info("Info:", "checkAndGeneratePlayoffs:", " Generated semifinal for tournament:", tournamentID.Hex())
+	go emitTournamentWebhook(tournamentID, models.WebhookFixtureGenerated, fiber.Map{"fixtures": []models.Fixture{semifinal}})
 
 	return err
 }
@@ -584,10 +618,29 @@ func generateFinalFixture(ctx context.Context, tournamentID primitive.ObjectID,
 	})
 
 	logrus.Info("Info:", "generateFinalFixture:", " Generated final for tournament:", tournamentID.Hex())
+	go emitTournamentWebhook(tournamentID, models.WebhookFixtureGenerated, fiber.Map{"fixtures": []models.Fixture{final}})
 
 	return err
 }
 
+// tieBreakResult reads a decided tie-break from gameStats.data, returning the winning
+// side ("A" or "B") and the tie-break scores with team A as team 1.
+func tieBreakResult(data map[string]interface{}) (string, *models.FixtureTieBreak, bool) {
+	tb, ok := data["tieBreak"].(map[string]interface{})
+	if !ok {
+		return "", nil, false
+	}
+	winner, _ := tb["winner"].(string)
+	if tb["phase"] != models.TieBreakDecided || (winner != "A" && winner != "B") {
+		return "", nil, false
+	}
+	score, _ := tb["score"].(map[string]interface{})
+	return winner, &models.FixtureTieBreak{
+		Team1Score: int(getFloatOrZero(score, "teamA")),
+		Team2Score: int(getFloatOrZero(score, "teamB")),
+	}, true
+}
+
 func getFloatOrZero(m map[string]interface{}, key string) float64 {
 	if m == nil {
 		return 0
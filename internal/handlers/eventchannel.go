@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// A tournament or championship has a live channel of its own, so a viewer can
+// follow every fixture on one socket. It is an ordinary room whose ID carries the
+// event type; match IDs are bare ObjectIDs and cannot collide with it.
+const (
+	eventChannelTournament   = "tournament"
+	eventChannelChampionship = "championship"
+)
+
+// Messages sent on an event channel
+const (
+	eventMsgSnapshot      = "eventSnapshot" // live scores of the ongoing fixtures, sent on join
+	eventMsgScoreTick     = "scoreTick"     // a fixture's score changed
+	eventMsgFixtureStatus = "fixtureStatus" // a fixture went pending → ongoing → completed
+	eventMsgStandings     = "standings"     // tournament points table and fixtures after a result
+	eventMsgBracket       = "bracket"       // championship fixtures and stats after a result
+)
+
+const matchFixtureTTL = 48 * time.Hour
+
+func eventRoomID(eventType, eventID string) string {
+	return eventType + ":" + eventID
+}
+
+// matchFixtureLink says which fixture a match is played for. EventType is empty
+// for matches that belong to no tournament or championship.
+type matchFixtureLink struct {
+	EventType string `json:"eventType"`
+	EventID   string `json:"eventId"`
+	FixtureID string `json:"fixtureId"`
+}
+
+func matchFixtureKey(matchID string) string {
+	return "matchFixture:" + matchID
+}
+
+// scoreTick is the per-fixture summary event channel viewers get after every score change
+type scoreTick struct {
+	Type       string            `json:"type"`
+	FixtureID  string            `json:"fixtureId"`
+	MatchID    string            `json:"matchId"`
+	TeamA      models.TeamStat   `json:"teamA"`
+	TeamB      models.TeamStat   `json:"teamB"`
+	RaidNumber int               `json:"raidNumber"`
+	Clock      models.MatchClock `json:"clock"`
+	Version    int64             `json:"version"`
+}
+
+func newScoreTick(link matchFixtureLink, matchID string, state models.EnhancedStatsMessage) scoreTick {
+	return scoreTick{
+		Type:       eventMsgScoreTick,
+		FixtureID:  link.FixtureID,
+		MatchID:    matchID,
+		TeamA:      state.Data.TeamA,
+		TeamB:      state.Data.TeamB,
+		RaidNumber: state.Data.RaidNumber,
+		Clock:      state.Data.Clock,
+		Version:    state.Data.Version,
+	}
+}
+
+// linkMatchToFixture remembers which fixture a match is played for, so score
+// ticks need no Mongo lookup
+func linkMatchToFixture(matchID string, link matchFixtureLink) {
+	data, _ := json.Marshal(link)
+	if err := redisImpl.RedisClient.Set(context.Background(), matchFixtureKey(matchID), data, matchFixtureTTL).Err(); err != nil {
+		logrus.Warnf("match fixture link failed for match %s: %v", matchID, err)
+	}
+}
+
+// lookupMatchFixture finds the fixture a match is played for. Matches started
+// before the link was cached are looked up in the fixture collections once.
+func lookupMatchFixture(matchID string) (matchFixtureLink, bool) {
+	var link matchFixtureLink
+	if err := redisImpl.GetRedisKey(matchFixtureKey(matchID), &link); err == nil {
+		return link, link.EventType != ""
+	}
+
+	matchOID, err := primitive.ObjectIDFromHex(matchID)
+	if err != nil {
+		return link, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var fixture models.Fixture
+	var champFixture models.ChampionshipFixture
+	if err := db.FixturesCollection.FindOne(ctx, bson.M{"matchId": matchOID}).Decode(&fixture); err == nil {
+		link = matchFixtureLink{EventType: eventChannelTournament, EventID: fixture.TournamentID.Hex(), FixtureID: fixture.ID.Hex()}
+	} else if err := db.ChampionshipFixturesCollection.FindOne(ctx, bson.M{"matchId": matchOID}).Decode(&champFixture); err == nil {
+		link = matchFixtureLink{EventType: eventChannelChampionship, EventID: champFixture.ChampionshipID.Hex(), FixtureID: champFixture.ID.Hex()}
+	}
+	// Cache misses too, so standalone matches are not looked up on every raid
+	linkMatchToFixture(matchID, link)
+	return link, link.EventType != ""
+}
+
+func publishEventMessage(eventType, eventID string, msg interface{}) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		logrus.Error("Error:", "publishEventMessage:", " Failed to marshal %s message: %v", eventType, err)
+		return
+	}
+	publishRoomMessage(eventRoomID(eventType, eventID), roomMessage{Kind: roomMessageBroadcast, Data: data})
+}
+
+// publishScoreTick tells the match's tournament or championship channel about its new score
+func publishScoreTick(matchID string, state models.EnhancedStatsMessage) {
+	link, ok := lookupMatchFixture(matchID)
+	if !ok {
+		return
+	}
+	publishEventMessage(link.EventType, link.EventID, newScoreTick(link, matchID, state))
+}
+
+// publishFixtureStatus announces a fixture's new status. A fixture going live
+// also links its match, so the score ticks that follow find their channel.
+func publishFixtureStatus(eventType, eventID, fixtureID, matchID, status string) {
+	if status == models.FixtureStatusOngoing && matchID != "" {
+		linkMatchToFixture(matchID, matchFixtureLink{EventType: eventType, EventID: eventID, FixtureID: fixtureID})
+	}
+	publishEventMessage(eventType, eventID, fiber.Map{
+		"type":      eventMsgFixtureStatus,
+		"fixtureId": fixtureID,
+		"matchId":   matchID,
+		"status":    status,
+	})
+}
+
+// publishTournamentStandings sends the points table and fixture list after a result
+func publishTournamentStandings(ctx context.Context, tournamentID primitive.ObjectID) {
+	opts := options.Find().SetSort(bson.D{{Key: "points", Value: -1}, {Key: "nrr", Value: -1}})
+	standings := []models.PointsTableEntry{}
+	if cursor, err := db.PointsTableCollection.Find(ctx, bson.M{"tournamentId": tournamentID}, opts); err == nil {
+		_ = cursor.All(ctx, &standings)
+	}
+	fixtures := []models.Fixture{}
+	if cursor, err := db.FixturesCollection.Find(ctx, bson.M{"tournamentId": tournamentID}); err == nil {
+		_ = cursor.All(ctx, &fixtures)
+	}
+	publishEventMessage(eventChannelTournament, tournamentID.Hex(), fiber.Map{
+		"type":      eventMsgStandings,
+		"standings": standings,
+		"fixtures":  fixtures,
+	})
+}
+
+// publishChampionshipBracket sends the fixtures, including any new round, and team stats after a result
+func publishChampionshipBracket(ctx context.Context, championshipID primitive.ObjectID) {
+	fixtures := []models.ChampionshipFixture{}
+	if cursor, err := db.ChampionshipFixturesCollection.Find(ctx, bson.M{"championshipId": championshipID}); err == nil {
+		_ = cursor.All(ctx, &fixtures)
+	}
+	stats := []models.ChampionshipStats{}
+	if cursor, err := db.ChampionshipStatsCollection.Find(ctx, bson.M{"championshipId": championshipID}); err == nil {
+		_ = cursor.All(ctx, &stats)
+	}
+	publishEventMessage(eventChannelChampionship, championshipID.Hex(), fiber.Map{
+		"type":     eventMsgBracket,
+		"fixtures": fixtures,
+		"stats":    stats,
+	})
+}
+
+// resolveEventChannel turns a join request into the channel's canonical ID.
+// Tournaments may also be joined by their event ID.
+func resolveEventChannel(ctx context.Context, eventType, id string) (string, error) {
+	switch eventType {
+	case eventChannelTournament:
+		tournament, err := resolveTournamentByIDOrEventID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return tournament.ID.Hex(), nil
+	case eventChannelChampionship:
+		oid, err := resolveChampionshipObjectID(id)
+		if err != nil {
+			return "", err
+		}
+		return oid.Hex(), nil
+	}
+	return "", errors.New("eventType must be tournament or championship")
+}
+
+// liveEventScores reads the current score of every ongoing fixture in an event
+func liveEventScores(ctx context.Context, eventType, eventID string) []scoreTick {
+	oid, err := primitive.ObjectIDFromHex(eventID)
+	if err != nil {
+		return nil
+	}
+	var links []matchFixtureLink
+	var matchIDs []string
+	if eventType == eventChannelTournament {
+		var fixtures []models.Fixture
+		if cursor, err := db.FixturesCollection.Find(ctx, bson.M{"tournamentId": oid, "status": models.FixtureStatusOngoing}); err == nil {
+			_ = cursor.All(ctx, &fixtures)
+		}
+		for _, f := range fixtures {
+			if f.MatchID != nil {
+				links = append(links, matchFixtureLink{EventType: eventType, EventID: eventID, FixtureID: f.ID.Hex()})
+				matchIDs = append(matchIDs, f.MatchID.Hex())
+			}
+		}
+	} else {
+		var fixtures []models.ChampionshipFixture
+		if cursor, err := db.ChampionshipFixturesCollection.Find(ctx, bson.M{"championshipId": oid, "status": models.ChampionshipFixtureStatusOngoing}); err == nil {
+			_ = cursor.All(ctx, &fixtures)
+		}
+		for _, f := range fixtures {
+			if f.MatchID != nil {
+				links = append(links, matchFixtureLink{EventType: eventType, EventID: eventID, FixtureID: f.ID.Hex()})
+				matchIDs = append(matchIDs, f.MatchID.Hex())
+			}
+		}
+	}
+
+	ticks := []scoreTick{}
+	for i, matchID := range matchIDs {
+		var state models.EnhancedStatsMessage
+		if err := redisImpl.GetRedisKey(gameStatsKey(matchID), &state); err != nil {
+			continue // not scored yet
+		}
+		ticks = append(ticks, newScoreTick(links[i], matchID, state))
+	}
+	return ticks
+}
+
+// eventViewerSocket serves /ws/event. The first message is
+// {"type":"join","eventType":"tournament"|"championship","eventId":"..."}; after
+// that the viewer only receives.
+func eventViewerSocket(c *websocket.Conn) {
+	defer c.Close()
+
+	_, joinMsg, err := c.ReadMessage()
+	if err != nil {
+		return
+	}
+	var join struct {
+		Type      string `json:"type"`
+		EventType string `json:"eventType"`
+		EventID   string `json:"eventId"`
+	}
+	if err := json.Unmarshal(joinMsg, &join); err != nil || join.Type != "join" || join.EventID == "" {
+		writeJSON(c, map[string]string{"type": "requestJoin"})
+		return
+	}
+	ctx := context.Background()
+	eventID, err := resolveEventChannel(ctx, join.EventType, join.EventID)
+	if err != nil {
+		msg := "Failed to load event"
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			msg = "Event not found"
+		} else if join.EventType != eventChannelTournament && join.EventType != eventChannelChampionship {
+			msg = err.Error()
+		}
+		writeJSON(c, map[string]string{"error": msg})
+		return
+	}
+
+	room := GetEventRoom(eventRoomID(join.EventType, eventID))
+	client := room.AddViewer(c)
+	defer client.Close()
+
+	snapshot := fiber.Map{
+		"type":      eventMsgSnapshot,
+		"eventType": join.EventType,
+		"eventId":   eventID,
+		"live":      liveEventScores(ctx, join.EventType, eventID),
+	}
+	if data, err := json.Marshal(snapshot); err == nil {
+		room.SendToViewer(c, data)
+	}
+
+	for {
+		if _, _, err := c.ReadMessage(); err != nil {
+			break
+		}
+	}
+	room.RemoveViewer(c)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// holdScorerLock takes the scorer lock for owner, or extends it if owner already
+// holds it. REST scorers have no socket to keep the lock alive, so every command
+// renews it; the lock lapses scorerLockTTL after their last command.
+func holdScorerLock(matchID, owner string) (bool, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := acquireScorerLock(matchID, owner)
+		if err != nil || acquired {
+			return acquired, err
+		}
+		holder, err := redisImpl.RedisClient.Get(context.Background(), scorerLockKey(matchID)).Result()
+		if err == redis.Nil {
+			continue // expired between the two calls
+		}
+		if err != nil {
+			return false, err
+		}
+		if holder != owner {
+			return false, nil
+		}
+		refreshScorerLock(matchID, owner)
+		return true, nil
+	}
+	return false, nil
+}
+
+// organizesMatch reports whether the user organizes the event a match is played in
+func organizesMatch(ctx context.Context, matchID string, userID primitive.ObjectID) (bool, error) {
+	eventID, ok := matchOwnerEventID(matchID)
+	if !ok {
+		return false, nil
+	}
+	var event models.Event
+	if err := db.EventsCollection.FindOne(ctx, bson.M{"_id": eventID}).Decode(&event); err != nil {
+		return false, err
+	}
+	return event.OrganizerID == userID, nil
+}
+
+// requireMatchOrganizer checks that the caller organizes the match's event. When
+// they do not, the response has already been written and ok is false.
+func requireMatchOrganizer(c *fiber.Ctx, ctx context.Context, matchID, caller string) (ok bool, resp error) {
+	userID, err := getUserIDFromLocals(c)
+	if err != nil {
+		return false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user"})
+	}
+	organizer, err := organizesMatch(ctx, matchID, userID)
+	if err != nil {
+		logrus.Error("Error:", caller+":", " Failed to check organizer of match %s: %v", matchID, err)
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check match ownership"})
+	}
+	if !organizer {
+		return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Only the event organizer can do this for this match"})
+	}
+	return true, nil
+}
+
+// commandErrorStatus maps a scorer error code to an HTTP status
+func commandErrorStatus(code string) int {
+	switch code {
+	case models.WSErrBadMessage, models.WSErrUnknownType:
+		return fiber.StatusBadRequest
+	case models.WSErrScorerLocked, models.WSErrNotInitialized:
+		return fiber.StatusConflict
+	case models.WSErrCommandRejected:
+		return fiber.StatusUnprocessableEntity
+	}
+	return fiber.StatusInternalServerError
+}
+
+func commandError(c *fiber.Ctx, commandID, code, message string) error {
+	return c.Status(commandErrorStatus(code)).JSON(fiber.Map{"error": message, "code": code, "commandId": commandID})
+}
+
+// PostMatchCommandHandler applies one scoring command over HTTP, for scoreboards
+// and scripts that cannot hold the scorer socket. The body is a version 2 command
+// envelope without "v": {"type","id","baseVersion","payload"}. It goes through the
+// same rules engine, event store and viewer broadcast as the socket, and respects
+// the same scorer lock, so a REST scorer and a browser cannot score at once.
+func PostMatchCommandHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+	userID, err := getUserIDFromLocals(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user"})
+	}
+
+	var env models.WSEnvelope
+	if err := c.BodyParser(&env); err != nil {
+		return commandError(c, "", models.WSErrBadMessage, "Invalid request body")
+	}
+	cmd, bad := commandFromEnvelope(env)
+	if bad != nil {
+		return commandError(c, env.ID, bad.Code, bad.Message)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	organizer, err := organizesMatch(ctx, matchID, userID)
+	if err != nil {
+		logrus.Error("Error:", "PostMatchCommandHandler:", " Failed to check organizer of match %s: %v", matchID, err)
+		return commandError(c, env.ID, models.WSErrInternal, "Failed to check match ownership")
+	}
+	if !organizer {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Only the event organizer can score this match"})
+	}
+
+	actor := models.MatchEventActor{UserID: fmt.Sprint(c.Locals("user_id")), SessionID: fmt.Sprint(c.Locals("session_id"))}
+	held, err := holdScorerLock(matchID, scorerLockOwner(actor))
+	if err != nil {
+		return commandError(c, env.ID, models.WSErrInternal, "Failed to acquire scorer lock")
+	}
+	if !held {
+		return commandError(c, env.ID, models.WSErrScorerLocked, "This match is already being scored by another active scorer")
+	}
+
+	// REST commands are applied as they arrive; a client-supplied time is not trusted
+	result, err := commitScorerCommand(matchID, cmd, actor, time.Now())
+	if err != nil {
+		return commandError(c, env.ID, scorerErrorCode(err), err.Error())
+	}
+	if result.Conflict {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":     "match state has changed since this command was issued",
+			"code":      "conflict",
+			"commandId": env.ID,
+			"version":   result.Match.Data.Version,
+			"state":     result.Match,
+		})
+	}
+
+	events := result.Events
+	if events == nil {
+		events = []scoring.Event{}
+	}
+	return c.JSON(fiber.Map{
+		"matchId":   matchID,
+		"commandId": env.ID,
+		"version":   result.Match.Data.Version,
+		"duplicate": result.Duplicate,
+		"events":    events,
+		"data":      result.Match.Data,
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"reflect"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// matchDelta is the compact viewer update sent after a scorer command instead of
+// the whole match state. Seq is the state version the delta produces: a client
+// applies it only on top of Seq-1 and asks for a snapshot when it sees a gap.
+// Fields that did not change are left out.
+type matchDelta struct {
+	Type            string                       `json:"type"` // always "delta"
+	Seq             int64                        `json:"seq"`
+	TeamA           *models.TeamStat             `json:"teamA,omitempty"`
+	TeamB           *models.TeamStat             `json:"teamB,omitempty"`
+	Players         map[string]models.PlayerStat `json:"players,omitempty"`     // changed players only
+	RaidLogFrom     *int                         `json:"raidLogFrom,omitempty"` // keep this many existing entries, then append RaidLog
+	RaidLog         []models.RaidLogEntry        `json:"raidLog,omitempty"`
+	RaidDetails     *models.RaidDetails          `json:"raidDetails,omitempty"`
+	RaidNumber      int                          `json:"raidNumber,omitempty"`
+	Awards          *models.MatchAwards          `json:"awards,omitempty"`
+	EmptyRaidCounts *models.TeamCounts           `json:"emptyRaidCounts,omitempty"`
+	Clock           *models.MatchClock           `json:"clock,omitempty"`
+	TieBreak        *models.TieBreakState        `json:"tieBreak,omitempty"`
+	Review          *models.ReviewState          `json:"review,omitempty"`
+	OutQueue        *models.OutQueues            `json:"outQueue,omitempty"`
+	Commentary      []models.CommentaryEntry     `json:"commentary,omitempty"` // new lines; drop any with index >= RaidLogFrom first
+	WinProbability  *models.WinProbability       `json:"winProbability,omitempty"`
+}
+
+const deltaMessageType = "delta"
+
+// buildMatchDelta describes how next differs from prev for viewers
+func buildMatchDelta(prev, next models.EnhancedStatsMessage) matchDelta {
+	p, n := prev.Data, next.Data
+	delta := matchDelta{Type: deltaMessageType, Seq: n.Version}
+
+	if p.TeamA != n.TeamA {
+		delta.TeamA = &n.TeamA
+	}
+	if p.TeamB != n.TeamB {
+		delta.TeamB = &n.TeamB
+	}
+	for id, stat := range n.PlayerStats {
+		if old, ok := p.PlayerStats[id]; !ok || old != stat {
+			if delta.Players == nil {
+				delta.Players = map[string]models.PlayerStat{}
+			}
+			delta.Players[id] = stat
+		}
+	}
+
+	// Raids are usually appended; undo and overturned reviews rewrite the tail
+	common := raidLogCommonPrefix(p.RaidLog, n.RaidLog)
+	if common != len(p.RaidLog) || common != len(n.RaidLog) {
+		delta.RaidLogFrom = &common
+		delta.RaidLog = n.RaidLog[common:]
+	}
+
+	if !reflect.DeepEqual(p.RaidDetails, n.RaidDetails) {
+		delta.RaidDetails = &n.RaidDetails
+	}
+	if p.RaidNumber != n.RaidNumber {
+		delta.RaidNumber = n.RaidNumber
+	}
+	if !reflect.DeepEqual(p.Awards, n.Awards) {
+		delta.Awards = &n.Awards
+	}
+	if p.EmptyRaidCounts != n.EmptyRaidCounts {
+		delta.EmptyRaidCounts = &models.TeamCounts{TeamA: n.EmptyRaidCounts.TeamA, TeamB: n.EmptyRaidCounts.TeamB}
+	}
+	if p.Clock != n.Clock {
+		delta.Clock = &n.Clock
+	}
+	if p.TieBreak != n.TieBreak {
+		delta.TieBreak = &n.TieBreak
+	}
+	if p.Review != n.Review {
+		delta.Review = &n.Review
+	}
+	if !reflect.DeepEqual(p.OutQueue, n.OutQueue) {
+		delta.OutQueue = &n.OutQueue
+	}
+	return delta
+}
+
+// raidLogCommonPrefix counts the leading entries two raid logs share
+func raidLogCommonPrefix(prev, next []models.RaidLogEntry) int {
+	common := 0
+	for common < len(prev) && common < len(next) && reflect.DeepEqual(prev[common], next[common]) {
+		common++
+	}
+	return common
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mhatrejeets/RaidX/internal/db"
 	"github.com/mhatrejeets/RaidX/internal/models"
 	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -115,7 +116,7 @@ func GetMatchByIDJSON(c *fiber.Ctx) error {
 		if redisErr == nil {
 			var live models.EnhancedStatsMessage
 			if unmarshalErr := json.Unmarshal([]byte(redisVal), &live); unmarshalErr == nil {
-				live.Data.Awards = computeAwardsFromPlayerStats(live.Data.PlayerStats)
+				live.Data.Awards = scoring.ComputeAwards(live.Data.PlayerStats)
 				return c.JSON(fiber.Map{
 					"matchId": idParam,
 					"type":    live.Type,
@@ -128,774 +129,8 @@ func GetMatchByIDJSON(c *fiber.Ctx) error {
 		return c.Status(404).JSON(fiber.Map{"error": "Match not found"})
 	}
 
-	match.Data.Awards = computeAwardsFromPlayerStats(match.Data.PlayerStats)
+	match.Data.Awards = scoring.ComputeAwards(match.Data.PlayerStats)
 
 	// Return match as JSON
 	return c.JSON(match)
 }
-
-// RaidPayload represents the payload expected from frontend when submitting a raid
-// Frontend sends ONLY raw data - backend determines everything else
-type RaidPayload struct {
-	RaidType    string   `json:"raidType"`
-	RaiderID    string   `json:"raiderId"`
-	DefenderIDs []string `json:"defenderIds"`
-	BonusTaken  bool     `json:"bonusTaken"`
-	// Note: RaidingTeam and EmptyRaidCounts removed - backend calculates these
-}
-
-// ProcessRaidResult handles raid outcomes and updates scores/state in Redis and broadcasts updates
-func ProcessRaidResult(c *fiber.Ctx) error {
-	var raidData RaidPayload
-	if err := c.BodyParser(&raidData); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid request data"})
-	}
-
-	// Read current match state from Redis
-	var currentMatch models.EnhancedStatsMessage
-	if err := redisImpl.GetRedisKey("gameStats", &currentMatch); err != nil {
-		logrus.Error("Error: ProcessRaidResult: Failed to read gameStats from Redis:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to read game state"})
-	}
-
-	// Process based on raid type
-	switch raidData.RaidType {
-	case "successful":
-		processSuccessfulRaid(&currentMatch, raidData)
-	case "defense":
-		processDefenseSuccess(&currentMatch, raidData)
-	case "empty":
-		processEmptyRaid(&currentMatch, raidData)
-	default:
-		return c.Status(400).JSON(fiber.Map{"error": "unknown raid type"})
-	}
-
-	currentMatch.Data.Awards = computeAwardsFromPlayerStats(currentMatch.Data.PlayerStats)
-
-	// Save back to Redis
-	if err := redisImpl.SetRedisKey("gameStats", currentMatch); err != nil {
-		logrus.Error("Error: ProcessRaidResult: Failed to save gameStats to Redis:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to persist game state"})
-	}
-
-	// Broadcast to viewers
-	BroadcastToViewers(currentMatch)
-
-	// respond with updated state
-	// Use a simple wrapper with Data field to match frontend expectation
-	resp := map[string]interface{}{"data": currentMatch.Data}
-	return c.JSON(resp)
-}
-
-// validateRaidPayload performs server-side validation of incoming raid data
-// Backend automatically determines which team the raider belongs to
-func validateRaidPayload(raid RaidPayload, match *models.EnhancedStatsMessage) error {
-	// raid type
-	if raid.RaidType != "successful" && raid.RaidType != "defense" && raid.RaidType != "empty" {
-		return fmt.Errorf("invalid raidType: %s", raid.RaidType)
-	}
-
-	// raider exists
-	if raid.RaiderID == "" {
-		return fmt.Errorf("missing raiderId")
-	}
-	raider, ok := match.Data.PlayerStats[raid.RaiderID]
-	if !ok {
-		// If the raider id exists in the team player id lists but missing from
-		// the PlayerStats map (possible if client initialized player lists but
-		// didn't populate stats), create a default PlayerStat entry so validation
-		// can proceed. This avoids spurious "raider not found" errors when the
-		// authoritative PlayerStats map is missing entries.
-		foundInTeam := false
-		for _, pid := range match.Data.TeamAPlayerIDs {
-			if pid == raid.RaiderID {
-				foundInTeam = true
-				break
-			}
-		}
-		if !foundInTeam {
-			for _, pid := range match.Data.TeamBPlayerIDs {
-				if pid == raid.RaiderID {
-					foundInTeam = true
-					break
-				}
-			}
-		}
-		if foundInTeam {
-			// initialize minimal PlayerStat
-			ps := models.PlayerStat{
-				Name:              raid.RaiderID,
-				ID:                raid.RaiderID,
-				RaidPoints:        0,
-				DefencePoints:     0,
-				TotalPoints:       0,
-				SuperRaids:        0,
-				SuperTackles:      0,
-				TotalRaids:        0,
-				SuccessfulRaids:   0,
-				TotalTackles:      0,
-				SuccessfulTackles: 0,
-				Status:            "in",
-			}
-			match.Data.PlayerStats[raid.RaiderID] = ps
-			raider = ps
-			ok = true
-			logrus.Info("Info:", "validateRaidPayload:", " Initialized missing PlayerStat for raider: %s", raid.RaiderID)
-		}
-	}
-	if !ok {
-		return fmt.Errorf("raider not found: %s", raid.RaiderID)
-	}
-	if raider.Status != "in" {
-		return fmt.Errorf("raider is not active: %s", raid.RaiderID)
-	}
-
-	// Determine which team the raider belongs to (backend logic, not frontend)
-	raiderTeam := ""
-	for _, pid := range match.Data.TeamAPlayerIDs {
-		if pid == raid.RaiderID {
-			raiderTeam = "A"
-			break
-		}
-	}
-	if raiderTeam == "" {
-		for _, pid := range match.Data.TeamBPlayerIDs {
-			if pid == raid.RaiderID {
-				raiderTeam = "B"
-				break
-			}
-		}
-	}
-	if raiderTeam == "" {
-		return fmt.Errorf("raider does not belong to any team: %s", raid.RaiderID)
-	}
-
-	// Verify alternating raid rule - backend determines expected team based on toss
-	firstRaidingTeam := match.Data.FirstRaidingTeam
-	if firstRaidingTeam == "" {
-		firstRaidingTeam = "teamA" // Default fallback for legacy matches
-	}
-
-	expectedRaidingTeam := "A"
-	if match.Data.RaidNumber%2 == 0 {
-		// Even raid - opposite team raids
-		if firstRaidingTeam == "teamA" {
-			expectedRaidingTeam = "B"
-		} else {
-			expectedRaidingTeam = "A"
-		}
-	} else {
-		// Odd raid - first team raids
-		if firstRaidingTeam == "teamA" {
-			expectedRaidingTeam = "A"
-		} else {
-			expectedRaidingTeam = "B"
-		}
-	}
-
-	if raiderTeam != expectedRaidingTeam {
-		return fmt.Errorf("incorrect raiding team. Expected team %s to raid, but raider belongs to team %s", expectedRaidingTeam, raiderTeam)
-	}
-
-	// defenders validation for types that require defenders
-	if raid.RaidType == "successful" || raid.RaidType == "defense" {
-		if len(raid.DefenderIDs) == 0 {
-			return fmt.Errorf("defenderIds required for raidType %s", raid.RaidType)
-		}
-		for _, defID := range raid.DefenderIDs {
-			if defID == raid.RaiderID {
-				return fmt.Errorf("defenderId equals raiderId: %s", defID)
-			}
-			d, ok := match.Data.PlayerStats[defID]
-			if !ok {
-				// Try to initialize missing defender stat if the id exists in team lists
-				foundInTeam := false
-				for _, pid := range match.Data.TeamAPlayerIDs {
-					if pid == defID {
-						foundInTeam = true
-						break
-					}
-				}
-				if !foundInTeam {
-					for _, pid := range match.Data.TeamBPlayerIDs {
-						if pid == defID {
-							foundInTeam = true
-							break
-						}
-					}
-				}
-				if foundInTeam {
-					ps := models.PlayerStat{
-						Name:              defID,
-						ID:                defID,
-						RaidPoints:        0,
-						DefencePoints:     0,
-						TotalPoints:       0,
-						SuperRaids:        0,
-						SuperTackles:      0,
-						TotalRaids:        0,
-						SuccessfulRaids:   0,
-						TotalTackles:      0,
-						SuccessfulTackles: 0,
-						Status:            "in",
-					}
-					match.Data.PlayerStats[defID] = ps
-					d = ps
-					ok = true
-					logrus.Info("Info:", "validateRaidPayload:", " Initialized missing PlayerStat for defender: %s", defID)
-				}
-			}
-			if !ok {
-				return fmt.Errorf("defender not found: %s", defID)
-			}
-			if d.Status != "in" {
-				return fmt.Errorf("defender is not active: %s", defID)
-			}
-		}
-	}
-
-	return nil
-}
-
-// Helper function to determine which team a raider belongs to (backend logic)
-func getRaidingTeam(match *models.EnhancedStatsMessage, raiderID string) string {
-	for _, pid := range match.Data.TeamAPlayerIDs {
-		if pid == raiderID {
-			return "A"
-		}
-	}
-	for _, pid := range match.Data.TeamBPlayerIDs {
-		if pid == raiderID {
-			return "B"
-		}
-	}
-	return "" // should never happen if validation passed
-}
-
-func processSuccessfulRaid(match *models.EnhancedStatsMessage, raid RaidPayload) {
-	// Backend determines raiding team from raider's team membership
-	raidingTeam := getRaidingTeam(match, raid.RaiderID)
-	raidNumber := match.Data.RaidNumber
-	lobbyEvents := consumeLobbyEvents(match)
-	lobbyRaiderEntered, lobbyDefenders := splitLobbyEvents(match, lobbyEvents)
-
-	// Check if this raid is a do-or-die (third consecutive empty for raiding team)
-	doOrDie := false
-	if raidingTeam == "A" {
-		doOrDie = match.Data.EmptyRaidCounts.TeamA >= 2
-	} else {
-		doOrDie = match.Data.EmptyRaidCounts.TeamB >= 2
-	}
-
-	raidPoints := len(raid.DefenderIDs)
-
-	// update team score
-	if raidingTeam == "A" {
-		match.Data.TeamA.Score += raidPoints
-		if raid.BonusTaken {
-			match.Data.TeamA.Score++
-		}
-	} else {
-		match.Data.TeamB.Score += raidPoints
-		if raid.BonusTaken {
-			match.Data.TeamB.Score++
-		}
-	}
-
-	// update raider stats
-	raiderStat := match.Data.PlayerStats[raid.RaiderID]
-	raiderStat.TotalRaids++
-	raiderStat.SuccessfulRaids++
-	raiderStat.RaidPoints += raidPoints
-	raiderStat.TotalPoints += raidPoints
-	if raid.BonusTaken {
-		raiderStat.RaidPoints++
-		raiderStat.TotalPoints++
-	}
-	if raidPoints >= 3 {
-		raiderStat.SuperRaids++
-	}
-	match.Data.PlayerStats[raid.RaiderID] = raiderStat
-
-	// Reset empty raid count for the raiding team on a successful raid
-	if raidingTeam == "A" {
-		match.Data.EmptyRaidCounts.TeamA = 0
-	} else {
-		match.Data.EmptyRaidCounts.TeamB = 0
-	}
-
-	// mark defenders out and keep their stats
-	for _, defID := range raid.DefenderIDs {
-		d := match.Data.PlayerStats[defID]
-		d.TotalTackles++
-		d.Status = "out"
-		match.Data.PlayerStats[defID] = d
-	}
-
-	// revival: for each point gained by raiding team, revive one out player from raiding team (if any)
-	// Only revive players based on defenders out, not bonus points
-	revivals := raidPoints                                  // number of defenders out determines revival count
-	pointsGained := raidPoints + boolToInt(raid.BonusTaken) // total points includes bonus
-	if raidingTeam == "A" {
-		revivePlayersByIDs(match, match.Data.TeamAPlayerIDs, revivals)
-	} else {
-		revivePlayersByIDs(match, match.Data.TeamBPlayerIDs, revivals)
-	}
-
-	// record last raid and check for all out
-	match.Data.RaidDetails = models.RaidDetails{
-		Type:           "raidSuccess",
-		Raider:         raiderStat.Name,
-		Defenders:      getDefenderNames(match, raid.DefenderIDs),
-		PointsGained:   pointsGained,
-		BonusTaken:     raid.BonusTaken,
-		SuperRaid:      raidPoints >= 3,
-		DoOrDie:        doOrDie,
-		LobbyRaider:    lobbyRaiderEntered,
-		LobbyDefenders: lobbyDefenders,
-	}
-
-	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
-		RaidNumber:  raidNumber,
-		RaidingTeam: raidingTeam,
-		RaiderId:    raid.RaiderID,
-		DefenderIds: raid.DefenderIDs,
-		Result:      "raidSuccess",
-		Points:      pointsGained,
-		BonusTaken:  raid.BonusTaken,
-		SuperRaid:   raidPoints >= 3,
-		DoOrDie:     doOrDie,
-		LobbyEvents: lobbyEvents,
-	})
-
-	// Check for all out before incrementing raid number
-	checkAndHandleAllOut(match)
-	match.Data.Awards = computeAwardsFromPlayerStats(match.Data.PlayerStats)
-
-	match.Data.RaidNumber++
-}
-
-func processDefenseSuccess(match *models.EnhancedStatsMessage, raid RaidPayload) {
-	// Backend determines raiding team from raider's team membership
-	raidingTeam := getRaidingTeam(match, raid.RaiderID)
-	raidNumber := match.Data.RaidNumber
-	lobbyEvents := consumeLobbyEvents(match)
-	lobbyRaiderEntered, lobbyDefenders := splitLobbyEvents(match, lobbyEvents)
-
-	// Check if this raid is a do-or-die (third consecutive empty for raiding team)
-	doOrDie := false
-	if raidingTeam == "A" {
-		doOrDie = match.Data.EmptyRaidCounts.TeamA >= 2
-	} else {
-		doOrDie = match.Data.EmptyRaidCounts.TeamB >= 2
-	}
-
-	// Determine defending team by id (A/B)
-	defendingTeam := "A"
-	if raidingTeam == "A" {
-		defendingTeam = "B"
-	}
-
-	// Base defense/tackle points
-	points := 1
-	// Determine super tackle based on defending team's active players (players with status "in").
-	// Super tackle occurs when the defending team has 3 or fewer active players on the mat.
-	var activeDefenders int
-	if defendingTeam == "A" {
-		for _, pid := range match.Data.TeamAPlayerIDs {
-			if match.Data.PlayerStats[pid].Status == "in" {
-				activeDefenders++
-			}
-		}
-	} else {
-		for _, pid := range match.Data.TeamBPlayerIDs {
-			if match.Data.PlayerStats[pid].Status == "in" {
-				activeDefenders++
-			}
-		}
-	}
-
-	isSuperTackle := activeDefenders <= 3
-	superTackleApplied := isSuperTackle
-	if isSuperTackle {
-		points = 2
-	}
-
-	// If raider had taken a bonus, the raiding team ALWAYS gets the bonus point.
-	// Update raider stats accordingly. When a raider with bonus is tackled, defenders
-	// should only be awarded the normal tackle point (1), not the super tackle bonus.
-	if raid.BonusTaken {
-		if raidingTeam == "A" {
-			match.Data.TeamA.Score++
-		} else {
-			match.Data.TeamB.Score++
-		}
-		// update raider stats for the bonus
-		rr := match.Data.PlayerStats[raid.RaiderID]
-		rr.RaidPoints++
-		rr.TotalPoints++
-		match.Data.PlayerStats[raid.RaiderID] = rr
-		// If it looked like a super tackle, reduce it to a normal tackle when bonus was taken
-		if isSuperTackle {
-			points = 1
-			superTackleApplied = false
-		}
-	}
-
-	// Award points to defending team (points already adjusted above)
-	if defendingTeam == "A" {
-		match.Data.TeamA.Score += points
-	} else {
-		match.Data.TeamB.Score += points
-	}
-
-	// Mark raider out
-	r := match.Data.PlayerStats[raid.RaiderID]
-	r.TotalRaids++
-	r.Status = "out"
-	match.Data.PlayerStats[raid.RaiderID] = r
-
-	// update defender stats
-	for _, defID := range raid.DefenderIDs {
-		d := match.Data.PlayerStats[defID]
-		d.TotalTackles++
-		d.SuccessfulTackles++
-		d.DefencePoints++
-		d.TotalPoints++
-		if superTackleApplied {
-			d.SuperTackles++
-		}
-		match.Data.PlayerStats[defID] = d
-	}
-
-	// PointsGained should reflect total points awarded this raid: defender points + bonus (if any)
-	match.Data.RaidDetails = models.RaidDetails{
-		Type:           "defenseSuccess",
-		Raider:         r.Name,
-		Defenders:      getDefenderNames(match, raid.DefenderIDs),
-		PointsGained:   points + boolToInt(raid.BonusTaken),
-		SuperTackle:    superTackleApplied,
-		BonusTaken:     raid.BonusTaken,
-		DoOrDie:        doOrDie,
-		LobbyRaider:    lobbyRaiderEntered,
-		LobbyDefenders: lobbyDefenders,
-	}
-
-	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
-		RaidNumber:  raidNumber,
-		RaidingTeam: raidingTeam,
-		RaiderId:    raid.RaiderID,
-		DefenderIds: raid.DefenderIDs,
-		Result:      "defenseSuccess",
-		Points:      points + boolToInt(raid.BonusTaken),
-		BonusTaken:  raid.BonusTaken,
-		SuperTackle: superTackleApplied,
-		DoOrDie:     doOrDie,
-		LobbyEvents: lobbyEvents,
-	})
-
-	// Check for all out before revival (will add all-out points to RaidDetails if any)
-	checkAndHandleAllOut(match)
-	// Revive exactly 1 player for the defending team (super tackle revives 1 as per rules)
-
-	// Reset empty raid count for the raiding team on a defense success
-	if raidingTeam == "A" {
-		match.Data.EmptyRaidCounts.TeamA = 0
-	} else {
-		match.Data.EmptyRaidCounts.TeamB = 0
-	}
-
-	// Revive exactly 1 player for the defending team (super tackle revives 1 as per rules)
-	if defendingTeam == "A" {
-		revivePlayersByIDs(match, match.Data.TeamAPlayerIDs, 1)
-	} else {
-		revivePlayersByIDs(match, match.Data.TeamBPlayerIDs, 1)
-	}
-	match.Data.Awards = computeAwardsFromPlayerStats(match.Data.PlayerStats)
-
-	match.Data.RaidNumber++
-}
-
-func processEmptyRaid(match *models.EnhancedStatsMessage, raid RaidPayload) {
-	// Backend determines raiding team from raider's team membership
-	raidingTeam := getRaidingTeam(match, raid.RaiderID)
-	raidNumber := match.Data.RaidNumber
-	lobbyEvents := consumeLobbyEvents(match)
-	lobbyRaiderEntered, lobbyDefenders := splitLobbyEvents(match, lobbyEvents)
-
-	r := match.Data.PlayerStats[raid.RaiderID]
-	r.TotalRaids++
-
-	// Award bonus point to raiding team if taken (no revival for bonus points)
-	if raid.BonusTaken {
-		if raidingTeam == "A" {
-			match.Data.TeamA.Score++
-		} else {
-			match.Data.TeamB.Score++
-		}
-		r.RaidPoints++
-		r.TotalPoints++
-	}
-
-	// Backend tracks empty raid counts (increment for current raiding team)
-	emptyCount := 0
-	if raidingTeam == "A" {
-		match.Data.EmptyRaidCounts.TeamA++
-		emptyCount = match.Data.EmptyRaidCounts.TeamA
-	} else {
-		match.Data.EmptyRaidCounts.TeamB++
-		emptyCount = match.Data.EmptyRaidCounts.TeamB
-	}
-
-	if emptyCount >= 3 {
-		// Do-or-die: on the 3rd consecutive empty raid by the same team.
-		// Kabaddi rule: if the raider takes a bonus on the do-or-die raid, the raider is safe
-		// (bonus already awarded above). Otherwise, raider is out, opponent gets 1 point
-		// and opponent revives 1 player.
-		if raid.BonusTaken {
-			// Raider took bonus on do-or-die -> safe. No out, no opponent point.
-			// Reset the empty raid count for that raiding team.
-			if raidingTeam == "A" {
-				match.Data.EmptyRaidCounts.TeamA = 0
-			} else {
-				match.Data.EmptyRaidCounts.TeamB = 0
-			}
-		} else {
-			// failure: raider out, opponent gets 1 point
-			r.Status = "out"
-			if raidingTeam == "A" {
-				match.Data.TeamB.Score++
-			} else {
-				match.Data.TeamA.Score++
-			}
-		}
-	}
-	match.Data.PlayerStats[raid.RaiderID] = r
-	match.Data.RaidDetails = models.RaidDetails{
-		Type:           ternaryString(emptyCount >= 3, "doOrDieRaid", "emptyRaid"),
-		Raider:         r.Name,
-		BonusTaken:     raid.BonusTaken,
-		DoOrDie:        emptyCount >= 3,
-		LobbyRaider:    lobbyRaiderEntered,
-		LobbyDefenders: lobbyDefenders,
-	}
-
-	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
-		RaidNumber:  raidNumber,
-		RaidingTeam: raidingTeam,
-		RaiderId:    raid.RaiderID,
-		Result:      ternaryString(emptyCount >= 3, "doOrDieRaid", "emptyRaid"),
-		Points:      boolToInt(raid.BonusTaken),
-		BonusTaken:  raid.BonusTaken,
-		DoOrDie:     emptyCount >= 3,
-		LobbyEvents: lobbyEvents,
-	})
-	// If do-or-die resulted in raider out (i.e., no bonus taken), defending team gets 1 point and revival
-	if emptyCount >= 3 && !raid.BonusTaken {
-		defending := "A"
-		if raidingTeam == "A" {
-			defending = "B"
-		}
-		if defending == "A" {
-			revivePlayersByIDs(match, match.Data.TeamAPlayerIDs, 1)
-		} else {
-			revivePlayersByIDs(match, match.Data.TeamBPlayerIDs, 1)
-		}
-		// After a do-or-die failure, reset that team's empty count (already reset above for bonus case)
-		if raidingTeam == "A" {
-			match.Data.EmptyRaidCounts.TeamA = 0
-		} else {
-			match.Data.EmptyRaidCounts.TeamB = 0
-		}
-	}
-	match.Data.Awards = computeAwardsFromPlayerStats(match.Data.PlayerStats)
-	match.Data.RaidNumber++
-}
-
-func computeAwardsFromPlayerStats(playerStats map[string]models.PlayerStat) models.MatchAwards {
-	awards := models.MatchAwards{}
-	if len(playerStats) == 0 {
-		return awards
-	}
-
-	mvp := models.AwardInfo{Points: -1}
-	bestRaider := models.AwardInfo{Points: -1}
-	bestDefender := models.AwardInfo{Points: -1}
-
-	for id, stat := range playerStats {
-		name := stat.Name
-		if stat.TotalPoints > mvp.Points {
-			mvp = models.AwardInfo{PlayerId: id, Name: name, Points: stat.TotalPoints}
-		}
-		if stat.RaidPoints > bestRaider.Points {
-			bestRaider = models.AwardInfo{PlayerId: id, Name: name, Points: stat.RaidPoints}
-		}
-		if stat.DefencePoints > bestDefender.Points {
-			bestDefender = models.AwardInfo{PlayerId: id, Name: name, Points: stat.DefencePoints}
-		}
-	}
-
-	if mvp.Points < 0 {
-		mvp.Points = 0
-	}
-	if bestRaider.Points < 0 {
-		bestRaider.Points = 0
-	}
-	if bestDefender.Points < 0 {
-		bestDefender.Points = 0
-	}
-
-	awards.MVP = mvp
-	awards.BestRaider = bestRaider
-	awards.BestDefender = bestDefender
-	return awards
-}
-
-func consumeLobbyEvents(match *models.EnhancedStatsMessage) []models.LobbyEvent {
-	if len(match.Data.PendingLobby.Events) == 0 {
-		return nil
-	}
-	currentRaid := match.Data.RaidNumber
-	current := make([]models.LobbyEvent, 0)
-	remaining := make([]models.LobbyEvent, 0)
-	for _, ev := range match.Data.PendingLobby.Events {
-		if ev.RaidNumber == 0 || ev.RaidNumber == currentRaid {
-			current = append(current, ev)
-			continue
-		}
-		if ev.RaidNumber > currentRaid {
-			remaining = append(remaining, ev)
-		}
-	}
-	match.Data.PendingLobby.Events = remaining
-	if len(current) == 0 {
-		return nil
-	}
-	return current
-}
-
-func splitLobbyEvents(match *models.EnhancedStatsMessage, events []models.LobbyEvent) (bool, []string) {
-	if len(events) == 0 {
-		return false, nil
-	}
-	lobbyRaider := false
-	lobbyDefenders := []string{}
-	for _, ev := range events {
-		if ev.IsRaider {
-			lobbyRaider = true
-			continue
-		}
-		if p, ok := match.Data.PlayerStats[ev.TouchedPlayerId]; ok {
-			lobbyDefenders = append(lobbyDefenders, p.Name)
-		} else {
-			lobbyDefenders = append(lobbyDefenders, ev.TouchedPlayerId)
-		}
-	}
-	return lobbyRaider, lobbyDefenders
-}
-
-// revivePlayersByIDs revives up to count players from the given playerID list by
-// setting their status to "in" in match.Data.PlayerStats. It revives the earliest
-// out players found in the provided order.
-func revivePlayersByIDs(match *models.EnhancedStatsMessage, playerIDs []string, count int) {
-	if count <= 0 || playerIDs == nil {
-		return
-	}
-	revived := 0
-	for _, pid := range playerIDs {
-		if revived >= count {
-			break
-		}
-		p, ok := match.Data.PlayerStats[pid]
-		if !ok {
-			continue
-		}
-		if p.Status == "out" {
-			p.Status = "in"
-			match.Data.PlayerStats[pid] = p
-			revived++
-		}
-	}
-}
-
-func boolToInt(b bool) int {
-	if b {
-		return 1
-	}
-	return 0
-}
-
-func getDefenderNames(match *models.EnhancedStatsMessage, defenderIDs []string) []string {
-	names := make([]string, len(defenderIDs))
-	for i, id := range defenderIDs {
-		names[i] = match.Data.PlayerStats[id].Name
-	}
-	return names
-}
-
-func ternaryString(cond bool, a, b string) string {
-	if cond {
-		return a
-	}
-	return b
-}
-
-// checkAndHandleAllOut checks if a team is all out and handles the scoring and revival
-// Returns true if an all-out occurred, false otherwise
-func checkAndHandleAllOut(match *models.EnhancedStatsMessage) bool {
-	// Check Team A
-	allOutA := true
-	activePlayersA := 0
-	for _, pid := range match.Data.TeamAPlayerIDs {
-		if match.Data.PlayerStats[pid].Status == "in" {
-			allOutA = false
-			activePlayersA++
-		}
-	}
-
-	// Check Team B
-	allOutB := true
-	activePlayersB := 0
-	for _, pid := range match.Data.TeamBPlayerIDs {
-		if match.Data.PlayerStats[pid].Status == "in" {
-			allOutB = false
-			activePlayersB++
-		}
-	}
-
-	// Handle Team A all out (only if they actually have players)
-	if allOutA && len(match.Data.TeamAPlayerIDs) > 0 {
-		// Award 2 extra points to Team B (all-out bonus)
-		match.Data.TeamB.Score += 2
-		// Revive all Team A players
-		for _, pid := range match.Data.TeamAPlayerIDs {
-			p := match.Data.PlayerStats[pid]
-			p.Status = "in"
-			match.Data.PlayerStats[pid] = p
-		}
-		// Update raid details
-		match.Data.RaidDetails.AllOut = true
-		match.Data.RaidDetails.AllOutTeam = "A"
-		match.Data.RaidDetails.PointsGained += 2 // Add all-out points to total points gained
-		return true
-	}
-
-	// Handle Team B all out (only if they actually have players)
-	if allOutB && len(match.Data.TeamBPlayerIDs) > 0 {
-		// Award 2 extra points to Team A (all-out bonus)
-		match.Data.TeamA.Score += 2
-		// Revive all Team B players
-		for _, pid := range match.Data.TeamBPlayerIDs {
-			p := match.Data.PlayerStats[pid]
-			p.Status = "in"
-			match.Data.PlayerStats[pid] = p
-		}
-		// Update raid details
-		match.Data.RaidDetails.AllOut = true
-		match.Data.RaidDetails.AllOutTeam = "B"
-		match.Data.RaidDetails.PointsGained += 2 // Add all-out points to total points gained
-		return true
-	}
-
-	return false
-}
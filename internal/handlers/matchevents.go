@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	matchEventsPendingKey = "matchEventsPending"    // events that could not be stored yet, retried by the snapshot worker
+	matchEventsDeadKey    = "matchEventsDeadLetter" // events that can never be stored as they are, kept for an operator
+	// matchEventRebuild marks where an organizer rebuilt the live state from the
+	// events before it. It only takes up a version; replaying it changes nothing.
+	matchEventRebuild = "rebuild"
+)
+
+// errMatchEventConflict means another event already holds the sequence number,
+// so retrying the write can never succeed
+var errMatchEventConflict = errors.New("sequence number already taken")
+
+func matchEventsColl() *mongo.Collection {
+	return db.MongoClient.Database("raidx").Collection("match_events")
+}
+
+// recordMatchEvent appends an applied scorer command to the match event store.
+// seq is the state version the command produced, so events follow the order
+// the commands were committed in. Events are never updated or deleted once
+// written. The write is tried once, as the scorer waits on it; an event that
+// cannot be stored is queued for the snapshot worker to retry.
+func recordMatchEvent(matchID string, seq int64, cmdType, commandID string, msg []byte, actor models.MatchEventActor, at time.Time) error {
+	event := models.MatchEvent{
+		MatchID:   matchID,
+		Seq:       seq,
+		Type:      cmdType,
+		Payload:   string(msg),
+		CommandID: commandID,
+		Actor:     actor,
+		CreatedAt: at,
+	}
+
+	err := insertMatchEvent(event)
+	if err == nil {
+		return nil
+	}
+
+	data, merr := json.Marshal(event)
+	if merr != nil {
+		return err
+	}
+	queue, note := matchEventsPendingKey, "queued for retry"
+	if errors.Is(err, errMatchEventConflict) {
+		queue, note = matchEventsDeadKey, "moved to dead letters"
+	}
+	if qerr := redisImpl.RedisClient.RPush(context.Background(), queue, data).Err(); qerr != nil {
+		return fmt.Errorf("%v; queueing failed: %v", err, qerr)
+	}
+	return fmt.Errorf("%v; %s", err, note)
+}
+
+// insertMatchEvent stores one event. The unique index on (matchId, seq) turns a
+// second write of the same event into a duplicate key error, which is fine when
+// the stored event is this one, as after a write that timed out but landed.
+func insertMatchEvent(event models.MatchEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := matchEventsColl().InsertOne(ctx, event)
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	var stored models.MatchEvent
+	if ferr := matchEventsColl().FindOne(ctx, bson.M{"matchId": event.MatchID, "seq": event.Seq}).Decode(&stored); ferr != nil {
+		return ferr
+	}
+	if stored.Type != event.Type || stored.CommandID != event.CommandID || stored.Payload != event.Payload {
+		return fmt.Errorf("%w: event %d of match %s is another %s command", errMatchEventConflict, event.Seq, event.MatchID, stored.Type)
+	}
+	return nil
+}
+
+// flushPendingMatchEvents retries events recordMatchEvent could not store. Events
+// that fail again go to the back of the queue for the next run; events whose
+// sequence number is taken by another event move to the dead-letter list, so
+// they never hold up the rest.
+func flushPendingMatchEvents() {
+	ctx := context.Background()
+	pending, err := redisImpl.RedisClient.LLen(ctx, matchEventsPendingKey).Result()
+	if err != nil {
+		return
+	}
+	for i := int64(0); i < pending; i++ {
+		raw, err := redisImpl.RedisClient.LPop(ctx, matchEventsPendingKey).Bytes()
+		if err != nil {
+			return
+		}
+		var event models.MatchEvent
+		if err := json.Unmarshal(raw, &event); err != nil {
+			logrus.Error("Error:", "flushPendingMatchEvents:", " Dropping unreadable event: %v", err)
+			continue
+		}
+		err = insertMatchEvent(event)
+		switch {
+		case err == nil:
+		case errors.Is(err, errMatchEventConflict):
+			logrus.Error("Error:", "flushPendingMatchEvents:", " Moving event to dead letters: %v", err)
+			_ = redisImpl.RedisClient.RPush(ctx, matchEventsDeadKey, raw).Err()
+		default:
+			logrus.Warnf("match event %s/%d still not stored: %v", event.MatchID, event.Seq, err)
+			_ = redisImpl.RedisClient.RPush(ctx, matchEventsPendingKey, raw).Err()
+		}
+	}
+}
+
+// loadMatchEvents returns all recorded events for a match in sequence order
+func loadMatchEvents(ctx context.Context, matchID string) ([]models.MatchEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+	cursor, err := matchEventsColl().Find(ctx, bson.M{"matchId": matchID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []models.MatchEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// pendingMatchEvents returns a match's events still queued for the event store
+func pendingMatchEvents(ctx context.Context, matchID string) ([]models.MatchEvent, error) {
+	raw, err := redisImpl.RedisClient.LRange(ctx, matchEventsPendingKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	var events []models.MatchEvent
+	for _, item := range raw {
+		var event models.MatchEvent
+		if json.Unmarshal([]byte(item), &event) == nil && event.MatchID == matchID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// loadReplayEvents returns every event a replay of the match needs: the stored
+// ones and any still queued, in sequence order
+func loadReplayEvents(ctx context.Context, matchID string) ([]models.MatchEvent, error) {
+	events, err := loadMatchEvents(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := pendingMatchEvents(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[int64]bool, len(events))
+	for _, ev := range events {
+		stored[ev.Seq] = true
+	}
+	for _, ev := range pending {
+		if !stored[ev.Seq] {
+			stored[ev.Seq] = true
+			events = append(events, ev)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+	return events, nil
+}
+
+// replayMatchEvents derives match state by applying events in order through the
+// same command path used by the live scorer. The state's version is the last
+// event's Seq, so commands after the replay carry on the sequence.
+func replayMatchEvents(events []models.MatchEvent) (models.EnhancedStatsMessage, error) {
+	var match models.EnhancedStatsMessage
+	initialized := false
+	for _, ev := range events {
+		if ev.Type == matchEventRebuild {
+			continue
+		}
+		if commandNeedsState(ev.Type) && !initialized {
+			return match, fmt.Errorf("event %d (%s) recorded before initial state", ev.Seq, ev.Type)
+		}
+		if _, err := applyScorerCommand(&match, ev.Type, []byte(ev.Payload), ev.CreatedAt); err != nil {
+			return match, fmt.Errorf("event %d (%s) failed to replay: %v", ev.Seq, ev.Type, err)
+		}
+		initialized = true
+	}
+	if !initialized {
+		return match, fmt.Errorf("no events recorded for match")
+	}
+	match.Data.Version = events[len(events)-1].Seq
+	return match, nil
+}
+
+// replayMatchEventsFor loads and replays the event store for a single match
+func replayMatchEventsFor(matchID string) (models.EnhancedStatsMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events, err := loadReplayEvents(ctx, matchID)
+	if err != nil {
+		return models.EnhancedStatsMessage{}, err
+	}
+	return replayMatchEvents(events)
+}
+
+// GetMatchEventsHandler returns the audit trail of scorer commands for a match.
+// Only the organizer of the match's event may read it.
+func GetMatchEventsHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if ok, resp := requireMatchOrganizer(c, ctx, matchID, "GetMatchEventsHandler"); !ok {
+		return resp
+	}
+
+	events, err := loadMatchEvents(ctx, matchID)
+	if err != nil {
+		logrus.Error("Error:", "GetMatchEventsHandler:", " Failed to load events: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load match events"})
+	}
+
+	return c.JSON(fiber.Map{"matchId": matchID, "events": events})
+}
+
+// RebuildMatchStateHandler replays the event store and overwrites the live Redis state for a match.
+// It is refused while a scorer on another session holds the scorer lock.
+// Pass ?dryRun=1 to only return the derived state.
+func RebuildMatchStateHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if ok, resp := requireMatchOrganizer(c, ctx, matchID, "RebuildMatchStateHandler"); !ok {
+		return resp
+	}
+
+	// Hold the scorer lock while replaying so no command lands between the
+	// replay and the write. A scorer on another session must stop first.
+	dryRun := c.Query("dryRun") != ""
+	actor := models.MatchEventActor{UserID: fmt.Sprint(c.Locals("user_id")), SessionID: fmt.Sprint(c.Locals("session_id"))}
+	if !dryRun {
+		owner := scorerLockOwner(actor)
+		acquired, err := acquireScorerLock(matchID, owner)
+		if err != nil {
+			logrus.Error("Error:", "RebuildMatchStateHandler:", " Failed to take scorer lock: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to take scorer lock"})
+		}
+		if acquired {
+			defer releaseScorerLock(matchID, owner)
+		} else if holder, _ := redisImpl.RedisClient.Get(ctx, scorerLockKey(matchID)).Result(); holder != owner {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "Another scorer is scoring this match; stop scoring before rebuilding"})
+		}
+	}
+
+	flushPendingMatchEvents()
+	events, err := loadReplayEvents(ctx, matchID)
+	if err != nil {
+		logrus.Error("Error:", "RebuildMatchStateHandler:", " Failed to load events: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load match events"})
+	}
+
+	rebuilt, err := replayMatchEvents(events)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if !dryRun {
+		if rebuilt, err = replaceMatchStateAtomically(matchID, rebuilt); err != nil {
+			logrus.Error("Error:", "RebuildMatchStateHandler:", " Failed to store rebuilt state: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to store rebuilt state"})
+		}
+		if err := recordMatchEvent(matchID, rebuilt.Data.Version, matchEventRebuild, "", []byte("{}"), actor, time.Now()); err != nil {
+			logrus.Error("Error:", "RebuildMatchStateHandler:", " Failed to record rebuild of match %s: %v", matchID, err)
+		}
+		persistMatchSnapshot(matchID, rebuilt)
+		syncMatchCommentary(matchID, models.EnhancedStatsMessage{}, rebuilt, nil)
+		pruneWinProbability(matchID, len(rebuilt.Data.RaidLog))
+		resetViewerDeltas(matchID)
+		if data, err := json.Marshal(rebuilt); err == nil {
+			publishRoomMessage(matchID, roomMessage{Kind: roomMessageBroadcast, Data: data})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"matchId":        matchID,
+		"eventsReplayed": len(events),
+		"data":           rebuilt.Data,
+	})
+}
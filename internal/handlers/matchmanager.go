@@ -6,13 +6,19 @@ import (
 	"time"
 
 	"github.com/gofiber/websocket/v2"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/sirupsen/logrus"
 )
 
-// MatchRoom manages all connections and broadcasts for a single match_id
+// MatchRoom manages this instance's connections and broadcasts for a single match_id.
+// Broadcasts and takeovers go through Redis so rooms for the same match on other
+// instances see them too.
 type MatchRoom struct {
 	ID           string
-	viewers      map[*websocket.Conn]bool
-	scorers      map[*websocket.Conn]bool
+	eventRoom    bool // tournament or championship channel: no presence or viewership is tracked
+	viewers      map[*websocket.Conn]*roomClient
+	scorers      map[*websocket.Conn]*scorerMember
+	streams      map[*roomStream]bool
 	broadcastCh  chan []byte
 	mu           sync.Mutex
 	stopCh       chan struct{}
@@ -29,6 +35,16 @@ var manager = &MatchManager{rooms: make(map[string]*MatchRoom)}
 
 // GetRoom returns existing room or creates a new one
 func GetRoom(matchID string) *MatchRoom {
+	return getRoom(matchID, false)
+}
+
+// GetEventRoom returns the room of a tournament or championship channel. Event
+// rooms only relay broadcasts; viewer counts belong to the matches themselves.
+func GetEventRoom(roomID string) *MatchRoom {
+	return getRoom(roomID, true)
+}
+
+func getRoom(matchID string, eventRoom bool) *MatchRoom {
 	manager.mu.RLock()
 	r, ok := manager.rooms[matchID]
 	manager.mu.RUnlock()
@@ -44,13 +60,16 @@ func GetRoom(matchID string) *MatchRoom {
 	}
 	r = &MatchRoom{
 		ID:           matchID,
-		viewers:      make(map[*websocket.Conn]bool),
-		scorers:      make(map[*websocket.Conn]bool),
+		eventRoom:    eventRoom,
+		viewers:      make(map[*websocket.Conn]*roomClient),
+		scorers:      make(map[*websocket.Conn]*scorerMember),
+		streams:      make(map[*roomStream]bool),
 		broadcastCh:  make(chan []byte, 100),
 		stopCh:       make(chan struct{}),
 		lastActivity: time.Now(),
 	}
 	manager.rooms[matchID] = r
+	subscribeRoom(matchID)
 	go r.run()
 	return r
 }
@@ -62,42 +81,90 @@ func RemoveRoom(matchID string) {
 	if r, ok := manager.rooms[matchID]; ok {
 		close(r.stopCh)
 		delete(manager.rooms, matchID)
+		unsubscribeRoom(matchID)
+		if !r.eventRoom {
+			go clearPresence(matchID)
+		}
 	}
 }
 
-// run forwards broadcasts to all viewers and cleans up inactive rooms
+// run forwards broadcasts to all viewers and cleans up inactive rooms. It only
+// queues messages, so no viewer can hold up the others.
 func (r *MatchRoom) run() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
+	// Event rooms never tick presence; a nil channel is never ready
+	var presenceC <-chan time.Time
+	if !r.eventRoom {
+		presence := time.NewTicker(presenceInterval)
+		defer presence.Stop()
+		presenceC = presence.C
+	}
 	for {
 		select {
 		case msg := <-r.broadcastCh:
 			r.mu.Lock()
-			for conn := range r.viewers {
-				_ = conn.WriteMessage(websocket.TextMessage, msg)
+			for _, client := range r.viewers {
+				// A viewer that is too far behind is dropped; its handler removes it
+				client.enqueue(msg)
+			}
+			for s := range r.streams {
+				select {
+				case s.ch <- msg:
+				default:
+					// The stream's owner has fallen behind; closing ch ends it so the client reconnects and resumes
+					close(s.ch)
+					delete(r.streams, s)
+				}
 			}
-			r.mu.Unlock()
 			r.lastActivity = time.Now()
+			r.mu.Unlock()
 		case <-ticker.C:
 			// cleanup if no clients for a while
 			r.mu.Lock()
-			if len(r.viewers) == 0 && len(r.scorers) == 0 && time.Since(r.lastActivity) > time.Minute*5 {
+			if len(r.viewers) == 0 && len(r.scorers) == 0 && len(r.streams) == 0 && time.Since(r.lastActivity) > time.Minute*5 {
 				r.mu.Unlock()
 				RemoveRoom(r.ID)
 				return
 			}
 			r.mu.Unlock()
+		case <-presenceC:
+			r.mu.Lock()
+			local := len(r.viewers) + len(r.streams)
+			scorers := make([]models.ScorerConnection, 0, len(r.scorers))
+			for _, m := range r.scorers {
+				scorers = append(scorers, m.info)
+			}
+			r.mu.Unlock()
+			go r.sendPresence(local)
+			if len(scorers) > 0 {
+				go refreshScorerRoster(r.ID, scorers)
+			}
 		case <-r.stopCh:
 			return
 		}
 	}
 }
 
-func (r *MatchRoom) AddViewer(conn *websocket.Conn) {
+// sendPresence reports this instance's viewers and tells them how many are watching in total
+func (r *MatchRoom) sendPresence(local int) {
+	total, err := reportPresence(r.ID, local)
+	if err != nil {
+		logrus.Warnf("presence report failed for match %s: %v", r.ID, err)
+	}
+	if local > 0 {
+		r.deliver(presenceMessage(total))
+	}
+}
+
+// AddViewer starts the viewer's writer. The handler must Close the returned client before it returns.
+func (r *MatchRoom) AddViewer(conn *websocket.Conn) *roomClient {
+	client := newRoomClient(conn)
 	r.mu.Lock()
-	r.viewers[conn] = true
+	r.viewers[conn] = client
 	r.lastActivity = time.Now()
 	r.mu.Unlock()
+	return client
 }
 
 func (r *MatchRoom) RemoveViewer(conn *websocket.Conn) {
@@ -107,13 +174,51 @@ func (r *MatchRoom) RemoveViewer(conn *websocket.Conn) {
 	r.mu.Unlock()
 }
 
-func (r *MatchRoom) AddScorer(conn *websocket.Conn) {
+// roomStream is a viewer that is not a websocket, such as a server-sent events
+// response. The room queues broadcasts on ch and the stream's owner writes them out.
+type roomStream struct {
+	ch chan []byte
+}
+
+const roomStreamBuffer = 64
+
+func (r *MatchRoom) AddStream() *roomStream {
+	s := &roomStream{ch: make(chan []byte, roomStreamBuffer)}
+	r.mu.Lock()
+	r.streams[s] = true
+	r.lastActivity = time.Now()
+	r.mu.Unlock()
+	return s
+}
+
+func (r *MatchRoom) RemoveStream(s *roomStream) {
 	r.mu.Lock()
-	r.scorers[conn] = true
+	if r.streams[s] {
+		close(s.ch)
+		delete(r.streams, s)
+	}
 	r.lastActivity = time.Now()
 	r.mu.Unlock()
 }
 
+// scorerMember is a socket on the scorer side of the room: the chief, an
+// assistant or a mirror
+type scorerMember struct {
+	client *roomClient
+	owner  string // "<user>:<session>", as held in the scorer lock
+	info   models.ScorerConnection
+}
+
+// AddScorer starts the scorer's writer. The handler must Close the returned client before it returns.
+func (r *MatchRoom) AddScorer(conn *websocket.Conn, owner string, info models.ScorerConnection) *roomClient {
+	client := newRoomClient(conn)
+	r.mu.Lock()
+	r.scorers[conn] = &scorerMember{client: client, owner: owner, info: info}
+	r.lastActivity = time.Now()
+	r.mu.Unlock()
+	return client
+}
+
 func (r *MatchRoom) RemoveScorer(conn *websocket.Conn) {
 	r.mu.Lock()
 	delete(r.scorers, conn)
@@ -121,7 +226,13 @@ func (r *MatchRoom) RemoveScorer(conn *websocket.Conn) {
 	r.mu.Unlock()
 }
 
+// BroadcastBytes sends b to the match's viewers on every instance
 func (r *MatchRoom) BroadcastBytes(b []byte) {
+	publishRoomMessage(r.ID, roomMessage{Kind: roomMessageBroadcast, Data: b})
+}
+
+// deliver queues b for this instance's viewers
+func (r *MatchRoom) deliver(b []byte) {
 	select {
 	case r.broadcastCh <- b:
 	default:
@@ -129,28 +240,56 @@ func (r *MatchRoom) BroadcastBytes(b []byte) {
 	}
 }
 
-func (r *MatchRoom) NotifyAndCloseScorers(redirectURL string) {
+// SendToViewer queues a message for a single viewer, in order with the room's broadcasts
+func (r *MatchRoom) SendToViewer(conn *websocket.Conn, b []byte) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	if client, ok := r.viewers[conn]; ok {
+		client.enqueue(b)
+	}
+}
 
-	if len(r.scorers) == 0 {
-		return
+// deliverToScorers queues b for this instance's scorer-side sockets with one of
+// roles, or all of them when roles is empty
+func (r *MatchRoom) deliverToScorers(b []byte, roles []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.scorers {
+		if len(roles) == 0 || containsString(roles, m.info.Role) {
+			m.client.enqueue(b)
+		}
 	}
+}
+
+// NotifyAndCloseScorers tells the match's chief scorer on every instance that
+// scoring continues elsewhere and closes its socket
+func (r *MatchRoom) NotifyAndCloseScorers(redirectURL string) {
+	publishRoomMessage(r.ID, roomMessage{Kind: roomMessageTakeover, RedirectURL: redirectURL})
+}
 
+// closeLocalScorers closes the chief's socket if it is connected here. Assistants
+// and mirrors stay; they follow the match, not the device.
+func (r *MatchRoom) closeLocalScorers(redirectURL string) {
 	payload := map[string]string{
 		"type":        "scorerTakeover",
 		"message":     "Continued on other device",
 		"redirectUrl": redirectURL,
 	}
 	b, _ := json.Marshal(payload)
+	r.closeLocalChief("", b)
+}
 
-	for conn := range r.scorers {
-		if len(b) > 0 {
-			_ = conn.WriteMessage(websocket.TextMessage, b)
+// closeLocalChief sends msg to the chief connected here and closes its socket.
+// A non-empty owner only closes the chief holding the lock as owner.
+func (r *MatchRoom) closeLocalChief(owner string, msg []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for conn, m := range r.scorers {
+		if m.info.Role != models.ScorerRoleChief || (owner != "" && m.owner != owner) {
+			continue
 		}
-		_ = conn.Close()
+		m.client.closeAfter(msg)
 		delete(r.scorers, conn)
+		r.lastActivity = time.Now()
 	}
-
-	r.lastActivity = time.Now()
 }
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+)
+
+// matchStreamKeepAlive is how often an idle event stream gets a comment line, so
+// proxies keep it open and dead clients are noticed
+const matchStreamKeepAlive = 15 * time.Second
+
+// matchStreamRetry tells EventSource clients how long to wait before reconnecting, in milliseconds
+const matchStreamRetry = 3000
+
+// MatchStreamHandler streams a live match as server-sent events, for viewers that
+// cannot hold a websocket open. Each event carries the same JSON as /ws/viewer.
+// Events that advance the state have the state version as their id, so a client
+// that reconnects with Last-Event-ID resumes like a websocket viewer sending lastSeq.
+func MatchStreamHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID is required"})
+	}
+
+	var lastSeq *int64
+	lastEventID := c.Get("Last-Event-ID", c.Query("lastEventId"))
+	if lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid Last-Event-ID"})
+		}
+		lastSeq = &seq
+	}
+
+	// Join the room before reading the state so no broadcast falls in between
+	room := GetRoom(matchID)
+	stream := room.AddStream()
+	recordUniqueViewer(matchID, viewerIdentity("", c.Query("viewerId"), c.IP()))
+
+	var catchUp [][]byte
+	var sent int64
+	var current models.EnhancedStatsMessage
+	if err := redisImpl.GetRedisKey(gameStatsKey(matchID), &current); err == nil {
+		catchUp = viewerCatchUp(matchID, lastSeq, current)
+		sent = current.Data.Version
+	} else {
+		room.RemoveStream(stream)
+		data, _ := json.Marshal(viewerMatchUnavailable(matchID, err))
+		catchUp = [][]byte{data}
+		stream = nil
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if stream != nil {
+			defer room.RemoveStream(stream)
+		}
+
+		fmt.Fprintf(w, "retry: %d\n\n", matchStreamRetry)
+		for _, msg := range catchUp {
+			writeMatchStreamEvent(w, msg)
+		}
+		if w.Flush() != nil || stream == nil {
+			return
+		}
+
+		keepAlive := time.NewTicker(matchStreamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case msg, ok := <-stream.ch:
+				if !ok {
+					// Dropped for falling behind; the client reconnects with Last-Event-ID
+					return
+				}
+				seq, isDelta, ok := viewerMessageSeq(msg)
+				if ok && isDelta && seq <= sent {
+					continue // already covered by the catch-up
+				}
+				if ok {
+					sent = seq
+				}
+				writeMatchStreamEvent(w, msg)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			if w.Flush() != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeMatchStreamEvent writes one viewer message as an SSE event. Marshalled JSON
+// has no newlines, so the message always fits on a single data line.
+func writeMatchStreamEvent(w *bufio.Writer, msg []byte) {
+	if seq, _, ok := viewerMessageSeq(msg); ok {
+		fmt.Fprintf(w, "id: %d\n", seq)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", msg)
+}
+
+// viewerMessageSeq reads the state version a viewer message brings the client to.
+// Notices and errors have none.
+func viewerMessageSeq(msg []byte) (seq int64, isDelta bool, ok bool) {
+	var probe struct {
+		Type string `json:"type"`
+		Seq  int64  `json:"seq"`
+		Data *struct {
+			Version int64 `json:"version"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(msg, &probe) != nil {
+		return 0, false, false
+	}
+	if probe.Type == deltaMessageType {
+		return probe.Seq, true, true
+	}
+	if probe.Data != nil {
+		return probe.Data.Version, false, true
+	}
+	return 0, false, false
+}
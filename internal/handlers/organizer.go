@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -23,9 +24,11 @@ func CreateEventHandler(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		EventName string `json:"event_name" form:"event_name"`
-		EventType string `json:"event_type" form:"event_type"`
-		MaxTeams  int    `json:"max_teams" form:"max_teams"`
+		EventName  string          `json:"event_name" form:"event_name"`
+		EventType  string          `json:"event_type" form:"event_type"`
+		MaxTeams   int             `json:"max_teams" form:"max_teams"`
+		RulePreset string          `json:"rule_preset" form:"rule_preset"`
+		RuleSet    json.RawMessage `json:"rule_set"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
@@ -53,6 +56,11 @@ func CreateEventHandler(c *fiber.Ctx) error {
 		maxTeams = 0
 	}
 
+	ruleSet, err := ruleSetFromRequest(req.RulePreset, req.RuleSet)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	eventsColl := db.MongoClient.Database("raidx").Collection("events")
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -64,6 +72,7 @@ func CreateEventHandler(c *fiber.Ctx) error {
 		MaxTeams:           maxTeams,
 		ParticipatingTeams: []models.EventTeamEntry{},
 		Status:             models.EventStatusDraft,
+		RuleSet:            ruleSet,
 		CreatedAt:          time.Now(),
 		UpdatedAt:          time.Now(),
 	}
@@ -78,6 +87,7 @@ func CreateEventHandler(c *fiber.Ctx) error {
 		"event_name": eventName,
 		"event_type": eventType,
 		"max_teams":  maxTeams,
+		"rule_set":   event.Rules(),
 	})
 }
 
@@ -124,9 +134,11 @@ func UpdateEventHandler(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		EventName string `json:"event_name" form:"event_name"`
-		EventType string `json:"event_type" form:"event_type"`
-		MaxTeams  int    `json:"max_teams" form:"max_teams"`
+		EventName  string          `json:"event_name" form:"event_name"`
+		EventType  string          `json:"event_type" form:"event_type"`
+		MaxTeams   int             `json:"max_teams" form:"max_teams"`
+		RulePreset string          `json:"rule_preset" form:"rule_preset"`
+		RuleSet    json.RawMessage `json:"rule_set"`
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
@@ -154,6 +166,11 @@ func UpdateEventHandler(c *fiber.Ctx) error {
 		maxTeams = 0
 	}
 
+	ruleSet, err := ruleSetFromRequest(req.RulePreset, req.RuleSet)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -169,13 +186,19 @@ func UpdateEventHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot edit an active or completed event"})
 	}
 
+	updateSet := bson.M{
+		"event_name": eventName,
+		"event_type": eventType,
+		"max_teams":  maxTeams,
+		"updated_at": time.Now(),
+	}
+	// Leave the existing rules alone unless the organizer picked new ones
+	if ruleSet != nil {
+		updateSet["rule_set"] = ruleSet
+	}
+
 	res, err := eventsColl.UpdateOne(ctx, bson.M{"_id": eventID, "organizer_id": organizerID}, bson.M{
-		"$set": bson.M{
-			"event_name": eventName,
-			"event_type": eventType,
-			"max_teams":  maxTeams,
-			"updated_at": time.Now(),
-		},
+		"$set": updateSet,
 	})
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update event"})
@@ -234,6 +257,7 @@ func GetOrganizerEventsHandler(c *fiber.Ctx) error {
 			"eventName": event.EventName,
 			"eventType": event.EventType,
 			"maxTeams":  event.MaxTeams,
+			"ruleSet":   event.Rules(),
 			"status":    event.Status,
 			"createdAt": event.CreatedAt,
 			"updatedAt": event.UpdatedAt,
@@ -361,6 +385,7 @@ func GetOrganizerEventDetailHandler(c *fiber.Ctx) error {
 		"eventName":     event.EventName,
 		"eventType":     event.EventType,
 		"maxTeams":      event.MaxTeams,
+		"ruleSet":       event.Rules(),
 		"status":        event.Status,
 		"createdAt":     event.CreatedAt,
 		"updatedAt":     event.UpdatedAt,
@@ -414,6 +439,10 @@ func GetOrganizerEventMatchStatsHandler(c *fiber.Ctx) error {
 	if findErr != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch match stats"})
 	}
+	if _, ok := matchDoc["viewership"]; !ok {
+		// Matches finished before viewer counting have none recorded
+		matchDoc["viewership"] = models.MatchViewership{}
+	}
 
 	return c.JSON(matchDoc)
 }
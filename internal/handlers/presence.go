@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// presenceInterval is how often each instance reports its viewer count for a match
+// and sends the combined count to its viewers
+const presenceInterval = 10 * time.Second
+
+// An instance that has not reported for this long is assumed gone and its viewers are not counted
+const presenceStale = 3 * presenceInterval
+
+const presenceTTL = 48 * time.Hour
+
+// instanceID names this process in the presence hashes
+var instanceID = func() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}()
+
+// viewerPresenceKey is a hash of instance ID to "<viewers>:<unix time reported>"
+func viewerPresenceKey(matchID string) string {
+	return "viewerPresence:" + matchID
+}
+
+func viewerPeakKey(matchID string) string {
+	return "viewerPeak:" + matchID
+}
+
+// viewerUniqueKey is a HyperLogLog of everyone who has watched the match
+func viewerUniqueKey(matchID string) string {
+	return "viewerUnique:" + matchID
+}
+
+// raiseViewerPeak stores ARGV[1] as the peak if it beats the current one
+var raiseViewerPeak = redis.NewScript(`
+local peak = tonumber(redis.call('GET', KEYS[1]) or '0')
+if tonumber(ARGV[1]) > peak then
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('EXPIRE', KEYS[1], ARGV[2])
+return 0
+`)
+
+// viewerIdentity picks what makes a viewer unique: the signed-in user, else the
+// browser's own ID, else its address
+func viewerIdentity(userID, viewerID, remoteAddr string) string {
+	switch {
+	case userID != "":
+		return "user:" + userID
+	case viewerID != "":
+		return "viewer:" + viewerID
+	}
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		remoteAddr = host
+	}
+	return "addr:" + remoteAddr
+}
+
+// recordUniqueViewer counts viewerKey towards the match's unique viewers
+func recordUniqueViewer(matchID, viewerKey string) {
+	ctx := context.Background()
+	key := viewerUniqueKey(matchID)
+	if err := redisImpl.RedisClient.PFAdd(ctx, key, viewerKey).Err(); err != nil {
+		logrus.Warnf("unique viewer update failed for match %s: %v", matchID, err)
+		return
+	}
+	redisImpl.RedisClient.Expire(ctx, key, presenceTTL)
+}
+
+// reportPresence records this instance's viewer count, raises the peak and
+// returns the number of viewers across all instances
+func reportPresence(matchID string, local int) (int, error) {
+	ctx := context.Background()
+	key := viewerPresenceKey(matchID)
+	now := time.Now()
+	_, err := redisImpl.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, instanceID, fmt.Sprintf("%d:%d", local, now.Unix()))
+		pipe.Expire(ctx, key, presenceTTL)
+		return nil
+	})
+	if err != nil {
+		return local, err
+	}
+
+	reports, err := redisImpl.RedisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return local, err
+	}
+	total := 0
+	for instance, report := range reports {
+		count, at, ok := strings.Cut(report, ":")
+		reportedAt, atErr := strconv.ParseInt(at, 10, 64)
+		viewers, countErr := strconv.Atoi(count)
+		if !ok || atErr != nil || countErr != nil {
+			continue
+		}
+		if now.Sub(time.Unix(reportedAt, 0)) > presenceStale {
+			redisImpl.RedisClient.HDel(ctx, key, instance)
+			continue
+		}
+		total += viewers
+	}
+
+	if err := raiseViewerPeak.Run(ctx, redisImpl.RedisClient, []string{viewerPeakKey(matchID)}, total, int(presenceTTL.Seconds())).Err(); err != nil {
+		logrus.Warnf("viewer peak update failed for match %s: %v", matchID, err)
+	}
+	return total, nil
+}
+
+// clearPresence withdraws this instance's viewers when it drops the room
+func clearPresence(matchID string) {
+	redisImpl.RedisClient.HDel(context.Background(), viewerPresenceKey(matchID), instanceID)
+}
+
+// matchViewership reads the peak and unique viewer counts recorded for a match
+func matchViewership(matchID string) models.MatchViewership {
+	ctx := context.Background()
+	var v models.MatchViewership
+	v.PeakViewers, _ = redisImpl.RedisClient.Get(ctx, viewerPeakKey(matchID)).Int64()
+	v.UniqueViewers, _ = redisImpl.RedisClient.PFCount(ctx, viewerUniqueKey(matchID)).Result()
+	return v
+}
+
+// presenceMessage tells viewers how many people are watching
+func presenceMessage(viewers int) []byte {
+	data, _ := json.Marshal(map[string]interface{}{"type": "presence", "viewers": viewers})
+	return data
+}
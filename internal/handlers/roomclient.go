@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+)
+
+const (
+	// clientSendBuffer is how many messages a socket may have queued. A client
+	// further behind than this is dropped; viewers reconnect and resume from their
+	// last sequence, so nothing is lost.
+	clientSendBuffer = 64
+
+	clientWriteWait  = 10 * time.Second
+	clientPongWait   = 60 * time.Second
+	clientPingPeriod = clientPongWait * 9 / 10
+)
+
+// roomClient is one websocket in a room. Everything written to it is queued on
+// send and written by the client's own goroutine, so a stalled connection only
+// ever holds up itself. The goroutine also pings the client; a socket that stops
+// answering misses its read deadline and its handler's read loop ends.
+type roomClient struct {
+	conn     *websocket.Conn
+	send     chan []byte
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	finished chan struct{}
+}
+
+func newRoomClient(conn *websocket.Conn) *roomClient {
+	c := &roomClient{
+		conn:     conn,
+		send:     make(chan []byte, clientSendBuffer),
+		stopCh:   make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(clientPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(clientPongWait))
+	})
+	go c.writePump()
+	return c
+}
+
+// enqueue queues msg without blocking. A client whose queue is full is stopped
+// and enqueue reports false.
+func (c *roomClient) enqueue(msg []byte) bool {
+	select {
+	case <-c.stopCh:
+		return false
+	default:
+	}
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		c.stop()
+		return false
+	}
+}
+
+// closeAfter queues a last message; the socket is closed once it has been written
+func (c *roomClient) closeAfter(msg []byte) {
+	if c.enqueue(msg) {
+		c.enqueue(nil)
+	}
+}
+
+func (c *roomClient) stop() {
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+// Close stops the writer and waits for it. The socket's handler must call it
+// before returning, since the websocket package recycles the connection afterwards.
+func (c *roomClient) Close() {
+	c.stop()
+	<-c.finished
+}
+
+func (c *roomClient) writePump() {
+	ticker := time.NewTicker(clientPingPeriod)
+	defer func() {
+		ticker.Stop()
+		// Unblocks the handler's read loop so it can clean up
+		_ = c.conn.Close()
+		close(c.finished)
+	}()
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(clientWriteWait))
+			if msg == nil {
+				_ = c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			}
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(clientWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.stopCh:
+			return
+		}
+	}
+}
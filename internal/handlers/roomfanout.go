@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Room messages travel over Redis pub/sub so every RaidX instance with clients in
+// a match sees them, whichever instance the scorer is connected to.
+const (
+	roomMessageBroadcast = "broadcast" // forward Data to the room's viewers
+	roomMessageTakeover  = "takeover"  // close the room's chief scorer, sending it to RedirectURL
+	roomMessageScorers   = "scorers"   // forward Data to the room's scorer-side sockets with one of Roles
+	roomMessageHandoff   = "handoff"   // send Data to the chief holding the lock as Owner and close it
+)
+
+const matchRoomChannelPrefix = "matchRoom:"
+
+type roomMessage struct {
+	Kind        string          `json:"kind"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	RedirectURL string          `json:"redirectUrl,omitempty"`
+	Roles       []string        `json:"roles,omitempty"`
+	Owner       string          `json:"owner,omitempty"`
+}
+
+// roomFanout is this instance's single pub/sub connection. It is subscribed to the
+// channel of every room the instance currently holds.
+var roomFanout struct {
+	once   sync.Once
+	pubsub *redis.PubSub
+}
+
+func matchRoomChannel(matchID string) string {
+	return matchRoomChannelPrefix + matchID
+}
+
+// startRoomFanout opens the pub/sub connection and dispatches incoming room messages
+func startRoomFanout() *redis.PubSub {
+	roomFanout.once.Do(func() {
+		roomFanout.pubsub = redisImpl.RedisClient.Subscribe(context.Background())
+		go func() {
+			for msg := range roomFanout.pubsub.Channel() {
+				dispatchRoomMessage(strings.TrimPrefix(msg.Channel, matchRoomChannelPrefix), []byte(msg.Payload))
+			}
+		}()
+	})
+	return roomFanout.pubsub
+}
+
+func subscribeRoom(matchID string) {
+	if err := startRoomFanout().Subscribe(context.Background(), matchRoomChannel(matchID)); err != nil {
+		logrus.Warnf("room fan-out subscribe failed for match %s: %v", matchID, err)
+	}
+}
+
+func unsubscribeRoom(matchID string) {
+	if err := startRoomFanout().Unsubscribe(context.Background(), matchRoomChannel(matchID)); err != nil {
+		logrus.Warnf("room fan-out unsubscribe failed for match %s: %v", matchID, err)
+	}
+}
+
+// publishRoomMessage sends a room message to every instance, this one included.
+// Local delivery also comes back through the subscription, so the message is
+// handled once per instance. Publishing does not need a local room.
+func publishRoomMessage(matchID string, msg roomMessage) {
+	data, err := json.Marshal(msg)
+	if err == nil {
+		err = redisImpl.RedisClient.Publish(context.Background(), matchRoomChannel(matchID), data).Err()
+	}
+	if err != nil {
+		// Redis is down; at least reach the clients connected here
+		logrus.Warnf("room fan-out publish failed for match %s: %v", matchID, err)
+		handleRoomMessage(matchID, msg)
+	}
+}
+
+// dispatchRoomMessage decodes a message received on a match channel
+func dispatchRoomMessage(matchID string, payload []byte) {
+	var msg roomMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		logrus.Warnf("room fan-out: bad message for match %s: %v", matchID, err)
+		return
+	}
+	handleRoomMessage(matchID, msg)
+}
+
+// handleRoomMessage applies a room message to this instance's room. Instances
+// without the room have nobody to deliver to.
+func handleRoomMessage(matchID string, msg roomMessage) {
+	manager.mu.RLock()
+	r, ok := manager.rooms[matchID]
+	manager.mu.RUnlock()
+	if !ok {
+		return
+	}
+	switch msg.Kind {
+	case roomMessageBroadcast:
+		r.deliver(msg.Data)
+	case roomMessageTakeover:
+		r.closeLocalScorers(msg.RedirectURL)
+	case roomMessageScorers:
+		r.deliverToScorers(msg.Data, msg.Roles)
+	case roomMessageHandoff:
+		r.closeLocalChief(msg.Owner, msg.Data)
+	}
+}
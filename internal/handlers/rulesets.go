@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ruleSetFromRequest builds an event rule set from a preset name and/or custom thresholds.
+// Custom thresholds are laid over the named preset, or the default rule set, so only
+// the fields present in custom change; a zero that is sent still counts. Returns nil
+// when neither is given.
+func ruleSetFromRequest(preset string, custom json.RawMessage) (*models.RuleSet, error) {
+	rules := models.DefaultRuleSet()
+	if strings.TrimSpace(preset) != "" {
+		var ok bool
+		if rules, ok = models.RuleSetPreset(preset); !ok {
+			return nil, fmt.Errorf("unknown rule preset: %s", preset)
+		}
+	}
+
+	if len(custom) > 0 && string(custom) != "null" {
+		base := rules
+		rules.Name = ""
+		if err := json.Unmarshal(custom, &rules); err != nil {
+			return nil, fmt.Errorf("invalid rule_set: %v", err)
+		}
+		if strings.TrimSpace(rules.Name) == "" {
+			rules.Name = base.Name
+			if rules != base {
+				rules.Name = models.RuleSetCustom
+			}
+		}
+		if err := rules.Validate(); err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	}
+
+	if strings.TrimSpace(preset) == "" {
+		return nil, nil
+	}
+	return &rules, nil
+}
+
+// eventForMatch finds the event a live match belongs to, whether it is a standalone
+// match event or a tournament/championship fixture.
+func eventForMatch(ctx context.Context, matchID string) (models.Event, error) {
+	var event models.Event
+	err := db.EventsCollection.FindOne(ctx, bson.M{"active_match_id": matchID}).Decode(&event)
+	if err == nil || !errors.Is(err, mongo.ErrNoDocuments) {
+		return event, err
+	}
+
+	matchObjID, convErr := primitive.ObjectIDFromHex(matchID)
+	if convErr != nil {
+		return event, err
+	}
+
+	var fixture models.Fixture
+	if err := db.FixturesCollection.FindOne(ctx, bson.M{"matchId": matchObjID}).Decode(&fixture); err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return event, err
+	} else if err == nil {
+		var tournament models.Tournament
+		if err := db.TournamentsCollection.FindOne(ctx, bson.M{"_id": fixture.TournamentID}).Decode(&tournament); err != nil {
+			return event, err
+		}
+		return event, db.EventsCollection.FindOne(ctx, bson.M{"_id": tournament.EventID}).Decode(&event)
+	}
+
+	var champFixture models.ChampionshipFixture
+	if err := db.ChampionshipFixturesCollection.FindOne(ctx, bson.M{"matchId": matchObjID}).Decode(&champFixture); err != nil {
+		return event, err
+	}
+	var championship models.Championship
+	if err := db.ChampionshipsCollection.FindOne(ctx, bson.M{"_id": champFixture.ChampionshipID}).Decode(&championship); err != nil {
+		return event, err
+	}
+	return event, db.EventsCollection.FindOne(ctx, bson.M{"_id": championship.EventID}).Decode(&event)
+}
+
+// errMatchRulesUnavailable is returned to a scorer whose initial state could not be
+// stamped with the match's rules; the command is safe to send again
+var errMatchRulesUnavailable = errors.New("could not load the match rules, try again")
+
+// resolveMatchRuleSet returns the rules a match is played under, falling back to
+// the default rule set for ad-hoc matches that do not belong to an event. Any
+// other lookup failure is returned, so a match is never scored under the wrong
+// rules because the database was briefly unreachable.
+func resolveMatchRuleSet(matchID string) (models.RuleSet, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event, err := eventForMatch(ctx, matchID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.DefaultRuleSet(), nil
+	}
+	if err != nil {
+		return models.RuleSet{}, err
+	}
+	return event.Rules(), nil
+}
+
+// withMatchRules stamps the match's rule set onto an initialState message so the
+// server, not the scorer client, decides which rules apply. The stamped message is
+// what gets recorded in the event store, keeping replays independent of later
+// changes to the event.
+func withMatchRules(matchID string, msg []byte) ([]byte, error) {
+	var state models.EnhancedStatsMessage
+	if err := json.Unmarshal(msg, &state); err != nil {
+		return msg, nil
+	}
+	rules, err := resolveMatchRuleSet(matchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the rules for match %s: %w", matchID, err)
+	}
+	state.Data.Rules = rules
+	stamped, err := json.Marshal(state)
+	if err != nil {
+		logrus.Warn("withMatchRules: failed to encode state for match ", matchID, ": ", err)
+		return msg, nil
+	}
+	return stamped, nil
+}
+
+// GetRuleSetPresetsHandler lists the named rule set presets organizers can pick from
+func GetRuleSetPresetsHandler(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"default": models.DefaultRuleSet().Name,
+		"presets": models.RuleSetPresets(),
+	})
+}
+
+// GetMatchRulesHandler returns the rule set a match is (or will be) played under
+func GetMatchRulesHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+	rules, err := resolveMatchRuleSet(matchID)
+	if err != nil {
+		logrus.Error("Error:", "GetMatchRulesHandler:", " Failed to resolve rules for match %s: %v", matchID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load match rules"})
+	}
+	return c.JSON(fiber.Map{"matchId": matchID, "rules": rules})
+}
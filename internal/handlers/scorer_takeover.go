@@ -7,6 +7,6 @@ func forceScorerTakeover(matchID, redirectURL string) {
 		return
 	}
 	_ = redisImpl.DeleteRedisKey("scorer_lock:" + matchID)
-	room := GetRoom(matchID)
-	room.NotifyAndCloseScorers(redirectURL)
+	// Scorers may be connected to any instance, so this one may hold no room
+	publishRoomMessage(matchID, roomMessage{Kind: roomMessageTakeover, RedirectURL: redirectURL})
 }
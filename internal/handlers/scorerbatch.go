@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/sirupsen/logrus"
+)
+
+// maxScorerBatch caps one batch. A ten-minute outage is a few dozen commands.
+const maxScorerBatch = 500
+
+// syncBatch applies the commands a scorer queued while offline, in the order they
+// were issued, and answers with a result for each and the reconciled state.
+// Queued commands were built on states the server never sent, so their base
+// versions mean nothing; each is applied to the current state and the rules
+// engine decides whether it still holds. A command it refuses is rejected and the
+// rest still go ahead. A server failure stops the batch; the commands after it get
+// no result, and the scorer sends them again.
+func (s *scorerSession) syncBatch(matchID string, msg []byte, actor models.MatchEventActor) {
+	var env models.WSEnvelope
+	var batch models.WSBatchPayload
+	if err := json.Unmarshal(msg, &env); err != nil || json.Unmarshal(env.Payload, &batch) != nil {
+		s.sendError(env.ID, models.WSErrBadMessage, "batch payload must hold a commands list")
+		return
+	}
+	if len(batch.Commands) > maxScorerBatch {
+		s.sendError(env.ID, models.WSErrBadMessage, fmt.Sprintf("a batch holds at most %d commands", maxScorerBatch))
+		return
+	}
+
+	results := make([]models.WSBatchCommandResult, 0, len(batch.Commands))
+	now := time.Now()
+	var last time.Time
+	for _, cmdEnv := range batch.Commands {
+		res := models.WSBatchCommandResult{ID: cmdEnv.ID, Type: cmdEnv.Type}
+		cmd, bad := commandFromEnvelope(cmdEnv)
+		if bad == nil && cmdEnv.ID == "" {
+			bad = &models.WSErrorPayload{Code: models.WSErrBadMessage, Message: "queued commands need an id"}
+		}
+		if bad != nil {
+			res.Status, res.Code, res.Reason = models.WSBatchRejected, bad.Code, bad.Message
+			results = append(results, res)
+			continue
+		}
+
+		cmd.Meta.BaseVersion = nil
+		at := batchCommandTime(cmdEnv.At, last, now)
+		last = at
+		result, err := commitScorerCommand(matchID, cmd, actor, at)
+		if err != nil {
+			code := scorerErrorCode(err)
+			if code == models.WSErrInternal {
+				logrus.Error("Error:", "syncBatch:", " Failed to apply queued %s for match %s: %v", cmd.Type, matchID, err)
+				break
+			}
+			res.Status, res.Code, res.Reason = models.WSBatchRejected, code, err.Error()
+		} else if result.Duplicate {
+			res.Status, res.Version = models.WSBatchDuplicate, result.Match.Data.Version
+		} else {
+			res.Status, res.Version = models.WSBatchApplied, result.Match.Data.Version
+		}
+		results = append(results, res)
+	}
+
+	payload := models.WSBatchResultPayload{Results: results}
+	var state models.EnhancedStatsMessage
+	if err := redisImpl.GetRedisKey(gameStatsKey(matchID), &state); err == nil {
+		payload.State = &state
+		payload.Version = state.Data.Version
+	}
+	s.send(models.WSTypeBatchResult, env.ID, payload)
+}
+
+// batchCommandTime is when a queued command counts as having happened: when the
+// scorer issued it, but never in the future and never before the command queued
+// ahead of it, so the match clock only moves forward
+func batchCommandTime(issuedMillis int64, last, now time.Time) time.Time {
+	at := now
+	if issuedMillis > 0 {
+		if issued := time.UnixMilli(issuedMillis); issued.Before(now) {
+			at = issued
+		}
+	}
+	if at.Before(last) {
+		at = last
+	}
+	return at
+}
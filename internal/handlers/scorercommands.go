@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+)
+
+// classifyScorerCommand works out which command a raw scorer message carries.
+// Raid payloads have no type field and are recognised by raidType; anything
+// unrecognised falls back to the legacy full state overwrite.
+func classifyScorerCommand(msg []byte) string {
+	var probe struct {
+		RaidType string `json:"raidType"`
+		Type     string `json:"type"`
+	}
+	_ = json.Unmarshal(msg, &probe)
+
+	switch {
+	case probe.Type == scoring.CommandInitialState:
+		return scoring.CommandInitialState
+	case probe.RaidType != "":
+		return scoring.CommandRaid
+	case probe.Type == scoring.CommandLobbyTouch, probe.Type == scoring.CommandUndo, probe.Type == scoring.CommandRedo,
+		probe.Type == scoring.CommandClock, probe.Type == scoring.CommandSubstitution,
+		probe.Type == scoring.CommandTechnicalPoint, probe.Type == scoring.CommandCard, probe.Type == scoring.CommandTieBreak,
+		probe.Type == scoring.CommandReview:
+		return probe.Type
+	default:
+		return scoring.CommandFullState
+	}
+}
+
+// commandNeedsState reports whether a command must be applied on top of existing match state
+func commandNeedsState(cmdType string) bool {
+	return cmdType != scoring.CommandInitialState && cmdType != scoring.CommandFullState
+}
+
+// decodeScorerCommand turns a raw scorer message into a rules engine command
+func decodeScorerCommand(cmdType string, msg []byte, at time.Time) (scoring.Command, error) {
+	cmd := scoring.Command{Type: cmdType, At: at}
+	switch cmdType {
+	case scoring.CommandInitialState, scoring.CommandFullState:
+		if err := json.Unmarshal(msg, &cmd.State); err != nil {
+			return cmd, fmt.Errorf("invalid state payload: %v", err)
+		}
+	case scoring.CommandRaid:
+		if err := json.Unmarshal(msg, &cmd.Raid); err != nil {
+			return cmd, fmt.Errorf("invalid raid payload: %v", err)
+		}
+	case scoring.CommandLobbyTouch:
+		var lobbyPayload struct {
+			Type string                    `json:"type"`
+			Data scoring.LobbyTouchPayload `json:"data"`
+		}
+		if err := json.Unmarshal(msg, &lobbyPayload); err != nil {
+			return cmd, fmt.Errorf("invalid lobby payload: %v", err)
+		}
+		cmd.Lobby = lobbyPayload.Data
+	case scoring.CommandClock:
+		if err := json.Unmarshal(msg, &cmd.Clock); err != nil {
+			return cmd, fmt.Errorf("invalid clock payload: %v", err)
+		}
+	case scoring.CommandSubstitution:
+		if err := json.Unmarshal(msg, &cmd.Sub); err != nil {
+			return cmd, fmt.Errorf("invalid substitution payload: %v", err)
+		}
+	case scoring.CommandTechnicalPoint:
+		if err := json.Unmarshal(msg, &cmd.Tech); err != nil {
+			return cmd, fmt.Errorf("invalid technical point payload: %v", err)
+		}
+	case scoring.CommandCard:
+		if err := json.Unmarshal(msg, &cmd.Card); err != nil {
+			return cmd, fmt.Errorf("invalid card payload: %v", err)
+		}
+	case scoring.CommandReview:
+		if err := json.Unmarshal(msg, &cmd.Review); err != nil {
+			return cmd, fmt.Errorf("invalid review payload: %v", err)
+		}
+	}
+	return cmd, nil
+}
+
+// applyScorerCommand decodes a raw scorer message and applies it to the match
+// state through the rules engine, bumping the state version. It is used both by
+// the live scorer loop and when replaying the match event store, so it must only
+// depend on the message and the supplied timestamp.
+func applyScorerCommand(match *models.EnhancedStatsMessage, cmdType string, msg []byte, at time.Time) ([]scoring.Event, error) {
+	cmd, err := decodeScorerCommand(cmdType, msg, at)
+	if err != nil {
+		return nil, err
+	}
+	next, events, err := scoring.Apply(*match, cmd)
+	if err != nil {
+		return nil, err
+	}
+	// State replacements carry whatever version the client sent; the server owns it
+	next.Data.Version = match.Data.Version + 1
+	*match = next
+	return events, nil
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+	"github.com/sirupsen/logrus"
+)
+
+// supportedScorerProtocols are the scorer socket versions this server speaks, newest first
+var supportedScorerProtocols = []int{models.WSProtocolCurrent, models.WSProtocolLegacy}
+
+// scorerSession is one scorer socket and the protocol version negotiated for it.
+// Everything the scorer loop sends goes through it, so the loop never needs to
+// know which format the page speaks.
+type scorerSession struct {
+	conn    *websocket.Conn
+	client  *roomClient // set once the scorer has joined the room; writes then go through its queue
+	version int
+	role    string // one of the models.ScorerRole values
+	device  string
+}
+
+// scorerCommand is a command read from a scorer socket. Body is in the untyped
+// version 1 form whatever protocol it arrived in; that is what the rules engine
+// decodes and what the event store records, so replays do not depend on it.
+type scorerCommand struct {
+	Type string
+	Meta scorerCommandMeta
+	Body []byte
+}
+
+// commandRejectedError marks errors from the rules engine or a malformed command
+// body, as opposed to failures reading or writing the match state
+type commandRejectedError struct {
+	err error
+}
+
+func (e commandRejectedError) Error() string { return e.err.Error() }
+func (e commandRejectedError) Unwrap() error { return e.err }
+
+// negotiateScorerJoin reads the join message and works out the protocol version.
+// A join without "v" is a version 1 page. A version 2 join lists the versions the
+// page speaks and gets the highest one both sides know.
+func negotiateScorerJoin(c *websocket.Conn, joinMsg []byte) (*scorerSession, string, error) {
+	var probe struct {
+		V       int    `json:"v"`
+		Type    string `json:"type"`
+		MatchID string `json:"matchId"`
+	}
+	if err := json.Unmarshal(joinMsg, &probe); err != nil || probe.Type != models.WSTypeJoin {
+		return nil, "", fmt.Errorf("first message must be a join")
+	}
+	if probe.V == 0 {
+		if probe.MatchID == "" {
+			return nil, "", fmt.Errorf("join is missing matchId")
+		}
+		return &scorerSession{conn: c, version: models.WSProtocolLegacy, role: models.ScorerRoleChief}, probe.MatchID, nil
+	}
+
+	var env models.WSEnvelope
+	var join models.WSJoinPayload
+	if err := json.Unmarshal(joinMsg, &env); err != nil || json.Unmarshal(env.Payload, &join) != nil || join.MatchID == "" {
+		return nil, "", fmt.Errorf("join is missing matchId")
+	}
+	switch join.Role {
+	case "":
+		join.Role = models.ScorerRoleChief
+	case models.ScorerRoleChief, models.ScorerRoleAssistant, models.ScorerRoleMirror:
+	default:
+		return nil, "", fmt.Errorf("unknown scorer role %q", join.Role)
+	}
+	offered := join.Versions
+	if len(offered) == 0 {
+		offered = []int{env.V}
+	}
+	for _, v := range supportedScorerProtocols {
+		for _, o := range offered {
+			if v == o {
+				s := &scorerSession{conn: c, version: v, role: join.Role, device: join.Device}
+				if v >= models.WSProtocolCurrent {
+					s.send(models.WSTypeWelcome, "", models.WSWelcomePayload{Version: v, Commands: scoring.CommandTypes, Events: scoring.EventTypes})
+				}
+				return s, join.MatchID, nil
+			}
+		}
+	}
+	return nil, "", errUnsupportedProtocol
+}
+
+var errUnsupportedProtocol = errors.New("no common protocol version; this server speaks versions 1 and 2")
+
+// rejectScorerJoin answers a join that could not be accepted in the form the page is most likely to understand
+func rejectScorerJoin(c *websocket.Conn, joinMsg []byte, err error) {
+	var probe struct {
+		V int `json:"v"`
+	}
+	_ = json.Unmarshal(joinMsg, &probe)
+	if probe.V == 0 {
+		// Version 1 pages expect to be asked to join again
+		writeJSON(c, map[string]string{"type": "requestJoin"})
+		return
+	}
+	code := models.WSErrBadMessage
+	if errors.Is(err, errUnsupportedProtocol) {
+		code = models.WSErrUnsupportedVersion
+	}
+	s := &scorerSession{conn: c, version: models.WSProtocolCurrent}
+	s.sendError("", code, err.Error())
+}
+
+// readCommand parses a message from the scorer, or says why it cannot be accepted
+func (s *scorerSession) readCommand(msg []byte) (scorerCommand, *models.WSErrorPayload) {
+	if s.version == models.WSProtocolLegacy {
+		return scorerCommand{Type: classifyScorerCommand(msg), Meta: parseScorerCommandMeta(msg), Body: msg}, nil
+	}
+
+	var env models.WSEnvelope
+	if err := json.Unmarshal(msg, &env); err != nil {
+		return scorerCommand{}, &models.WSErrorPayload{Code: models.WSErrBadMessage, Message: err.Error()}
+	}
+	return commandFromEnvelope(env)
+}
+
+// commandFromEnvelope turns a version 2 command envelope into a scorer command.
+// It is the one gate on command types for everything but version 1 sockets: only
+// scoring.CommandTypes pass, so the legacy fullState overwrite never does, whether
+// it comes over a version 2 socket, in a batch, as a proposal or over REST.
+func commandFromEnvelope(env models.WSEnvelope) (scorerCommand, *models.WSErrorPayload) {
+	cmd := scorerCommand{Type: env.Type, Meta: scorerCommandMeta{CommandID: env.ID, BaseVersion: env.BaseVersion}}
+	if !isScorerCommandType(env.Type) {
+		return cmd, &models.WSErrorPayload{Code: models.WSErrUnknownType, Message: fmt.Sprintf("unknown command type %q", env.Type)}
+	}
+	body := []byte(env.Payload)
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	if env.Type == scoring.CommandLobbyTouch {
+		// Version 1 nests lobby touches under "data"
+		wrapped, err := json.Marshal(fiber.Map{"type": env.Type, "data": env.Payload})
+		if err != nil {
+			return cmd, &models.WSErrorPayload{Code: models.WSErrBadMessage, Message: err.Error()}
+		}
+		body = wrapped
+	}
+	cmd.Body = body
+	return cmd, nil
+}
+
+// messageType reads the type of a version 2 message, so control messages can be
+// told from commands. Version 1 sessions only send commands.
+func (s *scorerSession) messageType(msg []byte) string {
+	if s.version < models.WSProtocolCurrent {
+		return ""
+	}
+	var probe struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(msg, &probe)
+	return probe.Type
+}
+
+func isScorerCommandType(t string) bool {
+	for _, known := range scoring.CommandTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// write sends v to the scorer
+func (s *scorerSession) write(v interface{}) {
+	if s.client == nil {
+		writeJSON(s.conn, v)
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		logrus.Error("Error:", "scorerSession.write:", " Failed to marshal socket message: %v", err)
+		return
+	}
+	s.client.enqueue(data)
+}
+
+// send writes a version 2 envelope; version 1 sessions have their own message shapes
+func (s *scorerSession) send(msgType, id string, payload interface{}) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		logrus.Error("Error:", "scorerSession.send:", " Failed to marshal %s payload: %v", msgType, err)
+		return
+	}
+	s.write(models.WSEnvelope{V: s.version, Type: msgType, ID: id, Payload: raw})
+}
+
+func (s *scorerSession) sendState(state models.EnhancedStatsMessage) {
+	if s.version == models.WSProtocolLegacy {
+		s.write(state)
+		return
+	}
+	s.send(models.WSTypeState, "", state)
+}
+
+func (s *scorerSession) requestInit() {
+	if s.version == models.WSProtocolLegacy {
+		s.write(map[string]string{"type": models.WSTypeRequestInit})
+		return
+	}
+	s.send(models.WSTypeRequestInit, "", struct{}{})
+}
+
+// sendAck confirms a command. Version 2 acks also carry the events it produced.
+func (s *scorerSession) sendAck(commandID string, version int64, duplicate bool, events []scoring.Event) {
+	if s.version == models.WSProtocolLegacy {
+		if commandID != "" {
+			s.write(fiber.Map{"type": "commandAck", "commandId": commandID, "version": version, "duplicate": duplicate})
+		}
+		return
+	}
+	if events == nil {
+		events = []scoring.Event{}
+	}
+	s.send(models.WSTypeAck, commandID, fiber.Map{"version": version, "duplicate": duplicate, "events": events})
+}
+
+func (s *scorerSession) sendConflict(commandID string, current models.EnhancedStatsMessage) {
+	const message = "match state has changed since this command was issued"
+	if s.version == models.WSProtocolLegacy {
+		s.write(fiber.Map{"type": "conflict", "commandId": commandID, "error": message, "version": current.Data.Version, "state": current})
+		return
+	}
+	s.send(models.WSTypeConflict, commandID, fiber.Map{"message": message, "version": current.Data.Version, "state": current})
+}
+
+func (s *scorerSession) sendError(commandID, code, message string) {
+	if s.version == models.WSProtocolLegacy {
+		errMsg := map[string]string{"error": message}
+		if commandID != "" {
+			errMsg["commandId"] = commandID
+		}
+		s.write(errMsg)
+		return
+	}
+	s.send(models.WSTypeError, commandID, models.WSErrorPayload{Code: code, Message: message})
+}
+
+// scorerErrorCode classifies an error from applying a scorer command
+func scorerErrorCode(err error) string {
+	var rejected commandRejectedError
+	switch {
+	case errors.Is(err, errStateNotInitialized):
+		return models.WSErrNotInitialized
+	case errors.As(err, &rejected):
+		return models.WSErrCommandRejected
+	}
+	return models.WSErrInternal
+}
+
+func writeJSON(c *websocket.Conn, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logrus.Error("Error:", "writeJSON:", " Failed to marshal socket message: %v", err)
+		return
+	}
+	_ = c.WriteMessage(websocket.TextMessage, data)
+}
@@ -0,0 +1,526 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A match's scorer side is shared across instances through Redis: the roster of
+// connected chief, assistant and mirror sockets, any lock handoff in progress and
+// the proposals waiting for the chief. Changes to any of it are pushed to every
+// scorer-side socket as a scorerRoom message.
+
+// scorerHandoffTTL is how long a handoff waits for the named scorer to join. The
+// lock is reserved for them meanwhile and lapses with the handoff.
+const scorerHandoffTTL = 2 * time.Minute
+
+// scorerHandoffPrefix marks a scorer lock reserved for a handoff
+const scorerHandoffPrefix = "handoff:"
+
+var (
+	errNotLockHolder        = errors.New("only the scorer holding the lock can hand it off")
+	errInvalidHandoffTarget = errors.New("handoff needs a userId or sessionId; a device alone is not enough")
+	errProposalNotFound     = errors.New("proposal not found or already resolved")
+)
+
+// scorerRosterKey is a hash of connection ID to models.ScorerConnection
+func scorerRosterKey(matchID string) string {
+	return "scorerRoster:" + matchID
+}
+
+func scorerHandoffKey(matchID string) string {
+	return "scorerHandoff:" + matchID
+}
+
+// scorerProposalsKey is a hash of proposal ID to models.ScorerProposal
+func scorerProposalsKey(matchID string) string {
+	return "scorerProposals:" + matchID
+}
+
+// scorerLockOwner is how a scorer is named in the scorer lock
+func scorerLockOwner(actor models.MatchEventActor) string {
+	return actor.UserID + ":" + actor.SessionID
+}
+
+func newScorerConnectionID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return instanceID + ":" + hex.EncodeToString(b)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// reserveForHandoff moves the lock from its holder to a handoff marker
+var reserveForHandoff = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// claimHandoff gives a lock reserved for a handoff to the scorer it names
+var claimHandoff = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+redis.call('DEL', KEYS[2])
+return 1
+`)
+
+func registerScorerConnection(matchID string, info models.ScorerConnection) {
+	ctx := context.Background()
+	data, _ := json.Marshal(info)
+	pipe := redisImpl.RedisClient.TxPipeline()
+	pipe.HSet(ctx, scorerRosterKey(matchID), info.ID, data)
+	pipe.Expire(ctx, scorerRosterKey(matchID), presenceTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logrus.Warnf("scorer roster update failed for match %s: %v", matchID, err)
+	}
+}
+
+func unregisterScorerConnection(matchID, connID string) {
+	if err := redisImpl.RedisClient.HDel(context.Background(), scorerRosterKey(matchID), connID).Err(); err != nil {
+		logrus.Warnf("scorer roster update failed for match %s: %v", matchID, err)
+	}
+}
+
+// refreshScorerRoster marks this instance's scorer-side sockets as still there.
+// Entries from an instance that stops refreshing drop out of the room state.
+func refreshScorerRoster(matchID string, conns []models.ScorerConnection) {
+	ctx := context.Background()
+	now := time.Now()
+	values := make([]interface{}, 0, 2*len(conns))
+	for _, conn := range conns {
+		conn.SeenAt = now
+		data, _ := json.Marshal(conn)
+		values = append(values, conn.ID, data)
+	}
+	if err := redisImpl.RedisClient.HSet(ctx, scorerRosterKey(matchID), values...).Err(); err != nil {
+		logrus.Warnf("scorer roster refresh failed for match %s: %v", matchID, err)
+	}
+}
+
+// loadScorerRoomState reads the scorer side of a match from Redis
+func loadScorerRoomState(ctx context.Context, matchID string) (models.ScorerRoomState, error) {
+	state := models.ScorerRoomState{MatchID: matchID, Connections: []models.ScorerConnection{}, Proposals: []models.ScorerProposal{}}
+
+	roster, err := redisImpl.RedisClient.HGetAll(ctx, scorerRosterKey(matchID)).Result()
+	if err != nil {
+		return state, err
+	}
+	for _, raw := range roster {
+		var conn models.ScorerConnection
+		if json.Unmarshal([]byte(raw), &conn) == nil && time.Since(conn.SeenAt) <= presenceStale {
+			state.Connections = append(state.Connections, conn)
+		}
+	}
+	sort.Slice(state.Connections, func(i, j int) bool {
+		return state.Connections[i].ConnectedAt.Before(state.Connections[j].ConnectedAt)
+	})
+
+	holder, err := redisImpl.RedisClient.Get(ctx, scorerLockKey(matchID)).Result()
+	if err != nil && err != redis.Nil {
+		return state, err
+	}
+	if holder != "" && !strings.HasPrefix(holder, scorerHandoffPrefix) {
+		userID, sessionID, _ := strings.Cut(holder, ":")
+		state.LockHolder = &models.MatchEventActor{UserID: userID, SessionID: sessionID}
+	}
+
+	var handoff models.ScorerHandoff
+	if err := redisImpl.GetRedisKey(scorerHandoffKey(matchID), &handoff); err == nil {
+		state.Handoff = &handoff
+	}
+
+	proposals, err := redisImpl.RedisClient.HGetAll(ctx, scorerProposalsKey(matchID)).Result()
+	if err != nil {
+		return state, err
+	}
+	for _, raw := range proposals {
+		var p models.ScorerProposal
+		if json.Unmarshal([]byte(raw), &p) == nil {
+			state.Proposals = append(state.Proposals, p)
+		}
+	}
+	sort.Slice(state.Proposals, func(i, j int) bool {
+		return state.Proposals[i].ProposedAt.Before(state.Proposals[j].ProposedAt)
+	})
+	return state, nil
+}
+
+// scorerEnvelope is a version 2 message for scorer-side sockets
+func scorerEnvelope(msgType, id string, payload interface{}) ([]byte, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(models.WSEnvelope{V: models.WSProtocolCurrent, Type: msgType, ID: id, Payload: raw})
+}
+
+// publishToScorers sends a version 2 message to the match's scorer-side sockets
+// with one of roles, on every instance. No roles means all of them.
+func publishToScorers(matchID string, roles []string, msgType, id string, payload interface{}) {
+	data, err := scorerEnvelope(msgType, id, payload)
+	if err != nil {
+		logrus.Error("Error:", "publishToScorers:", " Failed to marshal %s message: %v", msgType, err)
+		return
+	}
+	publishRoomMessage(matchID, roomMessage{Kind: roomMessageScorers, Data: data, Roles: roles})
+}
+
+// publishScorerRoomState pushes the current scorer room state to the scorer side
+func publishScorerRoomState(matchID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	state, err := loadScorerRoomState(ctx, matchID)
+	if err != nil {
+		logrus.Warnf("scorer room state failed for match %s: %v", matchID, err)
+		return
+	}
+	publishToScorers(matchID, nil, models.WSTypeScorerRoom, "", state)
+}
+
+// startScorerHandoff reserves the lock for the scorer the chief names and closes
+// the chief's socket. The named scorer gets the lock when they join as chief.
+func startScorerHandoff(matchID string, from models.MatchEventActor, to models.ScorerHandoffTarget) (models.ScorerHandoff, error) {
+	if !to.NamesScorer() {
+		return models.ScorerHandoff{}, errInvalidHandoffTarget
+	}
+	now := time.Now()
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	handoff := models.ScorerHandoff{
+		ID:        hex.EncodeToString(b),
+		From:      from,
+		To:        to,
+		CreatedAt: now,
+		ExpiresAt: now.Add(scorerHandoffTTL),
+	}
+
+	ctx := context.Background()
+	owner := scorerLockOwner(from)
+	reserved, err := reserveForHandoff.Run(ctx, redisImpl.RedisClient, []string{scorerLockKey(matchID)},
+		owner, scorerHandoffPrefix+handoff.ID, scorerHandoffTTL.Milliseconds()).Int()
+	if err != nil {
+		return handoff, err
+	}
+	if reserved == 0 {
+		return handoff, errNotLockHolder
+	}
+	data, _ := json.Marshal(handoff)
+	if err := redisImpl.RedisClient.Set(ctx, scorerHandoffKey(matchID), data, scorerHandoffTTL).Err(); err != nil {
+		return handoff, err
+	}
+
+	if msg, err := scorerEnvelope(models.WSTypeHandedOff, "", handoff); err == nil {
+		publishRoomMessage(matchID, roomMessage{Kind: roomMessageHandoff, Data: msg, Owner: owner})
+	}
+	publishScorerRoomState(matchID)
+	return handoff, nil
+}
+
+// claimScorerHandoff gives the lock to a joining chief if a handoff names them
+func claimScorerHandoff(matchID string, actor models.MatchEventActor, device string) (bool, error) {
+	var handoff models.ScorerHandoff
+	if err := redisImpl.GetRedisKey(scorerHandoffKey(matchID), &handoff); err != nil {
+		if err == redisImpl.RedisNull {
+			return false, nil
+		}
+		return false, err
+	}
+	if !handoff.To.Matches(actor.UserID, actor.SessionID, device) {
+		return false, nil
+	}
+	claimed, err := claimHandoff.Run(context.Background(), redisImpl.RedisClient,
+		[]string{scorerLockKey(matchID), scorerHandoffKey(matchID)},
+		scorerHandoffPrefix+handoff.ID, scorerLockOwner(actor), scorerLockTTL.Milliseconds()).Int()
+	return claimed == 1, err
+}
+
+// proposeScorerCommand stores an assistant's command for the chief to confirm.
+// Commands the engine could never accept are refused at once. A proposal that
+// was already confirmed is reported as such rather than proposed again.
+func proposeScorerCommand(matchID string, env models.WSEnvelope, proposer models.MatchEventActor) (*models.WSErrorPayload, bool) {
+	if _, bad := commandFromEnvelope(env); bad != nil {
+		return bad, false
+	}
+	if env.ID == "" {
+		return &models.WSErrorPayload{Code: models.WSErrBadMessage, Message: "proposals need an id"}, false
+	}
+
+	ctx := context.Background()
+	applied, err := redisImpl.RedisClient.HExists(ctx, appliedCommandsKey(matchID), env.ID).Result()
+	if err != nil {
+		return &models.WSErrorPayload{Code: models.WSErrInternal, Message: err.Error()}, false
+	}
+	if applied {
+		return nil, true
+	}
+
+	proposal := models.ScorerProposal{ID: env.ID, Type: env.Type, Command: env, ProposedBy: proposer, ProposedAt: time.Now()}
+	data, _ := json.Marshal(proposal)
+	created, err := redisImpl.RedisClient.HSetNX(ctx, scorerProposalsKey(matchID), env.ID, data).Result()
+	if err != nil {
+		return &models.WSErrorPayload{Code: models.WSErrInternal, Message: err.Error()}, false
+	}
+	redisImpl.RedisClient.Expire(ctx, scorerProposalsKey(matchID), appliedCommandsTTL)
+	if created {
+		publishToScorers(matchID, nil, models.WSTypeProposal, proposal.ID, proposal)
+		publishScorerRoomState(matchID)
+	}
+	return nil, false
+}
+
+// takeScorerProposal removes a pending proposal, so only one verdict can act on it
+func takeScorerProposal(ctx context.Context, matchID, proposalID string) (models.ScorerProposal, error) {
+	var proposal models.ScorerProposal
+	raw, err := redisImpl.RedisClient.HGet(ctx, scorerProposalsKey(matchID), proposalID).Result()
+	if err == redis.Nil {
+		return proposal, errProposalNotFound
+	}
+	if err != nil {
+		return proposal, err
+	}
+	removed, err := redisImpl.RedisClient.HDel(ctx, scorerProposalsKey(matchID), proposalID).Result()
+	if err != nil {
+		return proposal, err
+	}
+	if removed == 0 {
+		return proposal, errProposalNotFound
+	}
+	return proposal, json.Unmarshal([]byte(raw), &proposal)
+}
+
+// resolveScorerProposal carries out the chief's verdict on a proposal. A confirmed
+// proposal is applied as the chief's own command, with the assistant recorded as
+// its proposer, on the current state; the chief's confirmation stands in for the
+// base version check.
+func resolveScorerProposal(matchID string, verdict models.WSProposalVerdictPayload, confirm bool, chief models.MatchEventActor) (models.WSProposalResolvedPayload, *scorerCommandResult, error) {
+	resolved := models.WSProposalResolvedPayload{ProposalID: verdict.ProposalID, Status: models.ProposalRejected, Reason: verdict.Reason}
+	proposal, err := takeScorerProposal(context.Background(), matchID, verdict.ProposalID)
+	if err != nil {
+		return resolved, nil, err
+	}
+
+	var result *scorerCommandResult
+	if confirm {
+		cmd, bad := commandFromEnvelope(proposal.Command)
+		if bad != nil {
+			resolved.Reason = bad.Message
+		} else {
+			cmd.Meta.BaseVersion = nil
+			actor := chief
+			actor.ProposedBy = &proposal.ProposedBy
+			applied, err := commitScorerCommand(matchID, cmd, actor, time.Now())
+			if err != nil && scorerErrorCode(err) == models.WSErrInternal {
+				// Put it back so the chief can try again
+				data, _ := json.Marshal(proposal)
+				redisImpl.RedisClient.HSet(context.Background(), scorerProposalsKey(matchID), proposal.ID, data)
+				return resolved, nil, err
+			}
+			if err != nil {
+				resolved.Reason = err.Error()
+			} else {
+				resolved.Status = models.ProposalConfirmed
+				resolved.Reason = ""
+				resolved.Version = applied.Match.Data.Version
+				resolved.Events, _ = json.Marshal(applied.Events)
+				result = &applied
+			}
+		}
+	}
+
+	publishToScorers(matchID, nil, models.WSTypeProposalResolved, proposal.ID, resolved)
+	publishScorerRoomState(matchID)
+	return resolved, result, nil
+}
+
+// mayJoinScorerSide reports whether a user may join a match's scorer side as an
+// assistant or mirror: they must organize the match's event, or be the chief
+// scorer joining from another device
+func mayJoinScorerSide(matchID string, actor models.MatchEventActor) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	holder, err := redisImpl.RedisClient.Get(ctx, scorerLockKey(matchID)).Result()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+	if holder != "" && !strings.HasPrefix(holder, scorerHandoffPrefix) {
+		if userID, _, _ := strings.Cut(holder, ":"); userID == actor.UserID {
+			return true, nil
+		}
+	}
+	userID, err := primitive.ObjectIDFromHex(actor.UserID)
+	if err != nil {
+		return false, nil
+	}
+	return organizesMatch(ctx, matchID, userID)
+}
+
+// GetScorerRoomHandler returns who is on a match's scorer side to the match's
+// organizer. Session IDs are left out, since a session ID is enough to act as
+// that scorer.
+func GetScorerRoomHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if ok, resp := requireMatchOrganizer(c, ctx, matchID, "GetScorerRoomHandler"); !ok {
+		return resp
+	}
+	state, err := loadScorerRoomState(ctx, matchID)
+	if err != nil {
+		logrus.Error("Error:", "GetScorerRoomHandler:", " Failed to load scorer room for match %s: %v", matchID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load scorer room"})
+	}
+	return c.JSON(withoutSessionIDs(state))
+}
+
+// withoutSessionIDs blanks every session ID in a scorer room state
+func withoutSessionIDs(state models.ScorerRoomState) models.ScorerRoomState {
+	if state.LockHolder != nil {
+		holder := *state.LockHolder
+		holder.SessionID = ""
+		state.LockHolder = &holder
+	}
+	conns := make([]models.ScorerConnection, len(state.Connections))
+	for i, conn := range state.Connections {
+		conn.SessionID = ""
+		conns[i] = conn
+	}
+	state.Connections = conns
+	if state.Handoff != nil {
+		handoff := *state.Handoff
+		handoff.From.SessionID = ""
+		handoff.To.SessionID = ""
+		state.Handoff = &handoff
+	}
+	proposals := make([]models.ScorerProposal, len(state.Proposals))
+	for i, p := range state.Proposals {
+		p.ProposedBy.SessionID = ""
+		proposals[i] = p
+	}
+	state.Proposals = proposals
+	return state
+}
+
+// ScorerHandoffHandler hands the scorer lock from the caller, who must hold it,
+// to the user, session or device in the body. The caller's scorer socket is closed.
+func ScorerHandoffHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+	var to models.ScorerHandoffTarget
+	if err := c.BodyParser(&to); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	from := models.MatchEventActor{UserID: fmt.Sprint(c.Locals("user_id")), SessionID: fmt.Sprint(c.Locals("session_id"))}
+	handoff, err := startScorerHandoff(matchID, from, to)
+	switch {
+	case errors.Is(err, errInvalidHandoffTarget):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, errNotLockHolder):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		logrus.Error("Error:", "ScorerHandoffHandler:", " Failed to hand off match %s: %v", matchID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to hand off scorer lock"})
+	}
+	return c.JSON(handoff)
+}
+
+// handleNonChiefMessage answers a message from an assistant or mirror. An
+// assistant's commands become proposals; mirrors cannot send anything.
+func (s *scorerSession) handleNonChiefMessage(matchID string, msg []byte, actor models.MatchEventActor) {
+	var env models.WSEnvelope
+	if err := json.Unmarshal(msg, &env); err != nil {
+		s.sendError("", models.WSErrBadMessage, err.Error())
+		return
+	}
+	if s.role == models.ScorerRoleMirror {
+		s.sendError(env.ID, models.WSErrReadOnly, "scorer mirrors are read-only")
+		return
+	}
+	switch env.Type {
+	case models.WSTypeBatch, models.WSTypeConfirmProposal, models.WSTypeRejectProposal, models.WSTypeHandoff:
+		s.sendError(env.ID, models.WSErrNotChief, fmt.Sprintf("only the chief scorer can send %s", env.Type))
+		return
+	}
+
+	bad, applied := proposeScorerCommand(matchID, env, actor)
+	if bad != nil {
+		s.sendError(env.ID, bad.Code, bad.Message)
+		return
+	}
+	if applied {
+		s.send(models.WSTypeProposalResolved, env.ID, models.WSProposalResolvedPayload{ProposalID: env.ID, Status: models.ProposalConfirmed})
+	}
+}
+
+// resolveProposal carries out the chief's confirmProposal or rejectProposal
+func (s *scorerSession) resolveProposal(matchID string, msg []byte, chief models.MatchEventActor) {
+	var env models.WSEnvelope
+	var verdict models.WSProposalVerdictPayload
+	if err := json.Unmarshal(msg, &env); err != nil || json.Unmarshal(env.Payload, &verdict) != nil || verdict.ProposalID == "" {
+		s.sendError(env.ID, models.WSErrBadMessage, "payload must name a proposalId")
+		return
+	}
+	_, result, err := resolveScorerProposal(matchID, verdict, env.Type == models.WSTypeConfirmProposal, chief)
+	if errors.Is(err, errProposalNotFound) {
+		s.sendError(env.ID, models.WSErrBadMessage, err.Error())
+		return
+	}
+	if err != nil {
+		s.sendError(env.ID, models.WSErrInternal, err.Error())
+		return
+	}
+	if result != nil {
+		s.sendState(result.Match)
+		s.sendAck(env.ID, result.Match.Data.Version, false, result.Events)
+	}
+}
+
+// handOff hands the lock to the scorer named in the payload. On success this
+// socket is closed by the handoff room message.
+func (s *scorerSession) handOff(matchID string, msg []byte, chief models.MatchEventActor) {
+	var env models.WSEnvelope
+	var to models.ScorerHandoffTarget
+	if err := json.Unmarshal(msg, &env); err != nil || json.Unmarshal(env.Payload, &to) != nil {
+		s.sendError(env.ID, models.WSErrBadMessage, "payload must name a userId, sessionId or device")
+		return
+	}
+	_, err := startScorerHandoff(matchID, chief, to)
+	switch {
+	case errors.Is(err, errInvalidHandoffTarget):
+		s.sendError(env.ID, models.WSErrBadMessage, err.Error())
+	case errors.Is(err, errNotLockHolder):
+		s.sendError(env.ID, models.WSErrScorerLocked, err.Error())
+	case err != nil:
+		s.sendError(env.ID, models.WSErrInternal, err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+	"github.com/redis/go-redis/v9"
+)
+
+// appliedCommandsTTL keeps the record of applied command IDs long enough to cover
+// any reconnect, while still cleaning up after abandoned matches.
+const appliedCommandsTTL = 48 * time.Hour
+
+// maxStateWriteRetries bounds how often a command is retried when another writer
+// changes the match state between our read and write.
+const maxStateWriteRetries = 5
+
+var errStateNotInitialized = errors.New("server: game state not initialized. Please send initial state")
+
+func gameStatsKey(matchID string) string {
+	return "gameStats:" + matchID
+}
+
+// appliedCommandsKey maps each applied command ID of a match to the state version it produced
+func appliedCommandsKey(matchID string) string {
+	return "scorerCommands:" + matchID
+}
+
+// scorerCommandMeta is the envelope every scorer command may carry. CommandID is
+// generated by the client and reused when a command is resent; BaseVersion is the
+// state version the scorer was looking at when issuing it.
+type scorerCommandMeta struct {
+	CommandID   string `json:"commandId"`
+	BaseVersion *int64 `json:"baseVersion"`
+}
+
+func parseScorerCommandMeta(msg []byte) scorerCommandMeta {
+	var meta scorerCommandMeta
+	_ = json.Unmarshal(msg, &meta)
+	return meta
+}
+
+// scorerCommandResult is the outcome of applying a scorer command to the live state.
+// For duplicates and conflicts nothing was written and Match is the current state.
+type scorerCommandResult struct {
+	Match     models.EnhancedStatsMessage
+	Previous  models.EnhancedStatsMessage // the state the command was applied to
+	Events    []scoring.Event
+	Duplicate bool // the command ID was applied before
+	Conflict  bool // the command was based on an older state version
+}
+
+// applyScorerCommandAtomically applies a scorer command to the match state in Redis.
+// The read, the version check and the write happen in one WATCH transaction, so
+// two writers can never both build on the same version; the loser is retried.
+// Legacy full state overwrites must say which version they replace.
+func applyScorerCommandAtomically(matchID, cmdType string, msg []byte, meta scorerCommandMeta, at time.Time) (scorerCommandResult, error) {
+	ctx := context.Background()
+	stateKey := gameStatsKey(matchID)
+	commandsKey := appliedCommandsKey(matchID)
+
+	var result scorerCommandResult
+	txn := func(tx *redis.Tx) error {
+		result = scorerCommandResult{}
+
+		var current models.EnhancedStatsMessage
+		raw, err := tx.Get(ctx, stateKey).Bytes()
+		switch {
+		case err == redis.Nil:
+			if commandNeedsState(cmdType) {
+				return errStateNotInitialized
+			}
+		case err != nil:
+			return err
+		default:
+			if err := json.Unmarshal(raw, &current); err != nil {
+				return err
+			}
+		}
+
+		if meta.CommandID != "" {
+			_, err := tx.HGet(ctx, commandsKey, meta.CommandID).Result()
+			if err == nil {
+				result.Match = current
+				result.Duplicate = true
+				return nil
+			}
+			if err != redis.Nil {
+				return err
+			}
+		}
+
+		stale := meta.BaseVersion != nil && *meta.BaseVersion != current.Data.Version
+		if cmdType == scoring.CommandFullState && meta.BaseVersion == nil {
+			stale = true
+		}
+		if stale {
+			result.Match = current
+			result.Conflict = true
+			return nil
+		}
+
+		next := current
+		events, err := applyScorerCommand(&next, cmdType, msg, at)
+		if err != nil {
+			return commandRejectedError{err}
+		}
+		data, err := json.Marshal(next)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, stateKey, data, 0)
+			if meta.CommandID != "" {
+				pipe.HSet(ctx, commandsKey, meta.CommandID, next.Data.Version)
+				pipe.Expire(ctx, commandsKey, appliedCommandsTTL)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		result.Previous = current
+		result.Match = next
+		result.Events = events
+		return nil
+	}
+
+	for attempt := 0; attempt < maxStateWriteRetries; attempt++ {
+		err := redisImpl.RedisClient.Watch(ctx, txn, stateKey, commandsKey)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		return result, err
+	}
+	return result, errors.New("server: match state is changing too quickly, please retry")
+}
+
+// replaceMatchStateAtomically stores a rebuilt match state in place of the live
+// one. The stored version is one past the current state's, so scorers holding
+// the old state see a conflict rather than overwriting the rebuild. The caller
+// records the rebuild as an event at that version.
+func replaceMatchStateAtomically(matchID string, state models.EnhancedStatsMessage) (models.EnhancedStatsMessage, error) {
+	return casMatchState(matchID, func(current *models.EnhancedStatsMessage) (models.EnhancedStatsMessage, bool) {
+		if current != nil {
+			state.Data.Version = current.Data.Version + 1
+		} else {
+			state.Data.Version++
+		}
+		return state, true
+	})
+}
+
+// restoreMatchStateAtomically stores a state recovered from a snapshot or the
+// event store, unless another instance restored one first. It returns the state
+// that ends up stored.
+func restoreMatchStateAtomically(matchID string, state models.EnhancedStatsMessage) (models.EnhancedStatsMessage, error) {
+	return casMatchState(matchID, func(current *models.EnhancedStatsMessage) (models.EnhancedStatsMessage, bool) {
+		if current != nil {
+			return *current, false
+		}
+		return state, true
+	})
+}
+
+// casMatchState reads the stored match state, nil when there is none, and writes
+// what update returns in the same WATCH transaction, unless update says not to.
+// It returns the state stored afterwards.
+func casMatchState(matchID string, update func(current *models.EnhancedStatsMessage) (models.EnhancedStatsMessage, bool)) (models.EnhancedStatsMessage, error) {
+	ctx := context.Background()
+	stateKey := gameStatsKey(matchID)
+
+	var stored models.EnhancedStatsMessage
+	txn := func(tx *redis.Tx) error {
+		var current *models.EnhancedStatsMessage
+		raw, err := tx.Get(ctx, stateKey).Bytes()
+		switch {
+		case err == redis.Nil:
+		case err != nil:
+			return err
+		default:
+			current = &models.EnhancedStatsMessage{}
+			if err := json.Unmarshal(raw, current); err != nil {
+				return err
+			}
+		}
+
+		next, write := update(current)
+		stored = next
+		if !write {
+			return nil
+		}
+		data, err := json.Marshal(next)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, stateKey, data, 0)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxStateWriteRetries; attempt++ {
+		err := redisImpl.RedisClient.Watch(ctx, txn, stateKey)
+		if err == redis.TxFailedErr {
+			continue
+		}
+		return stored, err
+	}
+	return stored, errors.New("server: match state is changing too quickly, please retry")
+}
@@ -338,6 +338,7 @@ func StartTournamentMatchHandler(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update fixture"})
 	}
+	publishFixtureStatus(eventChannelTournament, tournament.ID.Hex(), fixture.ID.Hex(), matchID.Hex(), models.FixtureStatusOngoing)
 
 	// Return match ID and fixture info for player selection
 	return c.JSON(fiber.Map{
@@ -457,6 +458,7 @@ func RestartTournamentMatchHandler(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to restart fixture"})
 	}
+	publishFixtureStatus(eventChannelTournament, tournament.ID.Hex(), fixture.ID.Hex(), newMatchID.Hex(), models.FixtureStatusOngoing)
 
 	return c.JSON(fiber.Map{
 		"matchId": newMatchID.Hex(),
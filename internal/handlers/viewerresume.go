@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// viewerDeltaBufferSize is how many recent deltas a match keeps for viewers that
+// reconnect; anyone further behind gets a full snapshot.
+const viewerDeltaBufferSize = 200
+
+const viewerDeltaBufferTTL = 48 * time.Hour
+
+// viewerDeltasKey holds a match's recent viewer deltas, oldest first. It lives in
+// Redis rather than the room so resumes work after the room goroutine has gone.
+func viewerDeltasKey(matchID string) string {
+	return "viewerDeltas:" + matchID
+}
+
+// bufferViewerDelta appends a broadcast delta to the match's ring buffer
+func bufferViewerDelta(matchID string, data []byte) {
+	ctx := context.Background()
+	key := viewerDeltasKey(matchID)
+	_, err := redisImpl.RedisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, key, data)
+		pipe.LTrim(ctx, key, -viewerDeltaBufferSize, -1)
+		pipe.Expire(ctx, key, viewerDeltaBufferTTL)
+		return nil
+	})
+	if err != nil {
+		logrus.Warnf("viewer delta buffer update failed for match %s: %v", matchID, err)
+	}
+}
+
+// resetViewerDeltas empties the ring buffer. Used when a full snapshot replaces the
+// state, since deltas from before it can no longer be applied on top of it.
+func resetViewerDeltas(matchID string) {
+	_ = redisImpl.DeleteRedisKey(viewerDeltasKey(matchID))
+}
+
+// missedViewerDeltas returns the buffered deltas a viewer at lastSeq needs to catch
+// up to currentSeq. It reports false when the buffer no longer reaches back that
+// far, in which case the viewer needs a snapshot.
+func missedViewerDeltas(matchID string, lastSeq, currentSeq int64) ([][]byte, bool) {
+	if lastSeq == currentSeq {
+		return nil, true
+	}
+	if lastSeq > currentSeq {
+		return nil, false
+	}
+	raw, err := redisImpl.RedisClient.LRange(context.Background(), viewerDeltasKey(matchID), 0, -1).Result()
+	if err != nil {
+		return nil, false
+	}
+
+	var missed [][]byte
+	next := lastSeq + 1
+	for _, entry := range raw {
+		var probe struct {
+			Seq int64 `json:"seq"`
+		}
+		if json.Unmarshal([]byte(entry), &probe) != nil || probe.Seq < next {
+			continue
+		}
+		if probe.Seq > currentSeq {
+			break // broadcast after we read the state; it reaches the viewer through the room
+		}
+		if probe.Seq != next {
+			return nil, false
+		}
+		missed = append(missed, []byte(entry))
+		next++
+	}
+	return missed, next == currentSeq+1
+}
+
+// viewerCatchUp returns the messages that bring a joining viewer up to date. A
+// viewer resuming from lastSeq gets the deltas it missed, preceded by a "resumed"
+// notice so the UI can show what happened while it was away; everyone else gets a
+// full snapshot.
+func viewerCatchUp(matchID string, lastSeq *int64, current models.EnhancedStatsMessage) [][]byte {
+	if lastSeq != nil {
+		if missed, ok := missedViewerDeltas(matchID, *lastSeq, current.Data.Version); ok {
+			notice := fiber.Map{"type": "resumed", "fromSeq": *lastSeq, "toSeq": current.Data.Version, "missed": len(missed)}
+			if data, err := json.Marshal(notice); err == nil {
+				return append([][]byte{data}, missed...)
+			}
+			return missed
+		}
+	}
+	if data, err := json.Marshal(current); err == nil {
+		return [][]byte{data}
+	}
+	return nil
+}
+
+func sendViewerCatchUp(room *MatchRoom, c *websocket.Conn, matchID string, lastSeq *int64, current models.EnhancedStatsMessage) {
+	for _, msg := range viewerCatchUp(matchID, lastSeq, current) {
+		room.SendToViewer(c, msg)
+	}
+}
+
+// viewerMatchUnavailable explains to a viewer why a match has no live state,
+// telling a match that has ended apart from one that has not started
+func viewerMatchUnavailable(matchID string, err error) fiber.Map {
+	if err != redisImpl.RedisNull {
+		logrus.Error("Error:", "viewerMatchUnavailable:", " Failed to get match stats for viewer: %v", err)
+		return fiber.Map{"error": "Failed to retrieve match data"}
+	}
+	matchesColl := db.MongoClient.Database("raidx").Collection("matches")
+	var matchDoc bson.M
+	mErr := matchesColl.FindOne(context.Background(), bson.M{"matchId": matchID}).Decode(&matchDoc)
+	switch {
+	case mErr == nil:
+		return fiber.Map{"error": "Match ended", "matchId": matchID}
+	case mErr == mongo.ErrNoDocuments:
+		return fiber.Map{"error": "Match not initialized", "matchId": matchID}
+	}
+	logrus.Error("Error:", "viewerMatchUnavailable:", " Failed to check match in Mongo: %v", mErr)
+	return fiber.Map{"error": "Failed to retrieve match data"}
+}
@@ -0,0 +1,592 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	webhookMaxAttempts  = 6
+	webhookTimeout      = 10 * time.Second
+	webhookPollInterval = 5 * time.Second
+	// webhookLease is how long a claimed delivery is hidden from other workers
+	webhookLease = time.Minute
+)
+
+// webhookHTTPClient only connects to public addresses, checked on the address
+// actually dialled so a hostname cannot be re-pointed at an internal one after
+// registration. Redirects are not followed; a 3xx counts as a failed delivery.
+var webhookHTTPClient = &http.Client{
+	Timeout: webhookTimeout,
+	Transport: &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: webhookTimeout, Control: webhookDialControl}).DialContext,
+		TLSHandshakeTimeout: webhookTimeout,
+		MaxIdleConns:        20,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+var errWebhookAddressNotPublic = errors.New("webhook address is not public")
+
+// isPublicWebhookIP reports whether a webhook may be delivered to ip. Loopback,
+// private, link-local and other non-routable addresses are refused.
+func isPublicWebhookIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicWebhookIP(ip) {
+		return fmt.Errorf("%w: %s", errWebhookAddressNotPublic, host)
+	}
+	return nil
+}
+
+// validateWebhookURL checks that a webhook URL is absolute http(s) and that every
+// address its host resolves to is public
+func validateWebhookURL(ctx context.Context, raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("url host %s could not be resolved", parsed.Hostname())
+	}
+	for _, addr := range addrs {
+		if !isPublicWebhookIP(addr.IP) {
+			return errors.New("url must point to a public address")
+		}
+	}
+	return nil
+}
+
+// webhookWake nudges the worker when a delivery is queued, so it need not wait for the next poll
+var webhookWake = make(chan struct{}, 1)
+var webhookWorkerOnce sync.Once
+
+func webhooksColl() *mongo.Collection {
+	return db.MongoClient.Database("raidx").Collection("webhooks")
+}
+
+func webhookDeliveriesColl() *mongo.Collection {
+	return db.MongoClient.Database("raidx").Collection("webhook_deliveries")
+}
+
+// webhookBackoff is the wait after a failed attempt: 30s, 2m, 8m, 32m, then about 2h
+func webhookBackoff(attempts int) time.Duration {
+	return 30 * time.Second << (2 * (attempts - 1))
+}
+
+// webhookBody is what a webhook receives. ID is the delivery ID; a replay has a new one.
+type webhookBody struct {
+	ID         string          `json:"id"`
+	Event      string          `json:"event"`
+	EventID    string          `json:"eventId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// signWebhook returns the X-RaidX-Signature value for body sent at timestamp.
+// Receivers recompute HMAC-SHA256 over "<timestamp>.<body>" with their secret.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func wakeWebhookWorker() {
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// emitWebhook queues a delivery of event to every active webhook of the RBAC event
+// that subscribes to it. Sending happens in the background.
+func emitWebhook(eventID primitive.ObjectID, event string, data interface{}) {
+	if eventID.IsZero() {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		logrus.Error("Error:", "emitWebhook:", " Failed to marshal %s payload: %v", event, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cursor, err := webhooksColl().Find(ctx, bson.M{
+		"event_id": eventID,
+		"active":   true,
+		"$or":      []bson.M{{"events": event}, {"events": bson.M{"$size": 0}}},
+	})
+	if err != nil {
+		logrus.Error("Error:", "emitWebhook:", " Failed to load webhooks for event %s: %v", eventID.Hex(), err)
+		return
+	}
+	var hooks []models.Webhook
+	if err := cursor.All(ctx, &hooks); err != nil || len(hooks) == 0 {
+		return
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(hooks))
+	for _, hook := range hooks {
+		docs = append(docs, models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       eventID,
+			Event:         event,
+			Data:          string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			OccurredAt:    now,
+			CreatedAt:     now,
+		})
+	}
+	if _, err := webhookDeliveriesColl().InsertMany(ctx, docs); err != nil {
+		logrus.Error("Error:", "emitWebhook:", " Failed to queue %s deliveries: %v", event, err)
+		return
+	}
+	wakeWebhookWorker()
+}
+
+// emitTournamentWebhook queues a webhook event for the RBAC event behind a tournament
+func emitTournamentWebhook(tournamentID primitive.ObjectID, event string, data fiber.Map) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var tournament models.Tournament
+	if err := db.TournamentsCollection.FindOne(ctx, bson.M{"_id": tournamentID}).Decode(&tournament); err != nil {
+		return
+	}
+	data["tournamentId"] = tournamentID.Hex()
+	emitWebhook(tournament.EventID, event, data)
+}
+
+// emitChampionshipWebhook queues a webhook event for the RBAC event behind a championship
+func emitChampionshipWebhook(championshipID primitive.ObjectID, event string, data fiber.Map) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var championship models.Championship
+	if err := db.ChampionshipsCollection.FindOne(ctx, bson.M{"_id": championshipID}).Decode(&championship); err != nil {
+		return
+	}
+	data["championshipId"] = championshipID.Hex()
+	emitWebhook(championship.EventID, event, data)
+}
+
+// emitScorerWebhooks turns an applied scorer command into webhook events: the
+// initial state starts the match, raids are scored, and all-outs are called out.
+func emitScorerWebhooks(matchID, cmdType string, state models.EnhancedStatsMessage, events []scoring.Event) {
+	eventID, ok := matchOwnerEventID(matchID)
+	if !ok {
+		return
+	}
+	score := fiber.Map{"teamA": state.Data.TeamA, "teamB": state.Data.TeamB}
+	switch cmdType {
+	case scoring.CommandInitialState:
+		emitWebhook(eventID, models.WebhookMatchStarted, fiber.Map{"matchId": matchID, "teamA": state.Data.TeamA, "teamB": state.Data.TeamB, "rules": state.Data.Rules})
+	case scoring.CommandRaid, scoring.CommandLobbyTouch:
+		emitWebhook(eventID, models.WebhookRaidScored, fiber.Map{"matchId": matchID, "raidNumber": state.Data.RaidNumber, "events": events, "score": score})
+	}
+	for _, e := range events {
+		if e.Type == scoring.EventAllOut {
+			emitWebhook(eventID, models.WebhookAllOut, fiber.Map{"matchId": matchID, "team": e.Team, "raidNumber": e.RaidNumber, "points": e.Points, "score": score})
+		}
+	}
+}
+
+func matchOwnerEventKey(matchID string) string {
+	return "matchOwnerEvent:" + matchID
+}
+
+// matchOwnerEventID finds the RBAC event a match belongs to: the tournament's or
+// championship's event for fixtures, otherwise the match event that started it.
+// The answer is cached, since the scorer loop asks on every raid.
+func matchOwnerEventID(matchID string) (primitive.ObjectID, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	key := matchOwnerEventKey(matchID)
+	if cached, err := redisImpl.RedisClient.Get(ctx, key).Result(); err == nil {
+		oid, err := primitive.ObjectIDFromHex(cached)
+		return oid, err == nil
+	}
+
+	var eventID primitive.ObjectID
+	if link, ok := lookupMatchFixture(matchID); ok {
+		if oid, err := primitive.ObjectIDFromHex(link.EventID); err == nil {
+			if link.EventType == eventChannelTournament {
+				var tournament models.Tournament
+				if db.TournamentsCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(&tournament) == nil {
+					eventID = tournament.EventID
+				}
+			} else {
+				var championship models.Championship
+				if db.ChampionshipsCollection.FindOne(ctx, bson.M{"_id": oid}).Decode(&championship) == nil {
+					eventID = championship.EventID
+				}
+			}
+		}
+	} else {
+		var evt models.Event
+		if db.EventsCollection.FindOne(ctx, bson.M{"active_match_id": matchID}).Decode(&evt) == nil {
+			eventID = evt.ID
+		}
+	}
+
+	cached := ""
+	if !eventID.IsZero() {
+		cached = eventID.Hex()
+	}
+	redisImpl.RedisClient.Set(ctx, key, cached, matchFixtureTTL)
+	return eventID, !eventID.IsZero()
+}
+
+// startWebhookWorker sends queued deliveries. Deliveries are claimed with a lease,
+// so several instances can run the worker side by side.
+func startWebhookWorker() {
+	webhookWorkerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(webhookPollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+				case <-webhookWake:
+				}
+				for {
+					delivery, ok := claimWebhookDelivery()
+					if !ok {
+						break
+					}
+					attemptWebhookDelivery(delivery)
+				}
+			}
+		}()
+	})
+}
+
+func claimWebhookDelivery() (models.WebhookDelivery, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	now := time.Now()
+	var delivery models.WebhookDelivery
+	err := webhookDeliveriesColl().FindOneAndUpdate(ctx,
+		bson.M{"status": models.WebhookDeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		bson.M{"$set": bson.M{"next_attempt_at": now.Add(webhookLease)}},
+		options.FindOneAndUpdate().SetSort(bson.M{"next_attempt_at": 1}).SetReturnDocument(options.After),
+	).Decode(&delivery)
+	return delivery, err == nil
+}
+
+// attemptWebhookDelivery posts a delivery once and records the outcome
+func attemptWebhookDelivery(delivery models.WebhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout+5*time.Second)
+	defer cancel()
+
+	set := bson.M{}
+	var hook models.Webhook
+	if err := webhooksColl().FindOne(ctx, bson.M{"_id": delivery.WebhookID}).Decode(&hook); err != nil || !hook.Active {
+		set["status"] = models.WebhookDeliveryFailed
+		set["last_error"] = "webhook removed or disabled"
+		_, _ = webhookDeliveriesColl().UpdateByID(ctx, delivery.ID, bson.M{"$set": set})
+		return
+	}
+
+	body, _ := json.Marshal(webhookBody{
+		ID:         delivery.ID.Hex(),
+		Event:      delivery.Event,
+		EventID:    delivery.EventID.Hex(),
+		OccurredAt: delivery.OccurredAt,
+		Data:       json.RawMessage(delivery.Data),
+	})
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	statusCode, sendErr := postWebhook(ctx, hook, delivery, body, timestamp)
+
+	attempts := delivery.Attempts + 1
+	set["attempts"] = attempts
+	set["last_status_code"] = statusCode
+	if sendErr == nil {
+		set["status"] = models.WebhookDeliverySucceeded
+		set["delivered_at"] = time.Now()
+		set["last_error"] = ""
+	} else {
+		set["last_error"] = sendErr.Error()
+		if attempts >= webhookMaxAttempts {
+			set["status"] = models.WebhookDeliveryFailed
+		} else {
+			set["next_attempt_at"] = time.Now().Add(webhookBackoff(attempts))
+		}
+	}
+	if _, err := webhookDeliveriesColl().UpdateByID(ctx, delivery.ID, bson.M{"$set": set}); err != nil {
+		logrus.Error("Error:", "attemptWebhookDelivery:", " Failed to record delivery %s: %v", delivery.ID.Hex(), err)
+	}
+}
+
+func postWebhook(ctx context.Context, hook models.Webhook, delivery models.WebhookDelivery, body []byte, timestamp string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RaidX-Webhooks/1")
+	req.Header.Set("X-RaidX-Event", delivery.Event)
+	req.Header.Set("X-RaidX-Delivery", delivery.ID.Hex())
+	req.Header.Set("X-RaidX-Timestamp", timestamp)
+	req.Header.Set("X-RaidX-Signature", signWebhook(hook.Secret, timestamp, body))
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// organizerEventFromParams loads the event in the :id param and checks the caller organizes it
+func organizerEventFromParams(c *fiber.Ctx) (models.Event, error) {
+	var event models.Event
+	organizerID, err := getUserIDFromLocals(c)
+	if err != nil {
+		return event, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user"})
+	}
+	eventID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return event, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event id"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.EventsCollection.FindOne(ctx, bson.M{"_id": eventID, "organizer_id": organizerID}).Decode(&event); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return event, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Event not found"})
+		}
+		return event, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch event"})
+	}
+	return event, nil
+}
+
+func isKnownWebhookEvent(event string) bool {
+	for _, known := range models.WebhookEvents {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookHandler registers a webhook URL for an event. The signing secret is
+// only ever returned here.
+func CreateWebhookHandler(c *fiber.Ctx) error {
+	event, err := organizerEventFromParams(c)
+	if event.ID.IsZero() {
+		return err
+	}
+
+	var req struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := validateWebhookURL(ctx, req.URL); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	for _, e := range req.Events {
+		if !isKnownWebhookEvent(e) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Unknown webhook event: " + e, "events": models.WebhookEvents})
+		}
+	}
+	if req.Events == nil {
+		req.Events = []string{}
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create webhook secret"})
+	}
+	now := time.Now()
+	hook := models.Webhook{
+		ID:          primitive.NewObjectID(),
+		EventID:     event.ID,
+		OrganizerID: event.OrganizerID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := webhooksColl().InsertOne(context.Background(), hook); err != nil {
+		logrus.Error("Error:", "CreateWebhookHandler:", " Failed to insert webhook: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create webhook"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(hook)
+}
+
+// ListWebhooksHandler lists an event's webhooks, without their secrets
+func ListWebhooksHandler(c *fiber.Ctx) error {
+	event, err := organizerEventFromParams(c)
+	if event.ID.IsZero() {
+		return err
+	}
+	ctx := context.Background()
+	cursor, err := webhooksColl().Find(ctx, bson.M{"event_id": event.ID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch webhooks"})
+	}
+	hooks := []models.Webhook{}
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode webhooks"})
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return c.JSON(fiber.Map{"webhooks": hooks, "events": models.WebhookEvents})
+}
+
+// DeleteWebhookHandler removes a webhook. Its delivery log is kept.
+func DeleteWebhookHandler(c *fiber.Ctx) error {
+	event, err := organizerEventFromParams(c)
+	if event.ID.IsZero() {
+		return err
+	}
+	hookID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid webhook id"})
+	}
+	res, err := webhooksColl().DeleteOne(context.Background(), bson.M{"_id": hookID, "event_id": event.ID})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete webhook"})
+	}
+	if res.DeletedCount == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Webhook not found"})
+	}
+	return c.JSON(fiber.Map{"success": true})
+}
+
+// ListWebhookDeliveriesHandler returns a webhook's most recent deliveries, newest
+// first. ?status= narrows them to pending, succeeded or failed.
+func ListWebhookDeliveriesHandler(c *fiber.Ctx) error {
+	event, err := organizerEventFromParams(c)
+	if event.ID.IsZero() {
+		return err
+	}
+	hookID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid webhook id"})
+	}
+	filter := bson.M{"webhook_id": hookID, "event_id": event.ID}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	limit := int64(c.QueryInt("limit", 50))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	ctx := context.Background()
+	cursor, err := webhookDeliveriesColl().Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch deliveries"})
+	}
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to decode deliveries"})
+	}
+	return c.JSON(fiber.Map{"deliveries": deliveries})
+}
+
+// ReplayWebhookDeliveryHandler sends a past delivery's payload again as a new
+// delivery, leaving the original's log untouched
+func ReplayWebhookDeliveryHandler(c *fiber.Ctx) error {
+	event, err := organizerEventFromParams(c)
+	if event.ID.IsZero() {
+		return err
+	}
+	hookID, err := primitive.ObjectIDFromHex(c.Params("webhookId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid webhook id"})
+	}
+	deliveryID, err := primitive.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid delivery id"})
+	}
+
+	ctx := context.Background()
+	var original models.WebhookDelivery
+	if err := webhookDeliveriesColl().FindOne(ctx, bson.M{"_id": deliveryID, "webhook_id": hookID, "event_id": event.ID}).Decode(&original); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Delivery not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to fetch delivery"})
+	}
+
+	now := time.Now()
+	replay := models.WebhookDelivery{
+		ID:            primitive.NewObjectID(),
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Data:          original.Data,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: now,
+		ReplayOf:      &original.ID,
+		OccurredAt:    original.OccurredAt,
+		CreatedAt:     now,
+	}
+	if _, err := webhookDeliveriesColl().InsertOne(ctx, replay); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to queue replay"})
+	}
+	wakeWebhookWorker()
+	return c.Status(fiber.StatusAccepted).JSON(replay)
+}
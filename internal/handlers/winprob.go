@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mhatrejeets/RaidX/internal/db"
+	"github.com/mhatrejeets/RaidX/internal/models"
+	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	winProbModelKey       = "winProbModel"
+	winProbCalibrationKey = "winProbCalibration" // held by the instance calibrating, until the next run is due
+	winProbCalibrateEvery = 6 * time.Hour
+	winProbHistoryLimit   = 500 // most recent finished matches to calibrate on
+)
+
+var winProbWorkerOnce sync.Once
+
+func winProbColl() *mongo.Collection {
+	return db.MongoClient.Database("raidx").Collection("match_win_probability")
+}
+
+// currentWinProbModel returns the calibrated model, or the default weights
+// until a calibration has run
+func currentWinProbModel() models.WinProbModel {
+	var model models.WinProbModel
+	if err := redisImpl.GetRedisKey(winProbModelKey, &model); err != nil || model.RaidsPerMinute <= 0 {
+		return models.DefaultWinProbModel()
+	}
+	return model
+}
+
+// syncWinProbability estimates the win probability after a command and, when the
+// raid log changed, stores it as the point for the newest entry. Points for
+// entries that undo or a review rewrote are dropped, as for commentary.
+func syncWinProbability(matchID string, prev, next models.EnhancedStatsMessage, at time.Time) *models.WinProbability {
+	if next.Data.PlayerStats == nil {
+		return nil
+	}
+	prob := scoring.WinProbability(&next, currentWinProbModel(), at)
+
+	p, n := prev.Data.RaidLog, next.Data.RaidLog
+	common := raidLogCommonPrefix(p, n)
+	if common == len(p) && common == len(n) {
+		return &prob
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := winProbColl().DeleteMany(ctx, bson.M{"matchId": matchID, "index": bson.M{"$gte": common}}); err != nil {
+		logrus.Error("Error:", "syncWinProbability:", " Failed to clear win probability for match %s: %v", matchID, err)
+		return &prob
+	}
+	if len(n) > common {
+		last := len(n) - 1
+		point := models.WinProbPoint{
+			MatchID:    matchID,
+			Index:      last,
+			RaidNumber: n[last].RaidNumber,
+			ScoreA:     next.Data.TeamA.Score,
+			ScoreB:     next.Data.TeamB.Score,
+			WinProb:    prob,
+			CreatedAt:  at,
+		}
+		if _, err := winProbColl().InsertOne(ctx, point); err != nil {
+			logrus.Error("Error:", "syncWinProbability:", " Failed to store win probability for match %s: %v", matchID, err)
+		}
+	}
+	return &prob
+}
+
+// pruneWinProbability drops points past the end of a rebuilt raid log
+func pruneWinProbability(matchID string, logLength int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := winProbColl().DeleteMany(ctx, bson.M{"matchId": matchID, "index": bson.M{"$gte": logLength}}); err != nil {
+		logrus.Warnf("win probability prune failed for match %s: %v", matchID, err)
+	}
+}
+
+// startWinProbCalibration refits the model on finished matches at start-up and
+// then every winProbCalibrateEvery. Only one instance calibrates per period.
+func startWinProbCalibration() {
+	winProbWorkerOnce.Do(func() {
+		go func() {
+			for {
+				claimed, err := redisImpl.RedisClient.SetNX(context.Background(), winProbCalibrationKey, time.Now().Unix(), winProbCalibrateEvery).Result()
+				if err == nil && claimed {
+					calibrateWinProbModel()
+				}
+				time.Sleep(winProbCalibrateEvery)
+			}
+		}()
+	})
+}
+
+// calibrateWinProbModel replays recent finished matches and stores the fitted model
+func calibrateWinProbModel() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	matchesColl := db.MongoClient.Database("raidx").Collection("matches")
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(winProbHistoryLimit)
+	cursor, err := matchesColl.Find(ctx, bson.M{"data.raidLog.0": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		logrus.Error("Error:", "calibrateWinProbModel:", " Failed to load matches: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var samples []scoring.WinProbSample
+	matches, raids, minutes := 0, 0, 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			Data bson.M `bson:"data"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		// Finished matches are stored with the live state's JSON field names
+		var match models.EnhancedStatsMessage
+		raw, err := json.Marshal(doc.Data)
+		if err != nil || json.Unmarshal(raw, &match.Data) != nil {
+			continue
+		}
+		matchSamples := scoring.WinProbSamples(match)
+		if len(matchSamples) == 0 {
+			continue
+		}
+		samples = append(samples, matchSamples...)
+		matches++
+		raids += match.Data.RaidNumber - 1
+		minutes += 2 * match.Data.Rules.OrDefault().HalfMinutes
+	}
+
+	model := scoring.FitWinProbModel(samples, models.DefaultWinProbModel())
+	if model.Samples == 0 {
+		logrus.Infof("win probability: %d states from %d matches is too few to calibrate", len(samples), matches)
+		return
+	}
+	if raids > 0 && minutes > 0 {
+		model.RaidsPerMinute = float64(raids) / float64(minutes)
+	}
+	model.Matches = matches
+	model.CalibratedAt = time.Now()
+	if err := redisImpl.SetRedisKey(winProbModelKey, model); err != nil {
+		logrus.Error("Error:", "calibrateWinProbModel:", " Failed to store model: %v", err)
+		return
+	}
+	logrus.Infof("win probability calibrated on %d states from %d matches: %+v", model.Samples, matches, model)
+}
+
+// GetMatchWinProbabilityHandler returns a match's win probability after each raid,
+// for drawing the win-probability graph
+func GetMatchWinProbabilityHandler(c *fiber.Ctx) error {
+	matchID := c.Params("id")
+	if matchID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Match ID required"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	opts := options.Find().SetSort(bson.D{{Key: "index", Value: 1}})
+	cursor, err := winProbColl().Find(ctx, bson.M{"matchId": matchID}, opts)
+	if err != nil {
+		logrus.Error("Error:", "GetMatchWinProbabilityHandler:", " Failed to load win probability: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load win probability"})
+	}
+	defer cursor.Close(ctx)
+
+	points := []models.WinProbPoint{}
+	if err := cursor.All(ctx, &points); err != nil {
+		logrus.Error("Error:", "GetMatchWinProbabilityHandler:", " Failed to decode win probability: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to load win probability"})
+	}
+	return c.JSON(fiber.Map{"matchId": matchID, "points": points})
+}
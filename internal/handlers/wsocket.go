@@ -14,20 +14,12 @@ import (
 	"github.com/mhatrejeets/RaidX/internal/middleware"
 	"github.com/mhatrejeets/RaidX/internal/models"
 	"github.com/mhatrejeets/RaidX/internal/redisImpl"
+	"github.com/mhatrejeets/RaidX/internal/scoring"
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var viewerClients = struct {
-	clients map[*websocket.Conn]bool
-	mu      sync.Mutex
-}{
-	clients: make(map[*websocket.Conn]bool),
-}
-
-var broadcastChan = make(chan []byte)
 var snapshotWorkerOnce sync.Once
 
 const scorerLockTTL = 45 * time.Second
@@ -132,6 +124,7 @@ func startIdleSnapshotWorker() {
 			ticker := time.NewTicker(time.Minute)
 			defer ticker.Stop()
 			for range ticker.C {
+				flushPendingMatchEvents()
 				keys, err := redisImpl.ListRedisKeys("gameStats:*")
 				if err != nil {
 					continue
@@ -158,36 +151,71 @@ func startIdleSnapshotWorker() {
 	})
 }
 
-func StartBroadcastWorker() {
-	go func() {
-		for msg := range broadcastChan {
-			viewerClients.mu.Lock()
-			for conn := range viewerClients.clients {
-				err := conn.WriteMessage(websocket.TextMessage, msg)
-				if err != nil {
-					logrus.Error("Error:", "StartBroadcastWorker:", " Error sending message to viewer: %v", err)
-					conn.Close()
-					delete(viewerClients.clients, conn)
-				}
-			}
-			viewerClients.mu.Unlock()
-		}
-	}()
-}
-
-func BroadcastToViewers(message models.EnhancedStatsMessage) {
-	data, err := json.Marshal(message)
+// broadcastMatchUpdate sends viewers a delta for the command just applied. State
+// replacements have nothing to diff against, so they go out as a full snapshot.
+// It publishes to the match room directly, so the caller need not hold the room.
+func broadcastMatchUpdate(matchID, cmdType string, prev, next models.EnhancedStatsMessage, commentary []models.CommentaryEntry, winProb *models.WinProbability) {
+	delta := buildMatchDelta(prev, next)
+	delta.Commentary = commentary
+	delta.WinProbability = winProb
+	var update interface{} = delta
+	if !commandNeedsState(cmdType) {
+		update = next
+	}
+	data, err := json.Marshal(update)
 	if err != nil {
-		logrus.Error("Error:", "BroadcastToViewers:", " Error marshalling data for viewers: %v", err)
+		logrus.Error("Error:", "broadcastMatchUpdate:", " Error marshalling update for viewers: %v", err)
 		return
 	}
-	broadcastChan <- data
+	if commandNeedsState(cmdType) {
+		bufferViewerDelta(matchID, data)
+	} else {
+		resetViewerDeltas(matchID)
+	}
+	publishRoomMessage(matchID, roomMessage{Kind: roomMessageBroadcast, Data: data})
+
+	p, n := prev.Data, next.Data
+	if !commandNeedsState(cmdType) || p.TeamA != n.TeamA || p.TeamB != n.TeamB || p.RaidNumber != n.RaidNumber || p.Clock != n.Clock {
+		publishScoreTick(matchID, next)
+	}
+}
+
+// commitScorerCommand applies a scorer command to the live state and, if it
+// changed anything, snapshots it, records it in the event store, tells webhooks
+// and pushes the update to viewers
+func commitScorerCommand(matchID string, cmd scorerCommand, actor models.MatchEventActor, at time.Time) (scorerCommandResult, error) {
+	if cmd.Type == scoring.CommandInitialState {
+		body, err := withMatchRules(matchID, cmd.Body)
+		if err != nil {
+			logrus.Error("Error:", "commitScorerCommand:", " %v", err)
+			return scorerCommandResult{}, errMatchRulesUnavailable
+		}
+		cmd.Body = body
+	}
+
+	result, err := applyScorerCommandAtomically(matchID, cmd.Type, cmd.Body, cmd.Meta, at)
+	if err != nil || result.Conflict || result.Duplicate {
+		return result, err
+	}
+
+	persistMatchSnapshot(matchID, result.Match)
+	if err := recordMatchEvent(matchID, result.Match.Data.Version, cmd.Type, cmd.Meta.CommandID, cmd.Body, actor, at); err != nil {
+		logrus.Error("Error:", "commitScorerCommand:", " Failed to record %s event for match %s: %v", cmd.Type, matchID, err)
+	}
+	go emitScorerWebhooks(matchID, cmd.Type, result.Match, result.Events)
+
+	commentary := syncMatchCommentary(matchID, result.Previous, result.Match, result.Events)
+	winProb := syncWinProbability(matchID, result.Previous, result.Match, at)
+	broadcastMatchUpdate(matchID, cmd.Type, result.Previous, result.Match, commentary, winProb)
+	// Assistants and mirrors follow the scorer's view rather than viewer deltas
+	publishToScorers(matchID, []string{models.ScorerRoleAssistant, models.ScorerRoleMirror}, models.WSTypeState, "", result.Match)
+	return result, nil
 }
 
 func SetupWebSocket(app *fiber.App) {
-	// Start the broadcast worker
-	StartBroadcastWorker()
 	startIdleSnapshotWorker()
+	startWebhookWorker()
+	startWinProbCalibration()
 
 	// Handle scorer WebSocket
 	app.Get("/ws/scorer", websocket.New(func(c *websocket.Conn) {
@@ -200,75 +228,107 @@ func SetupWebSocket(app *fiber.App) {
 			c.Close()
 			return
 		}
-		// Expect first message from client to be a join with matchId
+		// Expect first message from client to be a join with matchId; it also fixes the protocol version
 		_, joinMsg, err := c.ReadMessage()
 		if err != nil {
 			logrus.Error("Error:", "SetupWebSocket:", " Failed to read join message: %v", err)
 			return
 		}
-		var join struct {
-			Type    string `json:"type"`
-			MatchID string `json:"matchId"`
-		}
-		if err := json.Unmarshal(joinMsg, &join); err != nil || join.Type != "join" || join.MatchID == "" {
-			// ask client to send proper join
-			req := map[string]string{"type": "requestJoin"}
-			if data, e := json.Marshal(req); e == nil {
-				_ = c.WriteMessage(websocket.TextMessage, data)
-			}
+		session, matchID, err := negotiateScorerJoin(c, joinMsg)
+		if err != nil {
+			rejectScorerJoin(c, joinMsg, err)
 			return
 		}
 
-		matchID := join.MatchID
-		scorerOwner := fmt.Sprintf("%v:%v", claims["user_id"], claims["session_id"])
-		acquired, lockErr := acquireScorerLock(matchID, scorerOwner)
-		if lockErr != nil {
-			_ = c.WriteMessage(websocket.TextMessage, []byte(`{"error":"Failed to acquire scorer lock"}`))
-			c.Close()
-			return
+		scorerActor := models.MatchEventActor{
+			UserID:    fmt.Sprint(claims["user_id"]),
+			SessionID: fmt.Sprint(claims["session_id"]),
 		}
-		if !acquired {
-			_ = c.WriteMessage(websocket.TextMessage, []byte(`{"error":"This match is already being scored by another active scorer"}`))
-			c.Close()
-			return
+		scorerOwner := scorerLockOwner(scorerActor)
+		isChief := session.role == models.ScorerRoleChief
+		if isChief {
+			acquired, lockErr := acquireScorerLock(matchID, scorerOwner)
+			if lockErr == nil && !acquired {
+				// The lock may have been handed off to this scorer
+				acquired, lockErr = claimScorerHandoff(matchID, scorerActor, session.device)
+			}
+			if lockErr != nil {
+				session.sendError("", models.WSErrInternal, "Failed to acquire scorer lock")
+				c.Close()
+				return
+			}
+			if !acquired {
+				session.sendError("", models.WSErrScorerLocked, "This match is already being scored by another active scorer")
+				c.Close()
+				return
+			}
+		} else {
+			allowed, authErr := mayJoinScorerSide(matchID, scorerActor)
+			if authErr != nil {
+				logrus.Error("Error:", "SetupWebSocket:", " Failed to check scorer access to match %s: %v", matchID, authErr)
+				session.sendError("", models.WSErrInternal, "Failed to check match ownership")
+				c.Close()
+				return
+			}
+			if !allowed {
+				session.sendError("", models.WSErrForbidden, "Only the event organizer or the chief scorer can join as "+session.role)
+				c.Close()
+				return
+			}
 		}
 
+		now := time.Now()
+		connInfo := models.ScorerConnection{
+			ID:          newScorerConnectionID(),
+			Role:        session.role,
+			UserID:      scorerActor.UserID,
+			SessionID:   scorerActor.SessionID,
+			Device:      session.device,
+			ConnectedAt: now,
+			SeenAt:      now,
+		}
 		room := GetRoom(matchID)
-		room.AddScorer(c)
+		session.client = room.AddScorer(c, scorerOwner, connInfo)
+		registerScorerConnection(matchID, connInfo)
 		defer func() {
-			releaseScorerLock(matchID, scorerOwner)
+			if isChief {
+				releaseScorerLock(matchID, scorerOwner)
+			}
 			room.RemoveScorer(c)
+			unregisterScorerConnection(matchID, connInfo.ID)
+			go publishScorerRoomState(matchID)
+			session.client.Close()
 			logrus.Info("Info:", "SetupWebSocket:", " Scorer connection closed")
 			c.Close()
 		}()
-
-		// ...existing code...
+		publishScorerRoomState(matchID)
 
 		// send current match state from Redis (per-match key)
 		var currentMatch models.EnhancedStatsMessage
-		redisKey := "gameStats:" + matchID
+		redisKey := gameStatsKey(matchID)
 		if err := redisImpl.GetRedisKey(redisKey, &currentMatch); err != nil {
 			if err == redisImpl.RedisNull {
 				if snapErr := loadMatchSnapshot(matchID, &currentMatch); snapErr == nil {
-					_ = redisImpl.SetRedisKey(redisKey, currentMatch)
-					if data, e := json.Marshal(currentMatch); e == nil {
-						_ = c.WriteMessage(websocket.TextMessage, data)
+					if stored, err := restoreMatchStateAtomically(matchID, currentMatch); err == nil {
+						currentMatch = stored
 					}
-				} else {
-					// Ask client to send initial state
-					req := map[string]string{"type": "requestInit"}
-					if data, e := json.Marshal(req); e == nil {
-						_ = c.WriteMessage(websocket.TextMessage, data)
+					session.sendState(currentMatch)
+				} else if replayed, replayErr := replayMatchEventsFor(matchID); replayErr == nil {
+					// No snapshot either - derive the state from the event store
+					if stored, err := restoreMatchStateAtomically(matchID, replayed); err == nil {
+						replayed = stored
 					}
+					session.sendState(replayed)
+				} else if isChief {
+					// Ask client to send initial state
+					session.requestInit()
 				}
 			} else {
 				logrus.Error("Error:", "SetupWebSocket:", " Failed to get gameStats for match %s: %v", matchID, err)
 			}
 		} else {
-			if data, err := json.Marshal(currentMatch); err == nil {
-				// send to connecting scorer only
-				_ = c.WriteMessage(websocket.TextMessage, data)
-			}
+			// send to connecting scorer only
+			session.sendState(currentMatch)
 		}
 
 		// main read loop for this scorer
@@ -278,209 +338,60 @@ func SetupWebSocket(app *fiber.App) {
 				logrus.Error("Error:", "SetupWebSocket:", " Error reading message from scorer: %v", err)
 				break
 			}
-			refreshScorerLock(matchID, scorerOwner)
-
-			// First probe if this is a raid payload (from scorer UI) or a full state update
-			var probe struct {
-				RaidType string `json:"raidType"`
-				Type     string `json:"type"`
-			}
-			_ = json.Unmarshal(msg, &probe)
-			if probe.Type == "initialState" {
-				// client sent initial full state for this match - persist
-				var received models.EnhancedStatsMessage
-				if err := json.Unmarshal(msg, &received); err == nil {
-					if received.Data.LastScoreChangeAt == 0 {
-						received.Data.LastScoreChangeAt = time.Now().Unix()
-					}
-					if err := redisImpl.SetRedisKey(redisKey, received); err == nil {
-						persistMatchSnapshot(matchID, received)
-						if data, e := json.Marshal(received); e == nil {
-							room.BroadcastBytes(data)
-						}
-					}
-				}
+			if !isChief {
+				session.handleNonChiefMessage(matchID, msg, scorerActor)
 				continue
 			}
-
-			if probe.RaidType != "" {
-				var payload RaidPayload
-				if err := json.Unmarshal(msg, &payload); err != nil {
-					logrus.Error("Error:", "SetupWebSocket:", " Error unmarshalling raid payload: %v", err)
-					continue
-				}
-
-				var currentMatch models.EnhancedStatsMessage
-				if err := redisImpl.GetRedisKey(redisKey, &currentMatch); err != nil {
-					if err == redisImpl.RedisNull {
-						errMsg := map[string]string{"error": "server: game state not initialized. Please send initial state"}
-						if b, e := json.Marshal(errMsg); e == nil {
-							_ = c.WriteMessage(websocket.TextMessage, b)
-						}
-						continue
-					}
-					logrus.Error("Error:", "SetupWebSocket:", " Failed to get gameStats: %v", err)
-					continue
-				}
-
-				if err := validateRaidPayload(payload, &currentMatch); err != nil {
-					errMsg := map[string]string{"error": err.Error()}
-					if b, e := json.Marshal(errMsg); e == nil {
-						_ = c.WriteMessage(websocket.TextMessage, b)
-					}
-					continue
-				}
-
-				prevTeamAScore := currentMatch.Data.TeamA.Score
-				prevTeamBScore := currentMatch.Data.TeamB.Score
-
-				switch payload.RaidType {
-				case "successful":
-					processSuccessfulRaid(&currentMatch, payload)
-				case "defense":
-					processDefenseSuccess(&currentMatch, payload)
-				case "empty":
-					processEmptyRaid(&currentMatch, payload)
-				default:
-					logrus.Warn("Warning:", "SetupWebSocket:", " Unknown raid type from scorer: %v", payload.RaidType)
-				}
-
-				currentMatch.Data.Awards = computeAwardsFromPlayerStats(currentMatch.Data.PlayerStats)
-				if currentMatch.Data.TeamA.Score != prevTeamAScore || currentMatch.Data.TeamB.Score != prevTeamBScore {
-					currentMatch.Data.LastScoreChangeAt = time.Now().Unix()
-				}
-
-				if err := redisImpl.SetRedisKey(redisKey, currentMatch); err != nil {
-					logrus.Error("Error:", "SetupWebSocket:", " Failed to set gameStats: %v", err)
-					continue
-				}
-				persistMatchSnapshot(matchID, currentMatch)
-				if data, err := json.Marshal(currentMatch); err == nil {
-					room.BroadcastBytes(data)
-					_ = c.WriteMessage(websocket.TextMessage, data)
-				}
-				continue
+			// The lock may have been handed off; a chief that lost it must stop
+			if held, err := holdScorerLock(matchID, scorerOwner); err == nil && !held {
+				session.sendError("", models.WSErrScorerLocked, "Scoring has passed to another scorer")
+				break
 			}
 
-			// Probe for custom non-raid message types (e.g., lobbyTouch)
-			var typeProbe struct {
-				Type string `json:"type"`
+			switch session.messageType(msg) {
+			case models.WSTypeBatch:
+				session.syncBatch(matchID, msg, scorerActor)
+				continue
+			case models.WSTypeConfirmProposal, models.WSTypeRejectProposal:
+				session.resolveProposal(matchID, msg, scorerActor)
+				continue
+			case models.WSTypeHandoff:
+				session.handOff(matchID, msg, scorerActor)
+				continue
 			}
-			_ = json.Unmarshal(msg, &typeProbe)
-
-			if typeProbe.Type == "lobbyTouch" {
-				var lobbyPayload struct {
-					Type string `json:"type"`
-					Data struct {
-						TouchedPlayerId string   `json:"touchedPlayerId"`
-						IsRaider        bool     `json:"isRaider"`
-						ScoringTeam     string   `json:"scoringTeam"`
-						RaiderId        string   `json:"raiderId"`
-						DefenderIds     []string `json:"defenderIds"`
-						RaidNumber      int      `json:"raidNumber"`
-					} `json:"data"`
-				}
-				if err := json.Unmarshal(msg, &lobbyPayload); err != nil {
-					logrus.Error("Error:", "SetupWebSocket:", " Error unmarshalling lobby payload: %v", err)
-					continue
-				}
-
-				var currentMatch models.EnhancedStatsMessage
-				if err := redisImpl.GetRedisKey(redisKey, &currentMatch); err != nil {
-					if err == redisImpl.RedisNull {
-						errMsg := map[string]string{"error": "server: game state not initialized. Please send initial state"}
-						if b, e := json.Marshal(errMsg); e == nil {
-							_ = c.WriteMessage(websocket.TextMessage, b)
-						}
-						continue
-					}
-					logrus.Error("Error:", "SetupWebSocket:", " Failed to get gameStats for lobbyTouch: %v", err)
-					continue
-				}
-
-				prevTeamAScore := currentMatch.Data.TeamA.Score
-				prevTeamBScore := currentMatch.Data.TeamB.Score
-
-				if lobbyPayload.Data.ScoringTeam == "A" {
-					currentMatch.Data.TeamA.Score++
-				} else {
-					currentMatch.Data.TeamB.Score++
-				}
-				if currentMatch.Data.TeamA.Score != prevTeamAScore || currentMatch.Data.TeamB.Score != prevTeamBScore {
-					currentMatch.Data.LastScoreChangeAt = time.Now().Unix()
-				}
-
-				raiderName := ""
-				if p, ok := currentMatch.Data.PlayerStats[lobbyPayload.Data.TouchedPlayerId]; ok {
-					raiderName = p.Name
-				}
-
-				if p, ok := currentMatch.Data.PlayerStats[lobbyPayload.Data.TouchedPlayerId]; ok {
-					p.Status = "out"
-					currentMatch.Data.PlayerStats[lobbyPayload.Data.TouchedPlayerId] = p
-				}
-
-				if !lobbyPayload.Data.IsRaider && lobbyPayload.Data.RaiderId != "" {
-					if r, ok := currentMatch.Data.PlayerStats[lobbyPayload.Data.RaiderId]; ok {
-						r.RaidPoints++
-						r.TotalPoints++
-						currentMatch.Data.PlayerStats[lobbyPayload.Data.RaiderId] = r
-					}
-				}
-
-				currentMatch.Data.PendingLobby.Events = append(currentMatch.Data.PendingLobby.Events, models.LobbyEvent{
-					TouchedPlayerId: lobbyPayload.Data.TouchedPlayerId,
-					IsRaider:        lobbyPayload.Data.IsRaider,
-					ScoringTeam:     lobbyPayload.Data.ScoringTeam,
-					RaidNumber:      lobbyPayload.Data.RaidNumber,
-				})
-
-				currentMatch.Data.RaidDetails = models.RaidDetails{
-					Type:         "lobbyTouch",
-					Raider:       raiderName,
-					PointsGained: 1,
-				}
-				currentMatch.Data.Awards = computeAwardsFromPlayerStats(currentMatch.Data.PlayerStats)
-
-				checkAndHandleAllOut(&currentMatch)
-
-				// Increment raid number so next team raids (same as successful/defense/empty raid actions)
-				currentMatch.Data.RaidNumber++
-
-				if err := redisImpl.SetRedisKey(redisKey, currentMatch); err != nil {
-					logrus.Error("Error:", "SetupWebSocket:", " Failed to set gameStats for lobbyTouch: %v", err)
-					continue
-				}
-				persistMatchSnapshot(matchID, currentMatch)
-				if data, err := json.Marshal(currentMatch); err == nil {
-					room.BroadcastBytes(data)
-					_ = c.WriteMessage(websocket.TextMessage, data)
-				}
+			cmd, bad := session.readCommand(msg)
+			if bad != nil {
+				session.sendError(cmd.Meta.CommandID, bad.Code, bad.Message)
 				continue
 			}
-
-			// Otherwise treat as a full state update (legacy behavior)
-			var receivedMessage models.EnhancedStatsMessage
-			if err := json.Unmarshal(msg, &receivedMessage); err != nil {
-				logrus.Error("Error:", "SetupWebSocket:", " Error unmarshalling scorer message: %v", err)
+			result, err := commitScorerCommand(matchID, cmd, scorerActor, time.Now())
+			if err != nil {
+				session.sendError(cmd.Meta.CommandID, scorerErrorCode(err), err.Error())
 				continue
 			}
-
-			if err := redisImpl.SetRedisKey(redisKey, receivedMessage); err != nil {
-				logrus.Error("Error:", "SetupWebSocket:", " Error storing data in Redis: %v", err)
+			if result.Conflict {
+				// Built on a state the scorer has not seen; hand back the current one to rebase on
+				session.sendConflict(cmd.Meta.CommandID, result.Match)
+				continue
 			}
-			if receivedMessage.Data.LastScoreChangeAt == 0 {
-				receivedMessage.Data.LastScoreChangeAt = time.Now().Unix()
+			if result.Duplicate {
+				session.sendAck(cmd.Meta.CommandID, result.Match.Data.Version, true, nil)
+				continue
 			}
-			persistMatchSnapshot(matchID, receivedMessage)
 
-			if data, err := json.Marshal(receivedMessage); err == nil {
-				room.BroadcastBytes(data)
+			currentMatch := result.Match
+			if cmd.Type != scoring.CommandFullState {
+				// Echo back everything except legacy overwrites; initial state carries the server-chosen rules
+				session.sendState(currentMatch)
 			}
+			session.sendAck(cmd.Meta.CommandID, currentMatch.Data.Version, false, result.Events)
 		}
 
 	}))
 
+	// Handle tournament and championship channel WebSocket
+	app.Get("/ws/event", websocket.New(eventViewerSocket))
+
 	// Handle viewer WebSocket
 	app.Get("/ws/viewer", websocket.New(func(c *websocket.Conn) {
 		defer func() {
@@ -490,14 +401,17 @@ func SetupWebSocket(app *fiber.App) {
 
 		// --- Optional JWT Auth for Viewer WebSocket ---
 		token := c.Query("token")
+		userID := ""
 		if token != "" {
-			if _, err := middleware.AuthWebSocket(token); err != nil {
+			claims, err := middleware.AuthWebSocket(token)
+			if err != nil {
 				resp := map[string]string{"type": "error", "message": "Unauthorized: Invalid JWT token"}
 				if data, e := json.Marshal(resp); e == nil {
 					_ = c.WriteMessage(websocket.TextMessage, data)
 				}
 				return
 			}
+			userID = fmt.Sprint(claims["user_id"])
 		}
 
 		// Expect a join message with matchId
@@ -507,8 +421,10 @@ func SetupWebSocket(app *fiber.App) {
 			return
 		}
 		var join struct {
-			Type    string `json:"type"`
-			MatchID string `json:"matchId"`
+			Type     string `json:"type"`
+			MatchID  string `json:"matchId"`
+			LastSeq  *int64 `json:"lastSeq"`  // set when resuming after a reconnect
+			ViewerID string `json:"viewerId"` // anonymous browser ID, for unique viewer counts
 		}
 		if err := json.Unmarshal(joinMsg, &join); err != nil || join.Type != "join" || join.MatchID == "" {
 			req := map[string]string{"type": "requestJoin"}
@@ -520,51 +436,39 @@ func SetupWebSocket(app *fiber.App) {
 
 		matchID := join.MatchID
 		room := GetRoom(matchID)
-		room.AddViewer(c)
+		client := room.AddViewer(c)
+		defer client.Close()
+		recordUniqueViewer(matchID, viewerIdentity(userID, join.ViewerID, c.RemoteAddr().String()))
 
 		// send latest game stats from Redis for this match
 		var latestStats models.EnhancedStatsMessage
-		redisKey := "gameStats:" + matchID
+		redisKey := gameStatsKey(matchID)
 		err = redisImpl.GetRedisKey(redisKey, &latestStats)
 		if err == nil {
-			data, _ := json.Marshal(latestStats)
-			_ = c.WriteMessage(websocket.TextMessage, data)
-		} else if err == redisImpl.RedisNull {
-			// Match not found in Redis - check Mongo to distinguish ended vs not initialized
-			matchesColl := db.MongoClient.Database("raidx").Collection("matches")
-			var matchDoc bson.M
-			mErr := matchesColl.FindOne(context.Background(), bson.M{"matchId": matchID}).Decode(&matchDoc)
-			if mErr == nil {
-				errMsg := map[string]string{"error": "Match ended", "matchId": matchID}
-				if data, e := json.Marshal(errMsg); e == nil {
-					_ = c.WriteMessage(websocket.TextMessage, data)
-				}
-			} else if mErr == mongo.ErrNoDocuments {
-				errMsg := map[string]string{"error": "Match not initialized", "matchId": matchID}
-				if data, e := json.Marshal(errMsg); e == nil {
-					_ = c.WriteMessage(websocket.TextMessage, data)
-				}
-			} else {
-				logrus.Error("Error:", "SetupWebSocket:", " Failed to check match in Mongo: %v", mErr)
-				errMsg := map[string]string{"error": "Failed to retrieve match data"}
-				if data, e := json.Marshal(errMsg); e == nil {
-					_ = c.WriteMessage(websocket.TextMessage, data)
-				}
-			}
-		} else {
-			// Other Redis error
-			logrus.Error("Error:", "SetupWebSocket:", " Failed to get match stats for viewer: %v", err)
-			errMsg := map[string]string{"error": "Failed to retrieve match data"}
-			if data, e := json.Marshal(errMsg); e == nil {
-				_ = c.WriteMessage(websocket.TextMessage, data)
-			}
+			sendViewerCatchUp(room, c, matchID, join.LastSeq, latestStats)
+		} else if data, e := json.Marshal(viewerMatchUnavailable(matchID, err)); e == nil {
+			room.SendToViewer(c, data)
 		}
 
-		// Keep connection open
+		// Viewers only ever ask for a fresh snapshot, after spotting a gap in the delta sequence
 		for {
-			if _, _, err := c.NextReader(); err != nil {
+			_, msg, err := c.ReadMessage()
+			if err != nil {
 				break
 			}
+			var req struct {
+				Type string `json:"type"`
+			}
+			if json.Unmarshal(msg, &req) != nil || req.Type != "snapshotRequest" {
+				continue
+			}
+			var snapshot models.EnhancedStatsMessage
+			if err := redisImpl.GetRedisKey(redisKey, &snapshot); err != nil {
+				continue
+			}
+			if data, err := json.Marshal(snapshot); err == nil {
+				room.SendToViewer(c, data)
+			}
 		}
 
 		room.RemoveViewer(c)
@@ -37,6 +37,7 @@ type ChampionshipFixture struct {
 	WinnerID       *primitive.ObjectID `json:"winnerId,omitempty" bson:"winnerId,omitempty"`
 	Team1Score     int                 `json:"team1Score,omitempty" bson:"team1Score,omitempty"`
 	Team2Score     int                 `json:"team2Score,omitempty" bson:"team2Score,omitempty"`
+	TieBreak       *FixtureTieBreak    `json:"tieBreak,omitempty" bson:"tieBreak,omitempty"`
 	CreatedAt      time.Time           `json:"createdAt" bson:"createdAt"`
 	UpdatedAt      time.Time           `json:"updatedAt" bson:"updatedAt"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// CommentaryEntry is the generated text commentary for one raid log entry.
+// Index is the entry's position in the raid log; when undo or a review rewrites
+// the log, the entries from that position on are replaced.
+type CommentaryEntry struct {
+	MatchID    string    `json:"matchId" bson:"matchId"`
+	Index      int       `json:"index" bson:"index"`
+	RaidNumber int       `json:"raidNumber" bson:"raidNumber"`
+	Result     string    `json:"result" bson:"result"`
+	Lines      []string  `json:"lines" bson:"lines"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+}
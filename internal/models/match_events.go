@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MatchEventActor identifies who issued a scorer command (taken from the JWT)
+type MatchEventActor struct {
+	UserID     string           `json:"userId" bson:"user_id"`
+	SessionID  string           `json:"sessionId" bson:"session_id"`
+	ProposedBy *MatchEventActor `json:"proposedBy,omitempty" bson:"proposed_by,omitempty"` // the assistant scorer whose proposal the chief confirmed
+}
+
+// MatchEvent is an immutable, ordered record of a scorer command applied to a match.
+// Replaying a match's events in Seq order through the raid processors reproduces its state.
+type MatchEvent struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	MatchID   string             `json:"matchId" bson:"matchId"`
+	Seq       int64              `json:"seq" bson:"seq"`                                 // The state version the command produced; unique per match
+	Type      string             `json:"type" bson:"type"`                               // initialState, raid, lobbyTouch, undo, redo, clock, substitution, technicalPoint, card, tieBreak, review, fullState, rebuild
+	Payload   string             `json:"payload" bson:"payload"`                         // Raw command JSON as received from the scorer
+	CommandID string             `json:"commandId,omitempty" bson:"commandId,omitempty"` // Client-generated, used to ignore resent commands
+	Actor     MatchEventActor    `json:"actor" bson:"actor"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
@@ -27,6 +27,9 @@ type Match struct {
 		TossDecision       string                `json:"tossDecision,omitempty" bson:"tossDecision,omitempty"`
 		FirstRaidingTeam   string                `json:"firstRaidingTeam,omitempty" bson:"firstRaidingTeam,omitempty"`
 		LastScoreChangeAt  int64                 `json:"lastScoreChangeAt,omitempty" bson:"lastScoreChangeAt,omitempty"`
+		Rules              *RuleSet              `json:"rules,omitempty" bson:"rules,omitempty"`
+		Clock              *MatchClock           `json:"clock,omitempty" bson:"clock,omitempty"`
+		TieBreak           *TieBreakState        `json:"tieBreak,omitempty" bson:"tieBreak,omitempty"`
 	} `json:"data" bson:"data"`
 }
 
@@ -59,17 +62,24 @@ type LobbyState struct {
 }
 
 type RaidLogEntry struct {
-	RaidNumber  int          `json:"raidNumber" bson:"raidNumber"`
-	RaidingTeam string       `json:"raidingTeam" bson:"raidingTeam"`
-	RaiderId    string       `json:"raiderId" bson:"raiderId"`
-	DefenderIds []string     `json:"defenderIds,omitempty" bson:"defenderIds,omitempty"`
-	Result      string       `json:"result" bson:"result"`
-	Points      int          `json:"points" bson:"points"`
-	BonusTaken  bool         `json:"bonusTaken,omitempty" bson:"bonusTaken,omitempty"`
-	SuperRaid   bool         `json:"superRaid,omitempty" bson:"superRaid,omitempty"`
-	SuperTackle bool         `json:"superTackle,omitempty" bson:"superTackle,omitempty"`
-	DoOrDie     bool         `json:"doOrDie,omitempty" bson:"doOrDie,omitempty"`
-	LobbyEvents []LobbyEvent `json:"lobbyEvents,omitempty" bson:"lobbyEvents,omitempty"`
+	RaidNumber       int          `json:"raidNumber" bson:"raidNumber"`
+	RaidingTeam      string       `json:"raidingTeam" bson:"raidingTeam"`
+	RaiderId         string       `json:"raiderId" bson:"raiderId"`
+	DefenderIds      []string     `json:"defenderIds,omitempty" bson:"defenderIds,omitempty"`
+	Result           string       `json:"result" bson:"result"`
+	Points           int          `json:"points" bson:"points"`
+	BonusTaken       bool         `json:"bonusTaken,omitempty" bson:"bonusTaken,omitempty"`
+	SuperRaid        bool         `json:"superRaid,omitempty" bson:"superRaid,omitempty"`
+	SuperTackle      bool         `json:"superTackle,omitempty" bson:"superTackle,omitempty"`
+	DoOrDie          bool         `json:"doOrDie,omitempty" bson:"doOrDie,omitempty"`
+	LobbyEvents      []LobbyEvent `json:"lobbyEvents,omitempty" bson:"lobbyEvents,omitempty"`
+	SubOutId         string       `json:"subOutId,omitempty" bson:"subOutId,omitempty"`                 // Substitutions only: player sent to the bench
+	SubInId          string       `json:"subInId,omitempty" bson:"subInId,omitempty"`                   // Substitutions only: player brought on
+	Card             string       `json:"card,omitempty" bson:"card,omitempty"`                         // Cards only: green, yellow or red
+	PlayerId         string       `json:"playerId,omitempty" bson:"playerId,omitempty"`                 // Cards only: player shown the card
+	SuspendUntilRaid int          `json:"suspendUntilRaid,omitempty" bson:"suspendUntilRaid,omitempty"` // Yellow cards only: see PlayerStat.SuspendedUntilRaid
+	SuspendUntilSec  int          `json:"suspendUntilSec,omitempty" bson:"suspendUntilSec,omitempty"`   // Yellow cards only: see PlayerStat.SuspendedUntilSec
+	Reason           string       `json:"reason,omitempty" bson:"reason,omitempty"`                     // Technical points and cards: referee's reason
 }
 
 type AwardInfo struct {
@@ -84,6 +94,82 @@ type MatchAwards struct {
 	BestDefender AwardInfo `json:"bestDefender,omitempty" bson:"bestDefender,omitempty"`
 }
 
+// MatchViewership is how many people watched a match live, recorded when the match ends
+type MatchViewership struct {
+	PeakViewers   int64 `json:"peakViewers" bson:"peakViewers"`     // Most viewers connected at once
+	UniqueViewers int64 `json:"uniqueViewers" bson:"uniqueViewers"` // Approximate distinct viewers over the whole match
+}
+
+// Match clock states
+const (
+	ClockStatusNotStarted = "notStarted"
+	ClockStatusRunning    = "running"
+	ClockStatusPaused     = "paused"
+	ClockStatusTimeout    = "timeout"
+	ClockStatusHalfTime   = "halfTime"
+	ClockStatusFullTime   = "fullTime"
+)
+
+// TeamCounts holds a per-team counter such as timeouts or substitutions used
+type TeamCounts struct {
+	TeamA int `json:"teamA" bson:"teamA"`
+	TeamB int `json:"teamB" bson:"teamB"`
+}
+
+// MatchClock is the server-side match clock. While running, the time elapsed in the
+// current half is ElapsedSec plus the time since RunningSince.
+type MatchClock struct {
+	Status              string     `json:"status,omitempty" bson:"status,omitempty"`
+	Half                int        `json:"half,omitempty" bson:"half,omitempty"` // 1 or 2; 0 before kick-off
+	HalfDurationSec     int        `json:"halfDurationSec,omitempty" bson:"halfDurationSec,omitempty"`
+	ElapsedSec          int        `json:"elapsedSec" bson:"elapsedSec"`                                       // Elapsed in the current half up to RunningSince
+	RunningSince        int64      `json:"runningSince,omitempty" bson:"runningSince,omitempty"`               // Unix seconds the clock last started, 0 when stopped
+	TimeoutTeam         string     `json:"timeoutTeam,omitempty" bson:"timeoutTeam,omitempty"`                 // "A" or "B" while a timeout is running
+	TimeoutsUsed        TeamCounts `json:"timeoutsUsed" bson:"timeoutsUsed"`                                   // Current half only
+	SecondHalfStartRaid int        `json:"secondHalfStartRaid,omitempty" bson:"secondHalfStartRaid,omitempty"` // Raid number the second half began at
+	UpdatedAt           int64      `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+}
+
+// Knockout tie-break phases
+const (
+	TieBreakShootout   = "shootout"   // five raids each
+	TieBreakGoldenRaid = "goldenRaid" // still level after the shootout; the next raid to score wins
+	TieBreakDecided    = "decided"
+)
+
+// TieBreakState tracks a knockout tie-break. Its raids are scored by the same rules
+// but their points go to Score, leaving the regulation team scores untouched.
+type TieBreakState struct {
+	Phase      string     `json:"phase,omitempty" bson:"phase,omitempty"`
+	StartRaid  int        `json:"startRaid,omitempty" bson:"startRaid,omitempty"` // Raid number of the first tie-break raid
+	Score      TeamCounts `json:"score" bson:"score"`
+	RaidsTaken TeamCounts `json:"raidsTaken" bson:"raidsTaken"`
+	Winner     string     `json:"winner,omitempty" bson:"winner,omitempty"` // "A" or "B" once decided
+}
+
+// ReviewStatusInProgress marks a raid under referee review; scoring is frozen until the verdict
+const ReviewStatusInProgress = "inProgress"
+
+// ReviewState is the raid review currently in progress, if any
+type ReviewState struct {
+	Status     string `json:"status,omitempty" bson:"status,omitempty"`
+	Team       string `json:"team,omitempty" bson:"team,omitempty"` // "A" or "B", the team that asked for the review
+	RaidNumber int    `json:"raidNumber,omitempty" bson:"raidNumber,omitempty"`
+}
+
+// OutPlayer is a player waiting to be revived and the raid they went out on
+type OutPlayer struct {
+	PlayerID  string `json:"playerId" bson:"playerId"`
+	OutAtRaid int    `json:"outAtRaid,omitempty" bson:"outAtRaid,omitempty"`
+}
+
+// OutQueues holds each team's out players in the order they went out. Revivals
+// take players from the front: first out, first in.
+type OutQueues struct {
+	TeamA []OutPlayer `json:"teamA" bson:"teamA"`
+	TeamB []OutPlayer `json:"teamB" bson:"teamB"`
+}
+
 type EnhancedStatsMessage struct {
 	Type string `json:"type"`
 	Data struct {
@@ -96,6 +182,7 @@ type EnhancedStatsMessage struct {
 		PlayerStats        map[string]PlayerStat `json:"playerStats"`
 		RaidDetails        RaidDetails           `json:"raidDetails"`
 		RaidLog            []RaidLogEntry        `json:"raidLog,omitempty"`
+		RedoLog            []RaidLogEntry        `json:"redoLog,omitempty"` // Raids removed by undo, most recent last
 		PendingLobby       LobbyState            `json:"pendingLobby,omitempty"`
 		Awards             MatchAwards           `json:"awards,omitempty"`
 		RaidNumber         int                   `json:"raidNumber"`
@@ -105,6 +192,15 @@ type EnhancedStatsMessage struct {
 		TossDecision       string                `json:"tossDecision,omitempty" bson:"tossDecision,omitempty"`
 		FirstRaidingTeam   string                `json:"firstRaidingTeam,omitempty" bson:"firstRaidingTeam,omitempty"`
 		LastScoreChangeAt  int64                 `json:"lastScoreChangeAt,omitempty" bson:"lastScoreChangeAt,omitempty"`
+		Rules              RuleSet               `json:"rules" bson:"rules"` // Zero value means DefaultRuleSet
+		Clock              MatchClock            `json:"clock" bson:"clock"`
+		StartingBench      []string              `json:"startingBench,omitempty" bson:"startingBench,omitempty"` // Players on the bench at kick-off, used when rebuilding
+		SubstitutionsUsed  TeamCounts            `json:"substitutionsUsed" bson:"substitutionsUsed"`
+		TieBreak           TieBreakState         `json:"tieBreak" bson:"tieBreak"`
+		Review             ReviewState           `json:"review" bson:"review"`
+		ReviewsUsed        TeamCounts            `json:"reviewsUsed" bson:"reviewsUsed"` // Unsuccessful reviews in the current half
+		OutQueue           OutQueues             `json:"outQueue" bson:"outQueue"`
+		Version            int64                 `json:"version" bson:"version"` // Bumped by every applied scorer command
 		EmptyRaidCounts    struct {
 			TeamA int `json:"teamA"`
 			TeamB int `json:"teamB"`
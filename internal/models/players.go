@@ -13,18 +13,24 @@ type Player struct {
 
 // PlayerStat represents a player’s stats (dynamic keys in MongoDB)
 type PlayerStat struct {
-	Name              string `json:"name" bson:"name"`
-	ID                string `json:"id" bson:"id"`
-	IsCaptain         bool   `json:"isCaptain,omitempty" bson:"isCaptain,omitempty"`
-	IsViceCaptain     bool   `json:"isViceCaptain,omitempty" bson:"isViceCaptain,omitempty"`
-	RaidPoints        int    `json:"raidPoints" bson:"raidPoints"`
-	DefencePoints     int    `json:"defencePoints" bson:"defencePoints"`
-	TotalPoints       int    `json:"totalPoints" bson:"totalPoints"`
-	SuperRaids        int    `json:"superRaids" bson:"superRaids"`
-	SuperTackles      int    `json:"superTackles" bson:"superTackles"`
-	TotalRaids        int    `json:"totalRaids" bson:"totalRaids"`
-	SuccessfulRaids   int    `json:"successfulRaids" bson:"successfulRaids"`
-	TotalTackles      int    `json:"totalTackles" bson:"totalTackles"`
-	SuccessfulTackles int    `json:"successfulTackles" bson:"successfulTackles"`
-	Status            string `json:"status" bson:"status"`
+	Name               string `json:"name" bson:"name"`
+	ID                 string `json:"id" bson:"id"`
+	IsCaptain          bool   `json:"isCaptain,omitempty" bson:"isCaptain,omitempty"`
+	IsViceCaptain      bool   `json:"isViceCaptain,omitempty" bson:"isViceCaptain,omitempty"`
+	RaidPoints         int    `json:"raidPoints" bson:"raidPoints"`
+	DefencePoints      int    `json:"defencePoints" bson:"defencePoints"`
+	TotalPoints        int    `json:"totalPoints" bson:"totalPoints"`
+	SuperRaids         int    `json:"superRaids" bson:"superRaids"`
+	SuperTackles       int    `json:"superTackles" bson:"superTackles"`
+	TotalRaids         int    `json:"totalRaids" bson:"totalRaids"`
+	SuccessfulRaids    int    `json:"successfulRaids" bson:"successfulRaids"`
+	TotalTackles       int    `json:"totalTackles" bson:"totalTackles"`
+	SuccessfulTackles  int    `json:"successfulTackles" bson:"successfulTackles"`
+	Status             string `json:"status" bson:"status"`
+	GreenCards         int    `json:"greenCards,omitempty" bson:"greenCards,omitempty"`
+	YellowCards        int    `json:"yellowCards,omitempty" bson:"yellowCards,omitempty"`
+	RedCards           int    `json:"redCards,omitempty" bson:"redCards,omitempty"`
+	SuspendedUntilRaid int    `json:"suspendedUntilRaid,omitempty" bson:"suspendedUntilRaid,omitempty"` // Yellow card: returns once this raid number is reached
+	SuspendedUntilSec  int    `json:"suspendedUntilSec,omitempty" bson:"suspendedUntilSec,omitempty"`   // Yellow card: returns once this much match time (both halves) has been played
+	SentOffFromBench   bool   `json:"sentOffFromBench,omitempty" bson:"sentOffFromBench,omitempty"`     // Red card shown to a substitute, who never held a place in the squad
 }
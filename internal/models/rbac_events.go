@@ -37,6 +37,15 @@ type Event struct {
 	MaxTeams           int                `bson:"max_teams,omitempty"`
 	ParticipatingTeams []EventTeamEntry   `bson:"participating_teams"`
 	Status             string             `bson:"status"`
+	RuleSet            *RuleSet           `bson:"rule_set,omitempty"` // nil means DefaultRuleSet
 	CreatedAt          time.Time          `bson:"created_at"`
 	UpdatedAt          time.Time          `bson:"updated_at"`
 }
+
+// Rules returns the rule set matches in this event are played under
+func (e Event) Rules() RuleSet {
+	if e.RuleSet == nil {
+		return DefaultRuleSet()
+	}
+	return e.RuleSet.OrDefault()
+}
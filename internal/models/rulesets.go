@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule set presets selectable by organizers
+const (
+	RuleSetPro     = "Pro"
+	RuleSetAmateur = "Amateur"
+	RuleSetYouth   = "Youth"
+	RuleSetCustom  = "Custom"
+)
+
+// RuleSet holds the scoring thresholds a match is played under.
+// It is attached to an Event and copied onto the live match state when scoring starts.
+type RuleSet struct {
+	Name                    string `json:"name" bson:"name"`
+	SquadSize               int    `json:"squadSize" bson:"squadSize"`                             // Players per team on the mat
+	AllOutBonus             int    `json:"allOutBonus" bson:"allOutBonus"`                         // Extra points for inflicting an all out
+	SuperTackleMaxDefenders int    `json:"superTackleMaxDefenders" bson:"superTackleMaxDefenders"` // A tackle is super when this many or fewer defenders are on the mat (0 disables)
+	DoOrDieEmptyRaids       int    `json:"doOrDieEmptyRaids" bson:"doOrDieEmptyRaids"`             // Consecutive empty raids before a do-or-die raid (0 disables)
+	SuperRaidPoints         int    `json:"superRaidPoints" bson:"superRaidPoints"`                 // Touch points needed for a super raid
+	HalfMinutes             int    `json:"halfMinutes" bson:"halfMinutes"`                         // Length of each half
+	TimeoutsPerHalf         int    `json:"timeoutsPerHalf" bson:"timeoutsPerHalf"`                 // Timeouts each team may call per half
+	BenchSize               int    `json:"benchSize" bson:"benchSize"`                             // Substitutes a team may name
+	SubstitutionsPerTeam    int    `json:"substitutionsPerTeam" bson:"substitutionsPerTeam"`       // Substitutions each team may make per match
+	YellowCardMinutes       int    `json:"yellowCardMinutes" bson:"yellowCardMinutes"`             // Yellow card suspension in match clock minutes, used while the clock is in use
+	YellowCardRaids         int    `json:"yellowCardRaids" bson:"yellowCardRaids"`                 // Yellow card suspension in raids, used when the clock is not
+	ReviewsPerHalf          int    `json:"reviewsPerHalf" bson:"reviewsPerHalf"`                   // Unsuccessful raid reviews each team may make per half (0 disables reviews)
+}
+
+var ruleSetPresets = []RuleSet{
+	{Name: RuleSetPro, SquadSize: 7, AllOutBonus: 2, SuperTackleMaxDefenders: 3, DoOrDieEmptyRaids: 2, SuperRaidPoints: 3, HalfMinutes: 20, TimeoutsPerHalf: 2, BenchSize: 5, SubstitutionsPerTeam: 5, YellowCardMinutes: 2, YellowCardRaids: 4, ReviewsPerHalf: 1},
+	{Name: RuleSetAmateur, SquadSize: 7, AllOutBonus: 2, SuperTackleMaxDefenders: 3, DoOrDieEmptyRaids: 0, SuperRaidPoints: 3, HalfMinutes: 20, TimeoutsPerHalf: 2, BenchSize: 5, SubstitutionsPerTeam: 5, YellowCardMinutes: 2, YellowCardRaids: 4},
+	{Name: RuleSetYouth, SquadSize: 5, AllOutBonus: 2, SuperTackleMaxDefenders: 2, DoOrDieEmptyRaids: 0, SuperRaidPoints: 3, HalfMinutes: 15, TimeoutsPerHalf: 1, BenchSize: 3, SubstitutionsPerTeam: 3, YellowCardMinutes: 2, YellowCardRaids: 4},
+}
+
+// DefaultRuleSet returns the rules used when an event has none configured
+func DefaultRuleSet() RuleSet {
+	return ruleSetPresets[0]
+}
+
+// RuleSetPresets returns a copy of all named presets
+func RuleSetPresets() []RuleSet {
+	presets := make([]RuleSet, len(ruleSetPresets))
+	copy(presets, ruleSetPresets)
+	return presets
+}
+
+// RuleSetPreset looks up a preset by name (case-insensitive)
+func RuleSetPreset(name string) (RuleSet, bool) {
+	for _, preset := range ruleSetPresets {
+		if strings.EqualFold(preset.Name, strings.TrimSpace(name)) {
+			return preset, true
+		}
+	}
+	return RuleSet{}, false
+}
+
+// IsZero reports whether no rules have been set
+func (r RuleSet) IsZero() bool {
+	return r == RuleSet{}
+}
+
+// OrDefault returns r, or the default rule set if r is unset
+func (r RuleSet) OrDefault() RuleSet {
+	if r.IsZero() {
+		return DefaultRuleSet()
+	}
+	return r
+}
+
+// Validate checks that every threshold is within a playable range
+func (r RuleSet) Validate() error {
+	if r.SquadSize < 2 || r.SquadSize > 12 {
+		return fmt.Errorf("squadSize must be between 2 and 12")
+	}
+	if r.AllOutBonus < 0 {
+		return fmt.Errorf("allOutBonus cannot be negative")
+	}
+	if r.SuperTackleMaxDefenders < 0 || r.SuperTackleMaxDefenders >= r.SquadSize {
+		return fmt.Errorf("superTackleMaxDefenders must be between 0 and squadSize-1")
+	}
+	if r.DoOrDieEmptyRaids < 0 {
+		return fmt.Errorf("doOrDieEmptyRaids cannot be negative")
+	}
+	if r.SuperRaidPoints < 1 {
+		return fmt.Errorf("superRaidPoints must be at least 1")
+	}
+	if r.HalfMinutes < 1 || r.HalfMinutes > 60 {
+		return fmt.Errorf("halfMinutes must be between 1 and 60")
+	}
+	if r.TimeoutsPerHalf < 0 {
+		return fmt.Errorf("timeoutsPerHalf cannot be negative")
+	}
+	if r.BenchSize < 0 || r.SubstitutionsPerTeam < 0 {
+		return fmt.Errorf("benchSize and substitutionsPerTeam cannot be negative")
+	}
+	if r.YellowCardMinutes < 0 || r.YellowCardRaids < 0 {
+		return fmt.Errorf("yellow card suspension cannot be negative")
+	}
+	if r.ReviewsPerHalf < 0 {
+		return fmt.Errorf("reviewsPerHalf cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Scorer socket roles. One chief holds the scorer lock and scores. Assistants
+// propose commands for the chief to confirm or reject. Mirrors follow the
+// scorer's view and cannot change anything.
+const (
+	ScorerRoleChief     = "chief"
+	ScorerRoleAssistant = "assistant"
+	ScorerRoleMirror    = "mirror"
+)
+
+// ScorerConnection is one socket connected to a match's scorer side
+type ScorerConnection struct {
+	ID          string    `json:"id"`
+	Role        string    `json:"role"`
+	UserID      string    `json:"userId"`
+	SessionID   string    `json:"sessionId"`
+	Device      string    `json:"device,omitempty"` // label the page chose, such as "scoreboard-1"
+	ConnectedAt time.Time `json:"connectedAt"`
+	SeenAt      time.Time `json:"seenAt"`
+}
+
+// ScorerHandoffTarget names who may take over the scorer lock. Every field given must match.
+// Device labels are chosen by the client, so they only narrow down a user or session.
+type ScorerHandoffTarget struct {
+	UserID    string `json:"userId,omitempty"`
+	SessionID string `json:"sessionId,omitempty"`
+	Device    string `json:"device,omitempty"`
+}
+
+// NamesScorer reports whether the target names a user or a session
+func (t ScorerHandoffTarget) NamesScorer() bool {
+	return t.UserID != "" || t.SessionID != ""
+}
+
+// Matches reports whether a joining chief is the one the handoff names
+func (t ScorerHandoffTarget) Matches(userID, sessionID, device string) bool {
+	if !t.NamesScorer() {
+		return false
+	}
+	return (t.UserID == "" || t.UserID == userID) &&
+		(t.SessionID == "" || t.SessionID == sessionID) &&
+		(t.Device == "" || t.Device == device)
+}
+
+// ScorerHandoff is a scorer lock transfer that waits for the named scorer to join
+type ScorerHandoff struct {
+	ID        string              `json:"id"`
+	From      MatchEventActor     `json:"from"`
+	To        ScorerHandoffTarget `json:"to"`
+	CreatedAt time.Time           `json:"createdAt"`
+	ExpiresAt time.Time           `json:"expiresAt"`
+}
+
+// ScorerProposal is a command an assistant scorer wants the chief to apply
+type ScorerProposal struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Command    WSEnvelope      `json:"command"`
+	ProposedBy MatchEventActor `json:"proposedBy"`
+	ProposedAt time.Time       `json:"proposedAt"`
+}
+
+// Proposal outcomes
+const (
+	ProposalConfirmed = "confirmed"
+	ProposalRejected  = "rejected" // by the chief, or by the rules engine on confirmation
+)
+
+// ScorerRoomState is who is on a match's scorer side: the lock holder, every
+// connection, any handoff in progress and the proposals awaiting the chief
+type ScorerRoomState struct {
+	MatchID     string             `json:"matchId"`
+	LockHolder  *MatchEventActor   `json:"lockHolder,omitempty"`
+	Connections []ScorerConnection `json:"connections"`
+	Handoff     *ScorerHandoff     `json:"handoff,omitempty"`
+	Proposals   []ScorerProposal   `json:"proposals"`
+}
+
+// WSProposalVerdictPayload confirms or rejects a proposal
+type WSProposalVerdictPayload struct {
+	ProposalID string `json:"proposalId"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// WSProposalResolvedPayload tells the scorer side what became of a proposal
+type WSProposalResolvedPayload struct {
+	ProposalID string          `json:"proposalId"`
+	Status     string          `json:"status"`
+	Reason     string          `json:"reason,omitempty"`
+	Version    int64           `json:"version,omitempty"`
+	Events     json.RawMessage `json:"events,omitempty"`
+}
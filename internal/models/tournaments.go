@@ -57,10 +57,17 @@ type Fixture struct {
 	Team1Score   int                 `json:"team1Score,omitempty" bson:"team1Score,omitempty"`
 	Team2Score   int                 `json:"team2Score,omitempty" bson:"team2Score,omitempty"`
 	IsDraw       bool                `json:"isDraw" bson:"isDraw"`
+	TieBreak     *FixtureTieBreak    `json:"tieBreak,omitempty" bson:"tieBreak,omitempty"` // Set when a knockout was decided by tie-break
 	CreatedAt    time.Time           `json:"createdAt" bson:"createdAt"`
 	UpdatedAt    time.Time           `json:"updatedAt" bson:"updatedAt"`
 }
 
+// FixtureTieBreak records the tie-break scores of a knockout fixture that ended level
+type FixtureTieBreak struct {
+	Team1Score int `json:"team1Score" bson:"team1Score"`
+	Team2Score int `json:"team2Score" bson:"team2Score"`
+}
+
 // PointsTableEntry represents a team's standing in the tournament
 type PointsTableEntry struct {
 	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
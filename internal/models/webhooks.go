@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Webhook event names
+const (
+	WebhookMatchStarted          = "match.started"
+	WebhookRaidScored            = "raid.scored"
+	WebhookAllOut                = "match.allOut"
+	WebhookMatchCompleted        = "match.completed"
+	WebhookFixtureGenerated      = "fixture.generated"
+	WebhookTournamentCompleted   = "tournament.completed"
+	WebhookChampionshipCompleted = "championship.completed"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookMatchStarted,
+	WebhookRaidScored,
+	WebhookAllOut,
+	WebhookMatchCompleted,
+	WebhookFixtureGenerated,
+	WebhookTournamentCompleted,
+	WebhookChampionshipCompleted,
+}
+
+// Webhook delivery states
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed" // gave up after the last retry
+)
+
+// Webhook is a URL an organizer registered to hear about one of their events.
+// Deliveries are signed with Secret.
+type Webhook struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	EventID     primitive.ObjectID `json:"eventId" bson:"event_id"`
+	OrganizerID primitive.ObjectID `json:"organizerId" bson:"organizer_id"`
+	URL         string             `json:"url" bson:"url"`
+	Secret      string             `json:"secret,omitempty" bson:"secret"`
+	Events      []string           `json:"events" bson:"events"` // empty means all events
+	Active      bool               `json:"active" bson:"active"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updated_at"`
+}
+
+// WebhookDelivery is one webhook event sent, or being sent, to one URL
+type WebhookDelivery struct {
+	ID             primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	WebhookID      primitive.ObjectID  `json:"webhookId" bson:"webhook_id"`
+	EventID        primitive.ObjectID  `json:"eventId" bson:"event_id"`
+	Event          string              `json:"event" bson:"event"`
+	Data           string              `json:"data" bson:"data"` // JSON payload, kept verbatim so replays send the same data
+	Status         string              `json:"status" bson:"status"`
+	Attempts       int                 `json:"attempts" bson:"attempts"`
+	LastStatusCode int                 `json:"lastStatusCode,omitempty" bson:"last_status_code,omitempty"`
+	LastError      string              `json:"lastError,omitempty" bson:"last_error,omitempty"`
+	NextAttemptAt  time.Time           `json:"nextAttemptAt" bson:"next_attempt_at"`
+	ReplayOf       *primitive.ObjectID `json:"replayOf,omitempty" bson:"replay_of,omitempty"`
+	OccurredAt     time.Time           `json:"occurredAt" bson:"occurred_at"`
+	CreatedAt      time.Time           `json:"createdAt" bson:"created_at"`
+	DeliveredAt    *time.Time          `json:"deliveredAt,omitempty" bson:"delivered_at,omitempty"`
+}
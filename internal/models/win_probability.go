@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// WinProbability is each team's estimated chance of winning, between 0 and 1
+type WinProbability struct {
+	TeamA float64 `json:"teamA" bson:"teamA"`
+	TeamB float64 `json:"teamB" bson:"teamB"`
+}
+
+// WinProbModel holds the weights of the win-probability model. It is a logistic
+// model without intercept, so level matches start at even chances.
+type WinProbModel struct {
+	Margin         float64   `json:"margin" bson:"margin"`                 // Per point of lead, growing as time runs out
+	Mat            float64   `json:"mat" bson:"mat"`                       // Per player more on the mat than the opponent
+	DoOrDie        float64   `json:"doOrDie" bson:"doOrDie"`               // Against the team whose next raid is do-or-die
+	RaidsPerMinute float64   `json:"raidsPerMinute" bson:"raidsPerMinute"` // Turns raid numbers into match time when the clock is not used
+	Matches        int       `json:"matches" bson:"matches"`               // Historical matches it was fitted on; 0 for the default
+	Samples        int       `json:"samples" bson:"samples"`               // Match states it was fitted on
+	CalibratedAt   time.Time `json:"calibratedAt,omitempty" bson:"calibratedAt,omitempty"`
+}
+
+// DefaultWinProbModel returns the weights used until enough matches have been
+// played to calibrate them
+func DefaultWinProbModel() WinProbModel {
+	return WinProbModel{Margin: 0.25, Mat: 0.15, DoOrDie: 0.3, RaidsPerMinute: 2}
+}
+
+// WinProbPoint is the win probability after one raid log entry. Index is the
+// entry's position in the raid log, as for CommentaryEntry.
+type WinProbPoint struct {
+	MatchID    string         `json:"matchId" bson:"matchId"`
+	Index      int            `json:"index" bson:"index"`
+	RaidNumber int            `json:"raidNumber" bson:"raidNumber"`
+	ScoreA     int            `json:"scoreA" bson:"scoreA"`
+	ScoreB     int            `json:"scoreB" bson:"scoreB"`
+	WinProb    WinProbability `json:"winProbability" bson:"winProbability"`
+	CreatedAt  time.Time      `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,115 @@
+package models
+
+import "encoding/json"
+
+// Scorer socket protocol versions. Version 1 is the original untyped format, where
+// the server works out what a message means from its fields. It is still spoken to
+// scorer pages whose join message carries no version.
+const (
+	WSProtocolLegacy  = 1
+	WSProtocolCurrent = 2
+)
+
+// WSEnvelope wraps every message on a version 2 scorer socket, in both directions.
+// The client picks ID for each command; the ack, error or conflict answering it
+// carries the same ID.
+type WSEnvelope struct {
+	V           int             `json:"v"`
+	Type        string          `json:"type"`
+	ID          string          `json:"id,omitempty"`
+	BaseVersion *int64          `json:"baseVersion,omitempty"` // Commands only: the state version the command builds on
+	At          int64           `json:"at,omitempty"`          // Commands only: when the scorer issued it, in Unix milliseconds
+	Payload     json.RawMessage `json:"payload,omitempty"`
+}
+
+// Message types sent by the server on a version 2 scorer socket. Clients send
+// WSTypeJoin first and then one of the scoring command types, WSTypeBatch, or one
+// of the chief's control messages.
+const (
+	WSTypeJoin             = "join"
+	WSTypeBatch            = "batch"            // commands queued while the scorer was offline
+	WSTypeBatchResult      = "batchResult"      // answers a batch, command by command
+	WSTypeConfirmProposal  = "confirmProposal"  // chief: apply an assistant's proposal
+	WSTypeRejectProposal   = "rejectProposal"   // chief: discard an assistant's proposal
+	WSTypeHandoff          = "handoff"          // chief: hand the scorer lock to someone else
+	WSTypeWelcome          = "welcome"          // answers join with the negotiated version
+	WSTypeState            = "state"            // full match state
+	WSTypeRequestInit      = "requestInit"      // no state yet; send initialState
+	WSTypeAck              = "ack"              // a command was applied, now or earlier
+	WSTypeConflict         = "conflict"         // a command was based on an old state version
+	WSTypeError            = "error"            // a message was rejected
+	WSTypeTakeover         = "scorerTakeover"   // another device took over scoring
+	WSTypeHandedOff        = "scorerHandoff"    // the chief handed the lock on; the socket closes
+	WSTypeProposal         = "proposal"         // an assistant proposed a command
+	WSTypeProposalResolved = "proposalResolved" // a proposal was confirmed or rejected
+	WSTypeScorerRoom       = "scorerRoom"       // who is on the scorer side (see ScorerRoomState)
+)
+
+// Error codes carried by WSTypeError messages
+const (
+	WSErrBadMessage         = "bad_message"
+	WSErrUnknownType        = "unknown_type"
+	WSErrUnsupportedVersion = "unsupported_version"
+	WSErrScorerLocked       = "scorer_locked"
+	WSErrNotInitialized     = "not_initialized"
+	WSErrCommandRejected    = "command_rejected" // the rules engine refused the command
+	WSErrReadOnly           = "read_only"        // mirrors cannot send commands
+	WSErrNotChief           = "not_chief"        // only the chief can confirm, reject or hand off
+	WSErrForbidden          = "forbidden"        // the caller may not join the scorer side in that role
+	WSErrInternal           = "internal"
+)
+
+// WSJoinPayload opens a scorer session. Versions lists the protocol versions the
+// client speaks; the server answers with the highest one it also speaks.
+// Role is one of the ScorerRole values and defaults to chief; Device labels the
+// connection so a handoff can name it.
+type WSJoinPayload struct {
+	MatchID  string `json:"matchId"`
+	Versions []int  `json:"versions"`
+	Role     string `json:"role,omitempty"`
+	Device   string `json:"device,omitempty"`
+}
+
+// WSWelcomePayload confirms the negotiated version and lists what it supports
+type WSWelcomePayload struct {
+	Version  int      `json:"version"`
+	Commands []string `json:"commands"`
+	Events   []string `json:"events"`
+}
+
+// WSErrorPayload describes why a message was rejected
+type WSErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// WSBatchPayload carries the commands a scorer queued while offline, oldest first.
+// Each is a full command envelope.
+type WSBatchPayload struct {
+	Commands []WSEnvelope `json:"commands"`
+}
+
+// Outcomes of a command in a batch
+const (
+	WSBatchApplied   = "applied"
+	WSBatchDuplicate = "duplicate" // applied by an earlier send
+	WSBatchRejected  = "rejected"  // refused for good; Code and Reason say why
+)
+
+// WSBatchCommandResult is what happened to one command of a batch
+type WSBatchCommandResult struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Version int64  `json:"version,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// WSBatchResultPayload answers a batch. Commands after a server failure have no
+// result and should be sent again. State is the match after the batch.
+type WSBatchResultPayload struct {
+	Results []WSBatchCommandResult `json:"results"`
+	Version int64                  `json:"version"`
+	State   *EnhancedStatsMessage  `json:"state,omitempty"`
+}
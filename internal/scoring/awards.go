@@ -0,0 +1,55 @@
+package scoring
+
+import (
+	"sort"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// ComputeAwards picks the MVP, best raider and best defender from player stats.
+// Ties go to the lowest player ID so replays always pick the same winner.
+func ComputeAwards(playerStats map[string]models.PlayerStat) models.MatchAwards {
+	awards := models.MatchAwards{}
+	if len(playerStats) == 0 {
+		return awards
+	}
+
+	mvp := models.AwardInfo{Points: -1}
+	bestRaider := models.AwardInfo{Points: -1}
+	bestDefender := models.AwardInfo{Points: -1}
+
+	ids := make([]string, 0, len(playerStats))
+	for id := range playerStats {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		stat := playerStats[id]
+		name := stat.Name
+		if stat.TotalPoints > mvp.Points {
+			mvp = models.AwardInfo{PlayerId: id, Name: name, Points: stat.TotalPoints}
+		}
+		if stat.RaidPoints > bestRaider.Points {
+			bestRaider = models.AwardInfo{PlayerId: id, Name: name, Points: stat.RaidPoints}
+		}
+		if stat.DefencePoints > bestDefender.Points {
+			bestDefender = models.AwardInfo{PlayerId: id, Name: name, Points: stat.DefencePoints}
+		}
+	}
+
+	if mvp.Points < 0 {
+		mvp.Points = 0
+	}
+	if bestRaider.Points < 0 {
+		bestRaider.Points = 0
+	}
+	if bestDefender.Points < 0 {
+		bestDefender.Points = 0
+	}
+
+	awards.MVP = mvp
+	awards.BestRaider = bestRaider
+	awards.BestDefender = bestDefender
+	return awards
+}
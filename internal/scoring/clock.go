@@ -0,0 +1,179 @@
+package scoring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// Clock actions accepted by the clock command
+const (
+	ClockStart         = "start" // kick-off, or start of the second half after half time
+	ClockPause         = "pause"
+	ClockResume        = "resume" // also ends a timeout
+	ClockEndHalf       = "endHalf"
+	ClockTimeout       = "timeout"
+	ClockEndRegulation = "endRegulation"
+)
+
+// ClockPayload is a match clock instruction from the scorer
+type ClockPayload struct {
+	Action string `json:"action"`
+	Team   string `json:"team,omitempty"` // "A" or "B" for timeouts
+}
+
+// applyClock moves the match clock according to the requested action. Time is
+// taken from the command so replaying the event store reproduces the same clock.
+func applyClock(match *models.EnhancedStatsMessage, p ClockPayload, at time.Time) ([]Event, error) {
+	clock := &match.Data.Clock
+	if clock.Status == "" {
+		clock.Status = models.ClockStatusNotStarted
+	}
+	rules := rulesOf(match)
+	now := at.Unix()
+
+	var event Event
+	switch p.Action {
+	case ClockStart:
+		switch clock.Status {
+		case models.ClockStatusNotStarted:
+			clock.Half = 1
+		case models.ClockStatusHalfTime:
+			clock.Half = 2
+			clock.SecondHalfStartRaid = match.Data.RaidNumber
+			clock.TimeoutsUsed = models.TeamCounts{}
+			match.Data.ReviewsUsed = models.TeamCounts{}
+		default:
+			return nil, fmt.Errorf("cannot start the clock while it is %s", clock.Status)
+		}
+		halfMinutes := rules.HalfMinutes
+		if halfMinutes <= 0 {
+			halfMinutes = models.DefaultRuleSet().HalfMinutes
+		}
+		clock.HalfDurationSec = halfMinutes * 60
+		clock.ElapsedSec = 0
+		clock.RunningSince = now
+		clock.Status = models.ClockStatusRunning
+		event = Event{Type: EventHalfStarted}
+
+	case ClockPause:
+		if clock.Status != models.ClockStatusRunning {
+			return nil, fmt.Errorf("clock is not running")
+		}
+		stopClock(clock, now)
+		clock.Status = models.ClockStatusPaused
+		event = Event{Type: EventClockPaused}
+
+	case ClockResume:
+		if clock.Status != models.ClockStatusPaused && clock.Status != models.ClockStatusTimeout {
+			return nil, fmt.Errorf("clock is not paused")
+		}
+		clock.TimeoutTeam = ""
+		clock.RunningSince = now
+		clock.Status = models.ClockStatusRunning
+		event = Event{Type: EventClockResumed}
+
+	case ClockTimeout:
+		if clock.Status != models.ClockStatusRunning && clock.Status != models.ClockStatusPaused {
+			return nil, fmt.Errorf("a timeout can only be called during play")
+		}
+		if p.Team != "A" && p.Team != "B" {
+			return nil, fmt.Errorf("timeout team must be A or B")
+		}
+		used := &clock.TimeoutsUsed.TeamA
+		if p.Team == "B" {
+			used = &clock.TimeoutsUsed.TeamB
+		}
+		if *used >= rules.TimeoutsPerHalf {
+			return nil, fmt.Errorf("team %s has no timeouts left this half", p.Team)
+		}
+		*used++
+		stopClock(clock, now)
+		clock.TimeoutTeam = p.Team
+		clock.Status = models.ClockStatusTimeout
+		event = Event{Type: EventTimeout, Team: p.Team}
+
+	case ClockEndHalf:
+		if clock.Half != 1 || !clockInPlay(clock) {
+			return nil, fmt.Errorf("the first half is not in progress")
+		}
+		stopClock(clock, now)
+		clock.TimeoutTeam = ""
+		clock.Status = models.ClockStatusHalfTime
+		// Lobby touches do not carry over the break
+		match.Data.PendingLobby = models.LobbyState{}
+		event = Event{Type: EventHalfEnded}
+
+	case ClockEndRegulation:
+		if clock.Half != 2 || !clockInPlay(clock) {
+			return nil, fmt.Errorf("the second half is not in progress")
+		}
+		stopClock(clock, now)
+		clock.TimeoutTeam = ""
+		clock.Status = models.ClockStatusFullTime
+		event = Event{Type: EventRegulationEnded}
+
+	default:
+		return nil, fmt.Errorf("unknown clock action: %s", p.Action)
+	}
+
+	clock.UpdatedAt = now
+	event.RaidNumber = match.Data.RaidNumber
+	event.Half = clock.Half
+	return []Event{event}, nil
+}
+
+// stopClock folds the running time into ElapsedSec
+func stopClock(clock *models.MatchClock, now int64) {
+	if clock.RunningSince > 0 && now > clock.RunningSince {
+		clock.ElapsedSec += int(now - clock.RunningSince)
+	}
+	clock.RunningSince = 0
+}
+
+// clockInPlay reports whether a half is under way (running, paused or in a timeout)
+func clockInPlay(clock *models.MatchClock) bool {
+	switch clock.Status {
+	case models.ClockStatusRunning, models.ClockStatusPaused, models.ClockStatusTimeout:
+		return true
+	}
+	return false
+}
+
+// checkClockAllowsPlay rejects scoring while the match is at half time or finished.
+// Matches that never started the clock can always be scored, and tie-break raids
+// are played after full time.
+func checkClockAllowsPlay(match *models.EnhancedStatsMessage) error {
+	if match.Data.TieBreak.Phase == models.TieBreakDecided {
+		return fmt.Errorf("the tie-break has been decided")
+	}
+	if tieBreakActive(match) {
+		return nil
+	}
+	switch match.Data.Clock.Status {
+	case models.ClockStatusHalfTime:
+		return fmt.Errorf("match is at half time; start the second half first")
+	case models.ClockStatusFullTime:
+		return fmt.Errorf("regulation time has ended")
+	}
+	return nil
+}
+
+// ClockElapsed returns the seconds played in the current half as of at
+func ClockElapsed(clock models.MatchClock, at time.Time) int {
+	elapsed := clock.ElapsedSec
+	if clock.RunningSince > 0 && at.Unix() > clock.RunningSince {
+		elapsed += int(at.Unix() - clock.RunningSince)
+	}
+	return elapsed
+}
+
+// ClockRemaining returns the seconds left in the current half as of at, never negative
+func ClockRemaining(clock models.MatchClock, at time.Time) int {
+	remaining := clock.HalfDurationSec - ClockElapsed(clock, at)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
@@ -0,0 +1,147 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func clockCmd(action, team string, at time.Time) Command {
+	return Command{Type: CommandClock, Clock: ClockPayload{Action: action, Team: team}, At: at}
+}
+
+func TestClockCommands(t *testing.T) {
+	tests := []struct {
+		name        string
+		actions     []ClockPayload
+		wantErr     bool
+		wantStatus  string
+		wantHalf    int
+		wantElapsed int
+	}{
+		{
+			name:       "kick-off starts the first half",
+			actions:    []ClockPayload{{Action: ClockStart}},
+			wantStatus: models.ClockStatusRunning,
+			wantHalf:   1,
+		},
+		{
+			name:        "pause keeps the elapsed time",
+			actions:     []ClockPayload{{Action: ClockStart}, {Action: ClockPause}},
+			wantStatus:  models.ClockStatusPaused,
+			wantHalf:    1,
+			wantElapsed: 60,
+		},
+		{
+			name:        "resume after a timeout",
+			actions:     []ClockPayload{{Action: ClockStart}, {Action: ClockTimeout, Team: "B"}, {Action: ClockResume}},
+			wantStatus:  models.ClockStatusRunning,
+			wantHalf:    1,
+			wantElapsed: 60,
+		},
+		{
+			name:    "timeouts are limited per half",
+			actions: []ClockPayload{{Action: ClockStart}, {Action: ClockTimeout, Team: "A"}, {Action: ClockResume}, {Action: ClockTimeout, Team: "A"}, {Action: ClockResume}, {Action: ClockTimeout, Team: "A"}},
+			wantErr: true,
+		},
+		{
+			name:    "timeout needs a team",
+			actions: []ClockPayload{{Action: ClockStart}, {Action: ClockTimeout}},
+			wantErr: true,
+		},
+		{
+			name:       "second half starts from half time",
+			actions:    []ClockPayload{{Action: ClockStart}, {Action: ClockEndHalf}, {Action: ClockStart}},
+			wantStatus: models.ClockStatusRunning,
+			wantHalf:   2,
+		},
+		{
+			name:        "end of regulation",
+			actions:     []ClockPayload{{Action: ClockStart}, {Action: ClockEndHalf}, {Action: ClockStart}, {Action: ClockEndRegulation}},
+			wantStatus:  models.ClockStatusFullTime,
+			wantHalf:    2,
+			wantElapsed: 60,
+		},
+		{
+			name:    "regulation cannot end in the first half",
+			actions: []ClockPayload{{Action: ClockStart}, {Action: ClockEndRegulation}},
+			wantErr: true,
+		},
+		{
+			name:    "cannot pause before kick-off",
+			actions: []ClockPayload{{Action: ClockPause}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestMatch()
+			at := testTime
+			var err error
+			for _, action := range tt.actions {
+				state, _, err = Apply(state, clockCmd(action.Action, action.Team, at))
+				if err != nil {
+					break
+				}
+				at = at.Add(time.Minute)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			clock := state.Data.Clock
+			if clock.Status != tt.wantStatus || clock.Half != tt.wantHalf {
+				t.Errorf("clock = %s half %d, want %s half %d", clock.Status, clock.Half, tt.wantStatus, tt.wantHalf)
+			}
+			if clock.ElapsedSec != tt.wantElapsed {
+				t.Errorf("elapsed = %d, want %d", clock.ElapsedSec, tt.wantElapsed)
+			}
+		})
+	}
+}
+
+func TestSecondHalfRaidingAlternation(t *testing.T) {
+	state := newTestMatch()
+	at := testTime
+	apply := func(cmd Command) {
+		t.Helper()
+		var err error
+		state, _, err = Apply(state, cmd)
+		if err != nil {
+			t.Fatalf("%s: %v", cmd.Type, err)
+		}
+	}
+
+	apply(clockCmd(ClockStart, "", at))
+	apply(raid("empty", "a1", false)) // raid 1: A
+	apply(raid("empty", "b1", false)) // raid 2: B
+	apply(raid("empty", "a2", false)) // raid 3: A
+	apply(clockCmd(ClockEndHalf, "", at.Add(20*time.Minute)))
+
+	if _, _, err := Apply(state, raid("empty", "b2", false)); err == nil {
+		t.Fatalf("expected raids to be rejected at half time")
+	}
+
+	apply(clockCmd(ClockStart, "", at.Add(30*time.Minute)))
+	if got := ExpectedRaidingTeam(&state); got != "B" {
+		t.Fatalf("second half opened by team %s, want B", got)
+	}
+	apply(raid("empty", "b2", false)) // raid 4: B opens the second half
+	if got := ExpectedRaidingTeam(&state); got != "A" {
+		t.Fatalf("second raid of the half by team %s, want A", got)
+	}
+
+	// Undoing back into the first half uses first half alternation again
+	apply(Command{Type: CommandUndo, At: at})
+	apply(Command{Type: CommandUndo, At: at})
+	if got := ExpectedRaidingTeam(&state); got != "A" {
+		t.Fatalf("raid 3 belongs to team %s, want A", got)
+	}
+}
@@ -0,0 +1,225 @@
+package scoring
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// Commentary templates. Each slot has a few wordings; the seed passed to
+// Commentary picks one, so a raid reads the same every time it is generated.
+var (
+	doOrDieOpeners = []string{
+		"Do-or-die raid for %[1]s!",
+		"Do-or-die for %[1]s.",
+		"It's do-or-die now for %[1]s.",
+	}
+	raidSuccessLines = []string{
+		"%[1]s picks up %[2]s%[3]s.",
+		"%[1]s touches %[4]s and makes it back%[3]s.",
+		"Successful raid by %[1]s, %[2]s%[3]s.",
+		"%[1]s gets the better of %[4]s: %[2]s%[3]s.",
+	}
+	superRaidLines = []string{
+		"SUPER RAID! %[1]s takes %[2]d in one go!",
+		"What a raid! %[1]s with a super raid!",
+		"Super raid from %[1]s!",
+	}
+	defenseSuccessLines = []string{
+		"%[1]s is brought down by %[2]s.",
+		"Great tackle! %[2]s stop %[1]s.",
+		"%[1]s is caught! Well held by %[2]s.",
+		"No way through for %[1]s, %[2]s make the tackle.",
+	}
+	tackleOnlyLines = []string{
+		"%[1]s is tackled.",
+		"%[1]s is caught and out.",
+	}
+	superTackleLines = []string{
+		"SUPER TACKLE! Two points for %[1]s.",
+		"Super tackle from %[1]s!",
+		"%[1]s pull off a super tackle!",
+	}
+	bonusBeforeTackleLines = []string{
+		"%[1]s had the bonus, so %[2]s still get a point.",
+		"The bonus counts, a point for %[2]s.",
+	}
+	emptyRaidLines = []string{
+		"Empty raid by %[1]s.",
+		"%[1]s comes back with nothing.",
+		"No points on that raid from %[1]s.",
+	}
+	emptyBonusLines = []string{
+		"%[1]s takes the bonus and heads back.",
+		"Just the bonus for %[1]s.",
+		"%[1]s sneaks a bonus point.",
+	}
+	doOrDieSafeLines = []string{
+		"%[1]s survives with a bonus.",
+		"%[1]s gets the bonus and stays in.",
+	}
+	doOrDieOutLines = []string{
+		"%[1]s comes back empty-handed and is out. A point to %[2]s.",
+		"%[1]s fails to score and is out, %[2]s get the point.",
+	}
+	lobbyRaiderLines = []string{
+		"%[1]s steps into the lobby untouched and is out. A point to %[2]s.",
+		"Lobby! %[1]s is out for stepping in without a touch, a point to %[2]s.",
+	}
+	lobbyDefenderLines = []string{
+		"%[1]s steps into the lobby and is out! A point to %[2]s.",
+		"Lobby! %[1]s is out, %[2]s get a point.",
+	}
+	allOutLines = []string{
+		"ALL OUT! %[1]s are wiped off the mat, %[3]d bonus points to %[2]s.",
+		"%[2]s inflict an all out on %[1]s! %[3]d extra points.",
+		"All out! %[1]s have nobody left, %[2]s get %[3]d bonus points.",
+	}
+)
+
+// Commentary turns a raid log entry into lines of text for viewers. events are
+// the events the entry produced when known; they add the all-out line. Entries
+// that are not raids or lobby touches have no commentary. With withScore the
+// match's current score is added as the last line.
+func Commentary(match *models.EnhancedStatsMessage, entry models.RaidLogEntry, events []Event, seed uint32, withScore bool) []string {
+	c := commentator{match: match, seed: seed}
+	raider := c.playerName(entry.RaiderId)
+	raiding := c.teamName(entry.RaidingTeam)
+	defending := c.teamName(opponent(entry.RaidingTeam))
+
+	var lines []string
+	switch entry.Result {
+	case "raidSuccess":
+		touches := len(entry.DefenderIds)
+		if touches == 0 {
+			// Only the bonus
+			lines = append(lines, c.withDoOrDie(entry, raiding, c.pick(emptyBonusLines, 1, raider)))
+			break
+		}
+		line := c.pick(raidSuccessLines, 1, raider, pointsPhrase(touches), bonusPhrase(entry.BonusTaken), c.playerNames(entry.DefenderIds))
+		lines = append(lines, c.withDoOrDie(entry, raiding, line))
+		if entry.SuperRaid {
+			lines = append(lines, c.pick(superRaidLines, 2, raider, touches))
+		}
+	case "defenseSuccess":
+		var line string
+		if len(entry.DefenderIds) > 0 {
+			line = c.pick(defenseSuccessLines, 1, raider, c.playerNames(entry.DefenderIds))
+		} else {
+			line = c.pick(tackleOnlyLines, 1, raider)
+		}
+		lines = append(lines, c.withDoOrDie(entry, raiding, line))
+		if entry.SuperTackle {
+			lines = append(lines, c.pick(superTackleLines, 2, defending))
+		}
+		if entry.BonusTaken {
+			lines = append(lines, c.pick(bonusBeforeTackleLines, 3, raider, raiding))
+		}
+	case "emptyRaid":
+		if entry.BonusTaken {
+			lines = append(lines, c.pick(emptyBonusLines, 1, raider))
+		} else {
+			lines = append(lines, c.pick(emptyRaidLines, 1, raider))
+		}
+	case "doOrDieRaid":
+		if entry.BonusTaken {
+			lines = append(lines, c.withDoOrDie(entry, raiding, c.pick(doOrDieSafeLines, 1, raider)))
+		} else {
+			lines = append(lines, c.withDoOrDie(entry, raiding, c.pick(doOrDieOutLines, 1, raider, defending)))
+		}
+	case "lobbyTouch":
+		if len(entry.LobbyEvents) == 0 {
+			return nil
+		}
+		touch := entry.LobbyEvents[0]
+		scoringTeam := c.teamName(touch.ScoringTeam)
+		if touch.IsRaider {
+			lines = append(lines, c.pick(lobbyRaiderLines, 1, c.playerName(touch.TouchedPlayerId), scoringTeam))
+		} else {
+			lines = append(lines, c.pick(lobbyDefenderLines, 1, c.playerName(touch.TouchedPlayerId), scoringTeam))
+		}
+	default:
+		return nil
+	}
+
+	for _, e := range events {
+		if e.Type == EventAllOut {
+			lines = append(lines, c.pick(allOutLines, 4, c.teamName(e.Team), c.teamName(opponent(e.Team)), e.Points))
+		}
+	}
+	if withScore {
+		d := match.Data
+		lines = append(lines, fmt.Sprintf("%s %d - %d %s", c.teamName("A"), d.TeamA.Score, d.TeamB.Score, c.teamName("B")))
+	}
+	return lines
+}
+
+type commentator struct {
+	match *models.EnhancedStatsMessage
+	seed  uint32
+}
+
+// pick formats one of the variants for a template slot. Each slot mixes the seed
+// differently so the lines of one raid do not all take the same variant.
+// Templates use indexed verbs, so a variant may leave arguments out.
+func (c commentator) pick(variants []string, slot uint32, args ...interface{}) string {
+	i := (c.seed ^ (slot * 2654435761)) % uint32(len(variants))
+	return fmt.Sprintf(variants[i], args...)
+}
+
+// withDoOrDie puts the do-or-die opener in front of a raid line
+func (c commentator) withDoOrDie(entry models.RaidLogEntry, team, line string) string {
+	if !entry.DoOrDie {
+		return line
+	}
+	return c.pick(doOrDieOpeners, 0, team) + " " + line
+}
+
+func (c commentator) playerName(id string) string {
+	if p, ok := c.match.Data.PlayerStats[id]; ok && p.Name != "" {
+		return p.Name
+	}
+	if id == "" {
+		return "The raider"
+	}
+	return id
+}
+
+func (c commentator) playerNames(ids []string) string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, c.playerName(id))
+	}
+	switch len(names) {
+	case 0:
+		return "nobody"
+	case 1:
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func (c commentator) teamName(team string) string {
+	if team == "A" && c.match.Data.TeamA.Name != "" {
+		return c.match.Data.TeamA.Name
+	}
+	if team == "B" && c.match.Data.TeamB.Name != "" {
+		return c.match.Data.TeamB.Name
+	}
+	return "Team " + team
+}
+
+func pointsPhrase(points int) string {
+	if points == 1 {
+		return "a point"
+	}
+	return fmt.Sprintf("%d points", points)
+}
+
+func bonusPhrase(bonus bool) string {
+	if bonus {
+		return " with a bonus"
+	}
+	return ""
+}
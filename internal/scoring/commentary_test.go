@@ -0,0 +1,103 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func TestCommentary(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(m *models.EnhancedStatsMessage)
+		cmd      Command
+		wantAny  []string // every line set must mention these
+		wantLine int      // number of lines, score line included
+	}{
+		{
+			name:     "successful raid with a bonus",
+			cmd:      raid("successful", "a1", true, "b1", "b2"),
+			wantAny:  []string{"a1", "with a bonus"},
+			wantLine: 2,
+		},
+		{
+			name:     "tackle",
+			cmd:      raid("defense", "a1", false, "b1"),
+			wantAny:  []string{"a1", "b1"},
+			wantLine: 2,
+		},
+		{
+			name:     "empty raid",
+			cmd:      raid("empty", "a1", false),
+			wantAny:  []string{"a1"},
+			wantLine: 2,
+		},
+		{
+			name:     "do-or-die raid lost",
+			setup:    func(m *models.EnhancedStatsMessage) { m.Data.EmptyRaidCounts.TeamA = 2 },
+			cmd:      raid("empty", "a1", false),
+			wantAny:  []string{"Do-or-die", "a1", "Team B"},
+			wantLine: 2,
+		},
+		{
+			name: "all out",
+			setup: func(m *models.EnhancedStatsMessage) {
+				setStatus(m, "out", "b2", "b3", "b4", "b5", "b6", "b7")
+			},
+			cmd:      raid("successful", "a1", false, "b1"),
+			wantAny:  []string{"a1"},
+			wantLine: 3,
+		},
+		{
+			name: "defender lobby touch",
+			cmd: Command{
+				Type:  CommandLobbyTouch,
+				Lobby: LobbyTouchPayload{TouchedPlayerId: "b3", ScoringTeam: "A", RaiderId: "a1", RaidNumber: 1},
+			},
+			wantAny:  []string{"b3", "lobby", "Team A"},
+			wantLine: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := uint32(0); seed < 12; seed++ {
+				state := newTestMatch()
+				if tt.setup != nil {
+					tt.setup(&state)
+				}
+				next, events, err := Apply(state, tt.cmd)
+				if err != nil {
+					t.Fatalf("Apply: %v", err)
+				}
+				entry := next.Data.RaidLog[len(next.Data.RaidLog)-1]
+				lines := Commentary(&next, entry, events, seed, true)
+				if len(lines) != tt.wantLine {
+					t.Fatalf("seed %d: got %d lines %q, want %d", seed, len(lines), lines, tt.wantLine)
+				}
+				text := strings.Join(lines, " ")
+				if strings.Contains(text, "%!") {
+					t.Fatalf("seed %d: bad template output %q", seed, text)
+				}
+				for _, want := range tt.wantAny {
+					if !strings.Contains(strings.ToLower(text), strings.ToLower(want)) {
+						t.Errorf("seed %d: %q does not mention %q", seed, text, want)
+					}
+				}
+				if again := Commentary(&next, entry, events, seed, true); strings.Join(again, " ") != text {
+					t.Errorf("seed %d: commentary is not stable: %q then %q", seed, text, again)
+				}
+			}
+		})
+	}
+}
+
+func TestCommentarySkipsNonRaids(t *testing.T) {
+	state := newTestMatch()
+	for _, result := range []string{"substitution", "card", "technicalPoint", "tieBreak"} {
+		if lines := Commentary(&state, models.RaidLogEntry{Result: result}, nil, 1, true); lines != nil {
+			t.Errorf("%s: got %q, want no commentary", result, lines)
+		}
+	}
+}
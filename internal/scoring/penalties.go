@@ -0,0 +1,206 @@
+package scoring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// Cards a referee can show
+const (
+	CardGreen  = "green"  // warning only
+	CardYellow = "yellow" // temporary suspension
+	CardRed    = "red"    // sent off for the rest of the match
+)
+
+// TechnicalPointPayload awards points to a team outside of a raid
+type TechnicalPointPayload struct {
+	Team   string `json:"team"`             // "A" or "B"
+	Points int    `json:"points,omitempty"` // defaults to 1
+	Reason string `json:"reason,omitempty"`
+}
+
+// CardPayload shows a card to a player. Raids or Minutes override the rule set's
+// yellow card suspension; they are ignored for green and red cards.
+type CardPayload struct {
+	PlayerID string `json:"playerId"`
+	Card     string `json:"card"`
+	Raids    int    `json:"raids,omitempty"`
+	Minutes  int    `json:"minutes,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// checkMatchNotOver rejects referee decisions once regulation time has ended,
+// unless a tie-break is being played. Unlike raids they are allowed at half time.
+func checkMatchNotOver(match *models.EnhancedStatsMessage) error {
+	if match.Data.TieBreak.Phase == models.TieBreakDecided {
+		return fmt.Errorf("the tie-break has been decided")
+	}
+	if match.Data.Clock.Status == models.ClockStatusFullTime && !tieBreakActive(match) {
+		return fmt.Errorf("regulation time has ended")
+	}
+	return nil
+}
+
+// ValidateTechnicalPoint checks a technical point award and fills in the default of one point
+func ValidateTechnicalPoint(tp *TechnicalPointPayload, match *models.EnhancedStatsMessage) error {
+	if err := checkMatchNotOver(match); err != nil {
+		return err
+	}
+	if tp.Team != "A" && tp.Team != "B" {
+		return fmt.Errorf("technical point team must be A or B")
+	}
+	if tp.Points == 0 {
+		tp.Points = 1
+	}
+	if tp.Points < 0 {
+		return fmt.Errorf("technical points cannot be negative")
+	}
+	return nil
+}
+
+// applyTechnicalPoint adds the points to the team score and logs them. The raid number does not move.
+// During a tie-break the points go to the tie-break score; they are not a raid, so
+// they never end the shootout or the golden raid by themselves.
+func applyTechnicalPoint(match *models.EnhancedStatsMessage, tp TechnicalPointPayload) []Event {
+	award := func() []Event {
+		teamStat(match, tp.Team).Score += tp.Points
+		return nil
+	}
+	if tieBreakActive(match) {
+		scoreTieBreak(match, "", award)
+	} else {
+		award()
+	}
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
+		RaidNumber:  match.Data.RaidNumber,
+		RaidingTeam: tp.Team,
+		Result:      "technicalPoint",
+		Points:      tp.Points,
+		Reason:      tp.Reason,
+	})
+	return []Event{{Type: EventTechnicalPoint, RaidNumber: match.Data.RaidNumber, Team: tp.Team, Points: tp.Points}}
+}
+
+// ValidateCard checks a card against the player's status and works out when a yellow
+// card suspension ends. It returns the logged form of the card, which Rebuild can
+// replay without knowing when the card was shown.
+func ValidateCard(card CardPayload, match *models.EnhancedStatsMessage, at time.Time) (models.RaidLogEntry, error) {
+	entry := models.RaidLogEntry{RaidNumber: match.Data.RaidNumber, Result: "card", Card: card.Card, PlayerId: card.PlayerID, Reason: card.Reason}
+	if err := checkMatchNotOver(match); err != nil {
+		return entry, err
+	}
+	if card.Card != CardGreen && card.Card != CardYellow && card.Card != CardRed {
+		return entry, fmt.Errorf("card must be green, yellow or red")
+	}
+	entry.RaidingTeam = TeamOf(match, card.PlayerID)
+	if entry.RaidingTeam == "" {
+		return entry, fmt.Errorf("player not found: %s", card.PlayerID)
+	}
+
+	p, _ := ensurePlayerStat(match, card.PlayerID)
+	if p.Status == "sentOff" {
+		return entry, fmt.Errorf("player %s has already been sent off", card.PlayerID)
+	}
+	if card.Card != CardYellow {
+		return entry, nil
+	}
+	// Only players on the mat can be suspended: an out player would come back
+	// "in" when the suspension ends, skipping the revival queue
+	if p.Status != "in" {
+		return entry, fmt.Errorf("player %s is not on the mat", card.PlayerID)
+	}
+	if card.Raids < 0 || card.Minutes < 0 {
+		return entry, fmt.Errorf("suspension cannot be negative")
+	}
+
+	rules := rulesOf(match)
+	clockUsed := match.Data.Clock.Half > 0
+	raids, minutes := card.Raids, card.Minutes
+	if raids == 0 && minutes == 0 {
+		if clockUsed && rules.YellowCardMinutes > 0 {
+			minutes = rules.YellowCardMinutes
+		} else {
+			raids = rules.YellowCardRaids
+		}
+	}
+	switch {
+	case raids > 0:
+		entry.SuspendUntilRaid = match.Data.RaidNumber + raids
+	case minutes > 0:
+		if !clockUsed {
+			return entry, fmt.Errorf("a suspension in minutes needs the match clock; start it or give raids")
+		}
+		entry.SuspendUntilSec = matchSecondsPlayed(match.Data.Clock, at) + minutes*60
+	default:
+		return entry, fmt.Errorf("no yellow card suspension configured; give raids or minutes")
+	}
+	return entry, nil
+}
+
+// applyCard records a validated card on the player and in the raid log
+func applyCard(match *models.EnhancedStatsMessage, entry models.RaidLogEntry) []Event {
+	p, _ := ensurePlayerStat(match, entry.PlayerId)
+	switch entry.Card {
+	case CardGreen:
+		p.GreenCards++
+	case CardYellow:
+		p.YellowCards++
+		p.Status = "suspended"
+		p.SuspendedUntilRaid = entry.SuspendUntilRaid
+		p.SuspendedUntilSec = entry.SuspendUntilSec
+	case CardRed:
+		p.RedCards++
+		p.SentOffFromBench = p.Status == "bench"
+		p.Status = "sentOff"
+		p.SuspendedUntilRaid = 0
+		p.SuspendedUntilSec = 0
+	}
+	match.Data.PlayerStats[entry.PlayerId] = p
+	match.Data.RaidLog = append(match.Data.RaidLog, entry)
+
+	events := []Event{{Type: EventCard, RaidNumber: entry.RaidNumber, Team: entry.RaidingTeam, PlayerIDs: []string{entry.PlayerId}}}
+	if entry.Card != CardGreen {
+		// Carding the last player on the mat is an all out like any other
+		events = append(events, checkAndHandleAllOut(match)...)
+	}
+	return events
+}
+
+// releaseSuspensions brings back yellow-carded players whose suspension is over.
+// playedSec is the match time played; pass a negative value to only check raids.
+func releaseSuspensions(match *models.EnhancedStatsMessage, playedSec int) []Event {
+	var events []Event
+	for _, team := range []string{"A", "B"} {
+		var returned []string
+		for _, pid := range teamPlayerIDs(match, team) {
+			p, ok := match.Data.PlayerStats[pid]
+			if !ok || p.Status != "suspended" {
+				continue
+			}
+			byRaids := p.SuspendedUntilRaid > 0 && match.Data.RaidNumber >= p.SuspendedUntilRaid
+			byTime := p.SuspendedUntilSec > 0 && playedSec >= 0 && playedSec >= p.SuspendedUntilSec
+			if !byRaids && !byTime {
+				continue
+			}
+			p.Status = "in"
+			p.SuspendedUntilRaid = 0
+			p.SuspendedUntilSec = 0
+			match.Data.PlayerStats[pid] = p
+			returned = append(returned, pid)
+		}
+		if len(returned) > 0 {
+			events = append(events, Event{Type: EventPlayersReturned, RaidNumber: match.Data.RaidNumber, Team: team, PlayerIDs: returned})
+		}
+	}
+	return events
+}
+
+// matchSecondsPlayed returns the match time played across both halves as of at
+func matchSecondsPlayed(clock models.MatchClock, at time.Time) int {
+	if clock.Half == 0 {
+		return 0
+	}
+	return (clock.Half-1)*clock.HalfDurationSec + ClockElapsed(clock, at)
+}
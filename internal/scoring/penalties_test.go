@@ -0,0 +1,211 @@
+package scoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func cardCmd(player, card string, raids, minutes int, at time.Time) Command {
+	return Command{Type: CommandCard, Card: CardPayload{PlayerID: player, Card: card, Raids: raids, Minutes: minutes}, At: at}
+}
+
+func TestTechnicalPoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   TechnicalPointPayload
+		fullTime  bool
+		wantErr   bool
+		wantScore int
+	}{
+		{name: "defaults to one point", payload: TechnicalPointPayload{Team: "B"}, wantScore: 1},
+		{name: "several points", payload: TechnicalPointPayload{Team: "B", Points: 2, Reason: "delay"}, wantScore: 2},
+		{name: "team is required", payload: TechnicalPointPayload{}, wantErr: true},
+		{name: "not after full time", payload: TechnicalPointPayload{Team: "B"}, fullTime: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestMatch()
+			if tt.fullTime {
+				state.Data.Clock.Status = models.ClockStatusFullTime
+			}
+			next, _, err := Apply(state, Command{Type: CommandTechnicalPoint, Tech: tt.payload, At: testTime})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next.Data.TeamB.Score != tt.wantScore || next.Data.RaidNumber != 1 {
+				t.Errorf("team B score = %d, raid number = %d", next.Data.TeamB.Score, next.Data.RaidNumber)
+			}
+			if last := next.Data.RaidLog[len(next.Data.RaidLog)-1]; last.Result != "technicalPoint" || last.Points != tt.wantScore {
+				t.Errorf("raid log entry = %+v", last)
+			}
+		})
+	}
+}
+
+func TestCards(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(m *models.EnhancedStatsMessage)
+		cmd        Command
+		wantErr    bool
+		wantStatus string
+	}{
+		{name: "green card is a warning", cmd: cardCmd("b1", CardGreen, 0, 0, testTime), wantStatus: "in"},
+		{name: "yellow card suspends", cmd: cardCmd("b1", CardYellow, 0, 0, testTime), wantStatus: "suspended"},
+		{name: "no yellow card for an out player", setup: func(m *models.EnhancedStatsMessage) { setStatus(m, "out", "b1") }, cmd: cardCmd("b1", CardYellow, 2, 0, testTime), wantErr: true},
+		{name: "red card sends off", cmd: cardCmd("b1", CardRed, 0, 0, testTime), wantStatus: "sentOff"},
+		{name: "unknown card", cmd: cardCmd("b1", "blue", 0, 0, testTime), wantErr: true},
+		{name: "unknown player", cmd: cardCmd("zz", CardRed, 0, 0, testTime), wantErr: true},
+		{name: "sent off players cannot be carded again", setup: func(m *models.EnhancedStatsMessage) { setStatus(m, "sentOff", "b1") }, cmd: cardCmd("b1", CardYellow, 0, 0, testTime), wantErr: true},
+		{name: "minutes need the clock", cmd: cardCmd("b1", CardYellow, 0, 2, testTime), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestMatch()
+			if tt.setup != nil {
+				tt.setup(&state)
+			}
+			next, _, err := Apply(state, tt.cmd)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := next.Data.PlayerStats["b1"].Status; got != tt.wantStatus {
+				t.Errorf("b1 status = %q, want %q", got, tt.wantStatus)
+			}
+			if last := next.Data.RaidLog[len(next.Data.RaidLog)-1]; last.Result != "card" || last.Card != tt.cmd.Card.Card || last.RaidingTeam != "B" {
+				t.Errorf("raid log entry = %+v", last)
+			}
+		})
+	}
+}
+
+func TestYellowCardSuspensionInRaids(t *testing.T) {
+	state := newTestMatch()
+	apply := func(cmd Command) []Event {
+		t.Helper()
+		next, events, err := Apply(state, cmd)
+		if err != nil {
+			t.Fatalf("%s: %v", cmd.Type, err)
+		}
+		state = next
+		return events
+	}
+
+	apply(cardCmd("b1", CardYellow, 2, 0, testTime)) // suspended for raids 1 and 2
+	if _, _, err := Apply(state, raid("defense", "a1", false, "b1")); err == nil {
+		t.Fatalf("suspended player should not be able to tackle")
+	}
+	apply(raid("empty", "a1", false))
+	if got := state.Data.PlayerStats["b1"].Status; got != "suspended" {
+		t.Fatalf("b1 status after one raid = %q, want suspended", got)
+	}
+	events := apply(raid("empty", "b2", false))
+	if got := state.Data.PlayerStats["b1"].Status; got != "in" {
+		t.Fatalf("b1 status after two raids = %q, want in", got)
+	}
+	if types := eventTypes(events); types[len(types)-1] != EventPlayersReturned {
+		t.Errorf("events = %v, want playersReturned last", types)
+	}
+
+	// Undoing the second raid puts the suspension back
+	apply(Command{Type: CommandUndo, At: testTime})
+	if got := state.Data.PlayerStats["b1"].Status; got != "suspended" {
+		t.Errorf("b1 status after undo = %q, want suspended", got)
+	}
+}
+
+func TestYellowCardSuspensionInMinutes(t *testing.T) {
+	state := newTestMatch()
+	var err error
+	if state, _, err = Apply(state, clockCmd(ClockStart, "", testTime)); err != nil {
+		t.Fatalf("start clock: %v", err)
+	}
+	// Rule set default: two minutes of match time while the clock is in use
+	if state, _, err = Apply(state, cardCmd("b1", CardYellow, 0, 0, testTime.Add(time.Minute))); err != nil {
+		t.Fatalf("yellow card: %v", err)
+	}
+	if got := state.Data.PlayerStats["b1"].SuspendedUntilSec; got != 180 {
+		t.Fatalf("suspended until %d s, want 180", got)
+	}
+
+	// Time spent paused does not count towards the suspension
+	for _, cmd := range []Command{
+		clockCmd(ClockPause, "", testTime.Add(2*time.Minute)),
+		clockCmd(ClockResume, "", testTime.Add(10*time.Minute)),
+	} {
+		if state, _, err = Apply(state, cmd); err != nil {
+			t.Fatalf("%s: %v", cmd.Clock.Action, err)
+		}
+	}
+	if got := state.Data.PlayerStats["b1"].Status; got != "suspended" {
+		t.Fatalf("b1 status after pause = %q, want suspended", got)
+	}
+	if state, _, err = Apply(state, clockCmd(ClockPause, "", testTime.Add(11*time.Minute))); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if got := state.Data.PlayerStats["b1"].Status; got != "in" {
+		t.Errorf("b1 status after three minutes played = %q, want in", got)
+	}
+}
+
+func TestAllOutSkipsCardedPlayers(t *testing.T) {
+	state := newTestMatch()
+	setStatus(&state, "out", "b2", "b3", "b4", "b5", "b6")
+	setStatus(&state, "sentOff", "b7")
+
+	next, events, err := Apply(state, raid("successful", "a1", false, "b1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := eventTypes(events); types[len(types)-1] != EventAllOut {
+		t.Fatalf("events = %v, want allOut last", types)
+	}
+	if got := next.Data.PlayerStats["b7"].Status; got != "sentOff" {
+		t.Errorf("b7 status = %q, want sentOff", got)
+	}
+	if got := activePlayers(&next, "B"); got != 6 {
+		t.Errorf("team B on the mat = %d, want 6", got)
+	}
+}
+
+func TestBenchRedCardKeepsRevivalRoom(t *testing.T) {
+	state := newTestMatch()
+	addBench(&state, 1)
+	setStatus(&state, "bench", "b8")
+	setStatus(&state, "out", "b1", "b2")
+
+	var err error
+	if state, _, err = Apply(state, cardCmd("b8", CardRed, 0, 0, testTime)); err != nil {
+		t.Fatalf("red card: %v", err)
+	}
+	if !state.Data.PlayerStats["b8"].SentOffFromBench {
+		t.Fatalf("b8 = %+v, want sent off from the bench", state.Data.PlayerStats["b8"])
+	}
+	if state, _, err = Apply(state, raid("empty", "a1", false)); err != nil {
+		t.Fatalf("empty raid: %v", err)
+	}
+	// Two points for team B bring back both out players; the substitute's card
+	// does not cost the team a place on the mat
+	if state, _, err = Apply(state, raid("successful", "b3", false, "a1", "a2")); err != nil {
+		t.Fatalf("raid: %v", err)
+	}
+	if got := activePlayers(&state, "B"); got != 7 {
+		t.Errorf("team B on the mat = %d, want 7", got)
+	}
+}
@@ -0,0 +1,575 @@
+package scoring
+
+import (
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func processSuccessfulRaid(match *models.EnhancedStatsMessage, raid RaidPayload) []Event {
+	// Backend determines raiding team from raider's team membership
+	raidingTeam := TeamOf(match, raid.RaiderID)
+	defendingTeam := opponent(raidingTeam)
+	raidNumber := match.Data.RaidNumber
+	lobbyEvents := consumeLobbyEvents(match)
+	lobbyRaiderEntered, lobbyDefenders := splitLobbyEvents(match, lobbyEvents)
+
+	// Check if this raid is a do-or-die (raiding team has used up its empty raids)
+	doOrDie := isDoOrDie(match, raidingTeam)
+
+	raidPoints := len(raid.DefenderIDs)
+	pointsGained := raidPoints + boolToInt(raid.BonusTaken) // total points includes bonus
+	superRaid := raidPoints >= rulesOf(match).SuperRaidPoints
+
+	// update team score
+	teamStat(match, raidingTeam).Score += pointsGained
+
+	// update raider stats
+	raiderStat := match.Data.PlayerStats[raid.RaiderID]
+	raiderStat.TotalRaids++
+	raiderStat.SuccessfulRaids++
+	raiderStat.RaidPoints += pointsGained
+	raiderStat.TotalPoints += pointsGained
+	if superRaid {
+		raiderStat.SuperRaids++
+	}
+	match.Data.PlayerStats[raid.RaiderID] = raiderStat
+
+	// Reset empty raid count for the raiding team on a successful raid
+	*emptyRaidCount(match, raidingTeam) = 0
+
+	// mark defenders out and keep their stats
+	for _, defID := range raid.DefenderIDs {
+		d := match.Data.PlayerStats[defID]
+		d.TotalTackles++
+		d.Status = "out"
+		match.Data.PlayerStats[defID] = d
+		markOut(match, defendingTeam, defID)
+	}
+
+	events := []Event{{
+		Type:       EventRaidSuccess,
+		RaidNumber: raidNumber,
+		Team:       raidingTeam,
+		PlayerIDs:  []string{raid.RaiderID},
+		Points:     pointsGained,
+	}}
+	if superRaid {
+		events = append(events, Event{Type: EventSuperRaid, RaidNumber: raidNumber, Team: raidingTeam, PlayerIDs: []string{raid.RaiderID}})
+	}
+	events = append(events, Event{Type: EventPlayersOut, RaidNumber: raidNumber, Team: defendingTeam, PlayerIDs: cloneStrings(raid.DefenderIDs)})
+
+	// revival: for each point gained by raiding team, revive one out player from raiding team (if any)
+	// Only revive players based on defenders out, not bonus points
+	if revived := revivePlayers(match, raidingTeam, raidPoints); len(revived) > 0 {
+		events = append(events, Event{Type: EventPlayersRevived, RaidNumber: raidNumber, Team: raidingTeam, PlayerIDs: revived})
+	}
+
+	// record last raid and check for all out
+	match.Data.RaidDetails = models.RaidDetails{
+		Type:           "raidSuccess",
+		Raider:         raiderStat.Name,
+		Defenders:      getDefenderNames(match, raid.DefenderIDs),
+		PointsGained:   pointsGained,
+		BonusTaken:     raid.BonusTaken,
+		SuperRaid:      superRaid,
+		DoOrDie:        doOrDie,
+		LobbyRaider:    lobbyRaiderEntered,
+		LobbyDefenders: lobbyDefenders,
+	}
+
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
+		RaidNumber:  raidNumber,
+		RaidingTeam: raidingTeam,
+		RaiderId:    raid.RaiderID,
+		DefenderIds: raid.DefenderIDs,
+		Result:      "raidSuccess",
+		Points:      pointsGained,
+		BonusTaken:  raid.BonusTaken,
+		SuperRaid:   superRaid,
+		DoOrDie:     doOrDie,
+		LobbyEvents: lobbyEvents,
+	})
+
+	// Check for all out before incrementing raid number
+	events = append(events, checkAndHandleAllOut(match)...)
+
+	match.Data.RaidNumber++
+	return events
+}
+
+func processDefenseSuccess(match *models.EnhancedStatsMessage, raid RaidPayload) []Event {
+	// Backend determines raiding team from raider's team membership
+	raidingTeam := TeamOf(match, raid.RaiderID)
+	defendingTeam := opponent(raidingTeam)
+	raidNumber := match.Data.RaidNumber
+	lobbyEvents := consumeLobbyEvents(match)
+	lobbyRaiderEntered, lobbyDefenders := splitLobbyEvents(match, lobbyEvents)
+
+	// Check if this raid is a do-or-die (raiding team has used up its empty raids)
+	doOrDie := isDoOrDie(match, raidingTeam)
+
+	// Base defense/tackle points
+	points := 1
+	// Super tackle occurs when the defending team is down to the rule set's threshold on the mat.
+	maxDefenders := rulesOf(match).SuperTackleMaxDefenders
+	superTackleApplied := maxDefenders > 0 && activePlayers(match, defendingTeam) <= maxDefenders
+	if superTackleApplied {
+		points = 2
+	}
+
+	// If raider had taken a bonus, the raiding team ALWAYS gets the bonus point.
+	// Update raider stats accordingly. When a raider with bonus is tackled, defenders
+	// should only be awarded the normal tackle point (1), not the super tackle bonus.
+	if raid.BonusTaken {
+		teamStat(match, raidingTeam).Score++
+		rr := match.Data.PlayerStats[raid.RaiderID]
+		rr.RaidPoints++
+		rr.TotalPoints++
+		match.Data.PlayerStats[raid.RaiderID] = rr
+		if superTackleApplied {
+			points = 1
+			superTackleApplied = false
+		}
+	}
+
+	// Award points to defending team (points already adjusted above)
+	teamStat(match, defendingTeam).Score += points
+
+	// Mark raider out
+	r := match.Data.PlayerStats[raid.RaiderID]
+	r.TotalRaids++
+	r.Status = "out"
+	match.Data.PlayerStats[raid.RaiderID] = r
+	markOut(match, raidingTeam, raid.RaiderID)
+
+	// update defender stats
+	for _, defID := range raid.DefenderIDs {
+		d := match.Data.PlayerStats[defID]
+		d.TotalTackles++
+		d.SuccessfulTackles++
+		d.DefencePoints++
+		d.TotalPoints++
+		if superTackleApplied {
+			d.SuperTackles++
+		}
+		match.Data.PlayerStats[defID] = d
+	}
+
+	events := []Event{{
+		Type:       EventDefenseSuccess,
+		RaidNumber: raidNumber,
+		Team:       defendingTeam,
+		PlayerIDs:  cloneStrings(raid.DefenderIDs),
+		Points:     points,
+	}}
+	if superTackleApplied {
+		events = append(events, Event{Type: EventSuperTackle, RaidNumber: raidNumber, Team: defendingTeam, PlayerIDs: cloneStrings(raid.DefenderIDs)})
+	}
+	events = append(events, Event{Type: EventPlayersOut, RaidNumber: raidNumber, Team: raidingTeam, PlayerIDs: []string{raid.RaiderID}})
+
+	// PointsGained should reflect total points awarded this raid: defender points + bonus (if any)
+	match.Data.RaidDetails = models.RaidDetails{
+		Type:           "defenseSuccess",
+		Raider:         r.Name,
+		Defenders:      getDefenderNames(match, raid.DefenderIDs),
+		PointsGained:   points + boolToInt(raid.BonusTaken),
+		SuperTackle:    superTackleApplied,
+		BonusTaken:     raid.BonusTaken,
+		DoOrDie:        doOrDie,
+		LobbyRaider:    lobbyRaiderEntered,
+		LobbyDefenders: lobbyDefenders,
+	}
+
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
+		RaidNumber:  raidNumber,
+		RaidingTeam: raidingTeam,
+		RaiderId:    raid.RaiderID,
+		DefenderIds: raid.DefenderIDs,
+		Result:      "defenseSuccess",
+		Points:      points + boolToInt(raid.BonusTaken),
+		BonusTaken:  raid.BonusTaken,
+		SuperTackle: superTackleApplied,
+		DoOrDie:     doOrDie,
+		LobbyEvents: lobbyEvents,
+	})
+
+	// Check for all out before revival (will add all-out points to RaidDetails if any)
+	events = append(events, checkAndHandleAllOut(match)...)
+
+	// Reset empty raid count for the raiding team on a defense success
+	*emptyRaidCount(match, raidingTeam) = 0
+
+	// Revive exactly 1 player for the defending team (super tackle revives 1 as per rules)
+	if revived := revivePlayers(match, defendingTeam, 1); len(revived) > 0 {
+		events = append(events, Event{Type: EventPlayersRevived, RaidNumber: raidNumber, Team: defendingTeam, PlayerIDs: revived})
+	}
+
+	match.Data.RaidNumber++
+	return events
+}
+
+func processEmptyRaid(match *models.EnhancedStatsMessage, raid RaidPayload) []Event {
+	// Backend determines raiding team from raider's team membership
+	raidingTeam := TeamOf(match, raid.RaiderID)
+	defendingTeam := opponent(raidingTeam)
+	raidNumber := match.Data.RaidNumber
+	lobbyEvents := consumeLobbyEvents(match)
+	lobbyRaiderEntered, lobbyDefenders := splitLobbyEvents(match, lobbyEvents)
+
+	r := match.Data.PlayerStats[raid.RaiderID]
+	r.TotalRaids++
+
+	// Award bonus point to raiding team if taken (no revival for bonus points)
+	if raid.BonusTaken {
+		teamStat(match, raidingTeam).Score++
+		r.RaidPoints++
+		r.TotalPoints++
+	}
+
+	// Backend tracks empty raid counts (increment for current raiding team)
+	doOrDie := isDoOrDie(match, raidingTeam)
+	count := emptyRaidCount(match, raidingTeam)
+	*count++
+
+	events := []Event{}
+	if doOrDie {
+		// Do-or-die: the raid after the allowed run of consecutive empty raids.
+		// Kabaddi rule: if the raider takes a bonus on the do-or-die raid, the raider is safe
+		// (bonus already awarded above). Otherwise, raider is out, opponent gets 1 point
+		// and opponent revives 1 player.
+		if !raid.BonusTaken {
+			r.Status = "out"
+			markOut(match, raidingTeam, raid.RaiderID)
+			teamStat(match, defendingTeam).Score++
+		}
+		events = append(events, Event{Type: EventDoOrDieRaid, RaidNumber: raidNumber, Team: raidingTeam, PlayerIDs: []string{raid.RaiderID}, Points: boolToInt(raid.BonusTaken)})
+		if !raid.BonusTaken {
+			events = append(events, Event{Type: EventPlayersOut, RaidNumber: raidNumber, Team: raidingTeam, PlayerIDs: []string{raid.RaiderID}})
+		}
+	} else {
+		events = append(events, Event{Type: EventEmptyRaid, RaidNumber: raidNumber, Team: raidingTeam, PlayerIDs: []string{raid.RaiderID}, Points: boolToInt(raid.BonusTaken)})
+	}
+	match.Data.PlayerStats[raid.RaiderID] = r
+
+	result := ternaryString(doOrDie, "doOrDieRaid", "emptyRaid")
+	match.Data.RaidDetails = models.RaidDetails{
+		Type:           result,
+		Raider:         r.Name,
+		BonusTaken:     raid.BonusTaken,
+		DoOrDie:        doOrDie,
+		LobbyRaider:    lobbyRaiderEntered,
+		LobbyDefenders: lobbyDefenders,
+	}
+
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
+		RaidNumber:  raidNumber,
+		RaidingTeam: raidingTeam,
+		RaiderId:    raid.RaiderID,
+		Result:      result,
+		Points:      boolToInt(raid.BonusTaken),
+		BonusTaken:  raid.BonusTaken,
+		DoOrDie:     doOrDie,
+		LobbyEvents: lobbyEvents,
+	})
+
+	if doOrDie {
+		// If do-or-die resulted in raider out (i.e., no bonus taken), defending team revives 1 player
+		if !raid.BonusTaken {
+			if revived := revivePlayers(match, defendingTeam, 1); len(revived) > 0 {
+				events = append(events, Event{Type: EventPlayersRevived, RaidNumber: raidNumber, Team: defendingTeam, PlayerIDs: revived})
+			}
+		}
+		// A do-or-die raid always resets that team's empty count, whatever its outcome
+		*count = 0
+	}
+
+	match.Data.RaidNumber++
+	return events
+}
+
+// processLobbyTouch awards a point to the scoring team, marks the touched player out
+// and records the touch in the raid log so it can be replayed on undo/redo.
+func processLobbyTouch(match *models.EnhancedStatsMessage, lobby LobbyTouchPayload) []Event {
+	raidNumber := match.Data.RaidNumber
+	scoringTeam := lobby.ScoringTeam
+	if scoringTeam != "A" {
+		scoringTeam = "B"
+	}
+
+	teamStat(match, scoringTeam).Score++
+
+	raiderName := ""
+	if p, ok := match.Data.PlayerStats[lobby.TouchedPlayerId]; ok {
+		raiderName = p.Name
+		p.Status = "out"
+		match.Data.PlayerStats[lobby.TouchedPlayerId] = p
+		markOut(match, opponent(scoringTeam), lobby.TouchedPlayerId)
+	}
+
+	if !lobby.IsRaider && lobby.RaiderId != "" {
+		if r, ok := match.Data.PlayerStats[lobby.RaiderId]; ok {
+			r.RaidPoints++
+			r.TotalPoints++
+			match.Data.PlayerStats[lobby.RaiderId] = r
+		}
+	}
+
+	event := models.LobbyEvent{
+		TouchedPlayerId: lobby.TouchedPlayerId,
+		IsRaider:        lobby.IsRaider,
+		ScoringTeam:     lobby.ScoringTeam,
+		RaidNumber:      lobby.RaidNumber,
+	}
+	match.Data.PendingLobby.Events = append(match.Data.PendingLobby.Events, event)
+
+	match.Data.RaidDetails = models.RaidDetails{
+		Type:         "lobbyTouch",
+		Raider:       raiderName,
+		PointsGained: 1,
+	}
+
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
+		RaidNumber:  raidNumber,
+		RaidingTeam: ExpectedRaidingTeam(match),
+		RaiderId:    lobby.RaiderId,
+		DefenderIds: lobby.DefenderIds,
+		Result:      "lobbyTouch",
+		Points:      1,
+		LobbyEvents: []models.LobbyEvent{event},
+	})
+
+	events := []Event{
+		{Type: EventLobbyTouch, RaidNumber: raidNumber, Team: scoringTeam, PlayerIDs: []string{lobby.TouchedPlayerId}, Points: 1},
+		{Type: EventPlayersOut, RaidNumber: raidNumber, Team: opponent(scoringTeam), PlayerIDs: []string{lobby.TouchedPlayerId}},
+	}
+	events = append(events, checkAndHandleAllOut(match)...)
+
+	// Increment raid number so next team raids (same as successful/defense/empty raid actions)
+	match.Data.RaidNumber++
+	return events
+}
+
+// checkAndHandleAllOut checks if a team is all out and handles the scoring and revival.
+// Returns the all-out event if one occurred.
+func checkAndHandleAllOut(match *models.EnhancedStatsMessage) []Event {
+	for _, team := range []string{"A", "B"} {
+		roster := teamPlayerIDs(match, team)
+		// Only if they actually have players
+		if len(roster) == 0 || activePlayers(match, team) > 0 {
+			continue
+		}
+		// Revive all out players of the all-out team; substitutes stay on the bench and
+		// carded players stay off. A team with nobody to revive is short, not all out.
+		revived := revivePlayers(match, team, len(roster))
+		if len(revived) == 0 {
+			continue
+		}
+		// Award extra points to the opponent (all-out bonus)
+		bonus := rulesOf(match).AllOutBonus
+		teamStat(match, opponent(team)).Score += bonus
+		// Update raid details
+		match.Data.RaidDetails.AllOut = true
+		match.Data.RaidDetails.AllOutTeam = team
+		match.Data.RaidDetails.PointsGained += bonus // Add all-out points to total points gained
+		return []Event{{Type: EventAllOut, RaidNumber: match.Data.RaidNumber, Team: team, PlayerIDs: revived, Points: bonus}}
+	}
+	return nil
+}
+
+// revivePlayers revives up to count players of the given team by setting their
+// status to "in" in match.Data.PlayerStats. Players come back in the order they
+// went out, never beyond the squad size, and their IDs are returned.
+// Carded players keep their place in the squad, so they count against the limit.
+func revivePlayers(match *models.EnhancedStatsMessage, team string, count int) []string {
+	if room := rulesOf(match).SquadSize - activePlayers(match, team) - cardedPlayers(match, team); room < count {
+		count = room
+	}
+	if count <= 0 {
+		return nil
+	}
+	syncOutQueue(match, team)
+	queue := outQueue(match, team)
+	if count > len(*queue) {
+		count = len(*queue)
+	}
+	var revived []string
+	for _, entry := range (*queue)[:count] {
+		p := match.Data.PlayerStats[entry.PlayerID]
+		p.Status = "in"
+		match.Data.PlayerStats[entry.PlayerID] = p
+		revived = append(revived, entry.PlayerID)
+	}
+	*queue = append((*queue)[:0:0], (*queue)[count:]...)
+	return revived
+}
+
+// markOut puts a player who has just gone out at the back of the team's out queue,
+// dropping any stale entry left from an earlier time they were out
+func markOut(match *models.EnhancedStatsMessage, team, playerID string) {
+	queue := outQueue(match, team)
+	kept := (*queue)[:0:0]
+	for _, entry := range *queue {
+		if entry.PlayerID != playerID && match.Data.PlayerStats[entry.PlayerID].Status == "out" {
+			kept = append(kept, entry)
+		}
+	}
+	*queue = append(kept, models.OutPlayer{PlayerID: playerID, OutAtRaid: match.Data.RaidNumber})
+}
+
+// syncOutQueue makes the team's out queue match the player statuses. Players who
+// left the mat some other way (cards, all outs, the tie-break) are dropped, and out
+// players missing from it, as in states saved before the queue existed, join the
+// back in roster order.
+func syncOutQueue(match *models.EnhancedStatsMessage, team string) {
+	queue := outQueue(match, team)
+	kept := make([]models.OutPlayer, 0, len(*queue))
+	queued := make(map[string]bool, len(*queue))
+	for _, entry := range *queue {
+		if match.Data.PlayerStats[entry.PlayerID].Status == "out" && !queued[entry.PlayerID] {
+			kept = append(kept, entry)
+			queued[entry.PlayerID] = true
+		}
+	}
+	for _, pid := range teamPlayerIDs(match, team) {
+		if match.Data.PlayerStats[pid].Status == "out" && !queued[pid] {
+			kept = append(kept, models.OutPlayer{PlayerID: pid})
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	*queue = kept
+}
+
+// syncOutQueues runs syncOutQueue for both teams
+func syncOutQueues(match *models.EnhancedStatsMessage) {
+	syncOutQueue(match, "A")
+	syncOutQueue(match, "B")
+}
+
+func outQueue(match *models.EnhancedStatsMessage, team string) *[]models.OutPlayer {
+	if team == "A" {
+		return &match.Data.OutQueue.TeamA
+	}
+	return &match.Data.OutQueue.TeamB
+}
+
+func consumeLobbyEvents(match *models.EnhancedStatsMessage) []models.LobbyEvent {
+	if len(match.Data.PendingLobby.Events) == 0 {
+		return nil
+	}
+	currentRaid := match.Data.RaidNumber
+	current := make([]models.LobbyEvent, 0)
+	remaining := make([]models.LobbyEvent, 0)
+	for _, ev := range match.Data.PendingLobby.Events {
+		if ev.RaidNumber == 0 || ev.RaidNumber == currentRaid {
+			current = append(current, ev)
+			continue
+		}
+		if ev.RaidNumber > currentRaid {
+			remaining = append(remaining, ev)
+		}
+	}
+	match.Data.PendingLobby.Events = remaining
+	if len(current) == 0 {
+		return nil
+	}
+	return current
+}
+
+func splitLobbyEvents(match *models.EnhancedStatsMessage, events []models.LobbyEvent) (bool, []string) {
+	if len(events) == 0 {
+		return false, nil
+	}
+	lobbyRaider := false
+	lobbyDefenders := []string{}
+	for _, ev := range events {
+		if ev.IsRaider {
+			lobbyRaider = true
+			continue
+		}
+		if p, ok := match.Data.PlayerStats[ev.TouchedPlayerId]; ok {
+			lobbyDefenders = append(lobbyDefenders, p.Name)
+		} else {
+			lobbyDefenders = append(lobbyDefenders, ev.TouchedPlayerId)
+		}
+	}
+	return lobbyRaider, lobbyDefenders
+}
+
+// rulesOf returns the rule set the match is being played under
+func rulesOf(match *models.EnhancedStatsMessage) models.RuleSet {
+	return match.Data.Rules.OrDefault()
+}
+
+// isDoOrDie reports whether the team's next raid is a do-or-die raid
+func isDoOrDie(match *models.EnhancedStatsMessage, team string) bool {
+	limit := rulesOf(match).DoOrDieEmptyRaids
+	return limit > 0 && *emptyRaidCount(match, team) >= limit
+}
+
+// activePlayers counts a team's players with status "in"
+func activePlayers(match *models.EnhancedStatsMessage, team string) int {
+	active := 0
+	for _, pid := range teamPlayerIDs(match, team) {
+		if match.Data.PlayerStats[pid].Status == "in" {
+			active++
+		}
+	}
+	return active
+}
+
+// cardedPlayers counts a team's suspended and sent off players. Substitutes
+// sent off from the bench never took a place in the squad, so they do not count.
+func cardedPlayers(match *models.EnhancedStatsMessage, team string) int {
+	carded := 0
+	for _, pid := range teamPlayerIDs(match, team) {
+		p := match.Data.PlayerStats[pid]
+		if p.Status == "suspended" || (p.Status == "sentOff" && !p.SentOffFromBench) {
+			carded++
+		}
+	}
+	return carded
+}
+
+func teamPlayerIDs(match *models.EnhancedStatsMessage, team string) []string {
+	if team == "A" {
+		return match.Data.TeamAPlayerIDs
+	}
+	return match.Data.TeamBPlayerIDs
+}
+
+func teamStat(match *models.EnhancedStatsMessage, team string) *models.TeamStat {
+	if team == "A" {
+		return &match.Data.TeamA
+	}
+	return &match.Data.TeamB
+}
+
+func emptyRaidCount(match *models.EnhancedStatsMessage, team string) *int {
+	if team == "A" {
+		return &match.Data.EmptyRaidCounts.TeamA
+	}
+	return &match.Data.EmptyRaidCounts.TeamB
+}
+
+func getDefenderNames(match *models.EnhancedStatsMessage, defenderIDs []string) []string {
+	names := make([]string, len(defenderIDs))
+	for i, id := range defenderIDs {
+		names[i] = match.Data.PlayerStats[id].Name
+	}
+	return names
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func ternaryString(cond bool, a, b string) string {
+	if cond {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,192 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// undoLastRaid removes the most recent raid log entry, pushes it onto the redo
+// log and rebuilds the match state by replaying the remaining raids.
+func undoLastRaid(match *models.EnhancedStatsMessage) ([]Event, error) {
+	n := len(match.Data.RaidLog)
+	if n == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+
+	undone := match.Data.RaidLog[n-1]
+	remaining := cloneRaidLog(match.Data.RaidLog[:n-1])
+	redoLog := append(cloneRaidLog(match.Data.RedoLog), undone)
+
+	Rebuild(match, remaining)
+	match.Data.RedoLog = redoLog
+	return []Event{{Type: EventRaidUndone, RaidNumber: undone.RaidNumber, Team: undone.RaidingTeam}}, nil
+}
+
+// redoLastRaid re-applies the most recently undone raid on top of the current state.
+func redoLastRaid(match *models.EnhancedStatsMessage) ([]Event, error) {
+	n := len(match.Data.RedoLog)
+	if n == 0 {
+		return nil, fmt.Errorf("nothing to redo")
+	}
+
+	entry := match.Data.RedoLog[n-1]
+	if err := validateLogEntry(entry, match, ValidateRaid); err != nil {
+		return nil, fmt.Errorf("cannot redo %v", err)
+	}
+
+	redoLog := cloneRaidLog(match.Data.RedoLog[:n-1])
+	events := []Event{{Type: EventRaidRedone, RaidNumber: entry.RaidNumber, Team: entry.RaidingTeam}}
+	events = append(events, applyRaidLogEntry(match, entry)...)
+	match.Data.RedoLog = redoLog
+	return events, nil
+}
+
+// validateLogEntry checks a logged entry against the match as it now stands, the
+// way the command that logged it was checked. Raids go through validateRaid since
+// a redo is held to the clock and a review correction is not.
+func validateLogEntry(entry models.RaidLogEntry, match *models.EnhancedStatsMessage, validateRaid func(RaidPayload, *models.EnhancedStatsMessage) error) error {
+	if payload, ok := raidPayloadFromLogEntry(entry); ok {
+		if err := validateRaid(payload, match); err != nil {
+			return fmt.Errorf("raid %d: %v", entry.RaidNumber, err)
+		}
+	}
+	switch entry.Result {
+	case "substitution":
+		if _, err := ValidateSubstitution(substitutionFromLogEntry(entry), match); err != nil {
+			return fmt.Errorf("substitution: %v", err)
+		}
+	case "tieBreak":
+		if err := validateTieBreakStart(match); err != nil {
+			return fmt.Errorf("tie-break: %v", err)
+		}
+	case "technicalPoint", "card":
+		if err := checkMatchNotOver(match); err != nil {
+			return fmt.Errorf("%s: %v", entry.Result, err)
+		}
+		if entry.Result == "card" && match.Data.PlayerStats[entry.PlayerId].Status == "sentOff" {
+			return fmt.Errorf("card: player %s has already been sent off", entry.PlayerId)
+		}
+	}
+	return nil
+}
+
+// Rebuild resets the match to its pre-raid baseline and replays the given raid
+// log entries through the raid processors. Rosters, captains, toss information
+// and the starting bench are kept; scores, player stats, empty raid counts,
+// substitutions, the tie-break, the out queues, pending lobby events and awards
+// are recomputed from scratch.
+func Rebuild(match *models.EnhancedStatsMessage, entries []models.RaidLogEntry) {
+	startRaid := match.Data.RaidNumber
+	if len(match.Data.RaidLog) > 0 {
+		startRaid = match.Data.RaidLog[0].RaidNumber
+	} else if len(entries) > 0 {
+		startRaid = entries[0].RaidNumber
+	}
+	if startRaid <= 0 {
+		startRaid = 1
+	}
+
+	match.Data.TeamA.Score = 0
+	match.Data.TeamB.Score = 0
+	bench := make(map[string]bool, len(match.Data.StartingBench))
+	for _, id := range match.Data.StartingBench {
+		bench[id] = true
+	}
+	for id, p := range match.Data.PlayerStats {
+		status := "in"
+		if bench[id] {
+			status = "bench"
+		}
+		match.Data.PlayerStats[id] = models.PlayerStat{
+			Name:          p.Name,
+			ID:            p.ID,
+			IsCaptain:     p.IsCaptain,
+			IsViceCaptain: p.IsViceCaptain,
+			Status:        status,
+		}
+	}
+	match.Data.RaidDetails = models.RaidDetails{}
+	match.Data.RaidLog = nil
+	match.Data.RedoLog = nil
+	match.Data.PendingLobby = models.LobbyState{}
+	match.Data.EmptyRaidCounts.TeamA = 0
+	match.Data.EmptyRaidCounts.TeamB = 0
+	match.Data.SubstitutionsUsed = models.TeamCounts{}
+	match.Data.TieBreak = models.TieBreakState{}
+	match.Data.OutQueue = models.OutQueues{}
+	match.Data.RaidNumber = startRaid
+
+	for _, entry := range entries {
+		applyRaidLogEntry(match, entry)
+	}
+	syncOutQueues(match)
+	match.Data.Awards = ComputeAwards(match.Data.PlayerStats)
+}
+
+// applyRaidLogEntry runs a single logged raid back through the matching processor.
+// Yellow card suspensions counted in raids end as the raid number passes them.
+func applyRaidLogEntry(match *models.EnhancedStatsMessage, entry models.RaidLogEntry) []Event {
+	events := applyLoggedCommand(match, entry)
+	return append(events, releaseSuspensions(match, -1)...)
+}
+
+// applyLoggedCommand dispatches a raid log entry on its result
+func applyLoggedCommand(match *models.EnhancedStatsMessage, entry models.RaidLogEntry) []Event {
+	switch entry.Result {
+	case "substitution":
+		return applySubstitution(match, substitutionFromLogEntry(entry), entry.RaidingTeam)
+	case "technicalPoint":
+		return applyTechnicalPoint(match, TechnicalPointPayload{Team: entry.RaidingTeam, Points: entry.Points, Reason: entry.Reason})
+	case "card":
+		return applyCard(match, entry)
+	case "tieBreak":
+		return startTieBreak(match)
+	case "lobbyTouch":
+		if len(entry.LobbyEvents) == 0 {
+			return nil
+		}
+		ev := entry.LobbyEvents[0]
+		return applyLobbyTouch(match, LobbyTouchPayload{
+			TouchedPlayerId: ev.TouchedPlayerId,
+			IsRaider:        ev.IsRaider,
+			ScoringTeam:     ev.ScoringTeam,
+			RaiderId:        entry.RaiderId,
+			DefenderIds:     entry.DefenderIds,
+			RaidNumber:      ev.RaidNumber,
+		})
+	}
+
+	payload, ok := raidPayloadFromLogEntry(entry)
+	if !ok {
+		return nil
+	}
+	return applyRaid(match, payload)
+}
+
+// raidPayloadFromLogEntry converts a raid log entry back into the scorer payload
+// that produced it. Lobby touches are not raids and return false.
+func raidPayloadFromLogEntry(entry models.RaidLogEntry) (RaidPayload, bool) {
+	payload := RaidPayload{
+		RaiderID:    entry.RaiderId,
+		DefenderIDs: entry.DefenderIds,
+		BonusTaken:  entry.BonusTaken,
+	}
+	switch entry.Result {
+	case "raidSuccess":
+		payload.RaidType = "successful"
+	case "defenseSuccess":
+		payload.RaidType = "defense"
+	case "emptyRaid", "doOrDieRaid":
+		payload.RaidType = "empty"
+		payload.DefenderIDs = []string{}
+	default:
+		return RaidPayload{}, false
+	}
+	return payload, true
+}
+
+// substitutionFromLogEntry converts a substitution raid log entry back into its payload
+func substitutionFromLogEntry(entry models.RaidLogEntry) SubstitutionPayload {
+	return SubstitutionPayload{OutPlayerID: entry.SubOutId, InPlayerID: entry.SubInId}
+}
@@ -0,0 +1,155 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// Review actions and verdicts accepted by the review command
+const (
+	ReviewRequest = "request"
+	ReviewVerdict = "verdict"
+
+	VerdictUpheld     = "upheld"
+	VerdictOverturned = "overturned"
+)
+
+// ReviewPayload asks for a raid to be reviewed, or gives the referee's verdict on the
+// review in progress. Correction is the raid as it should have been scored and is
+// only read when the verdict is overturned.
+type ReviewPayload struct {
+	Action     string      `json:"action"`
+	Team       string      `json:"team,omitempty"`       // request: "A" or "B"
+	RaidNumber int         `json:"raidNumber,omitempty"` // request: raid to review
+	Verdict    string      `json:"verdict,omitempty"`
+	Correction RaidPayload `json:"correction,omitempty"`
+}
+
+// reviewInProgress reports whether scoring is frozen by a review
+func reviewInProgress(match *models.EnhancedStatsMessage) bool {
+	return match.Data.Review.Status == models.ReviewStatusInProgress
+}
+
+// applyReview starts a review or settles the one in progress
+func applyReview(match *models.EnhancedStatsMessage, p ReviewPayload) ([]Event, error) {
+	switch p.Action {
+	case ReviewRequest:
+		return requestReview(match, p)
+	case ReviewVerdict:
+		return settleReview(match, p)
+	}
+	return nil, fmt.Errorf("unknown review action: %s", p.Action)
+}
+
+// requestReview puts the most recent raid under review, as long as it was played in
+// the current half. Each team may lose ReviewsPerHalf reviews per half; a review
+// that overturns the call is not counted.
+func requestReview(match *models.EnhancedStatsMessage, p ReviewPayload) ([]Event, error) {
+	if reviewInProgress(match) {
+		return nil, fmt.Errorf("raid %d is already under review", match.Data.Review.RaidNumber)
+	}
+	if err := checkMatchNotOver(match); err != nil {
+		return nil, err
+	}
+	if p.Team != "A" && p.Team != "B" {
+		return nil, fmt.Errorf("review team must be A or B")
+	}
+	limit := rulesOf(match).ReviewsPerHalf
+	if limit == 0 {
+		return nil, fmt.Errorf("reviews are not used under %s rules", rulesOf(match).Name)
+	}
+	used := match.Data.ReviewsUsed.TeamA
+	if p.Team == "B" {
+		used = match.Data.ReviewsUsed.TeamB
+	}
+	if used >= limit {
+		return nil, fmt.Errorf("team %s has no reviews left this half", p.Team)
+	}
+	if reviewedRaidIndex(match, p.RaidNumber) < 0 {
+		return nil, fmt.Errorf("raid %d not found in the raid log", p.RaidNumber)
+	}
+	if start := match.Data.Clock.SecondHalfStartRaid; start > 0 && p.RaidNumber < start {
+		return nil, fmt.Errorf("raid %d was played in the first half", p.RaidNumber)
+	}
+	if latest := match.Data.RaidLog[latestRaidIndex(match)].RaidNumber; p.RaidNumber != latest {
+		return nil, fmt.Errorf("only the most recent raid (raid %d) can be reviewed", latest)
+	}
+
+	match.Data.Review = models.ReviewState{Status: models.ReviewStatusInProgress, Team: p.Team, RaidNumber: p.RaidNumber}
+	return []Event{{Type: EventReviewRequested, RaidNumber: p.RaidNumber, Team: p.Team}}, nil
+}
+
+// settleReview ends the review in progress. An overturned raid is replaced by the
+// correction and everything logged after it is replayed on top. Each later entry
+// is checked again as it is replayed, as redo would check it; if the correction
+// leaves one impossible, such as substituting a player who is now out, the
+// overturn is refused.
+func settleReview(match *models.EnhancedStatsMessage, p ReviewPayload) ([]Event, error) {
+	if !reviewInProgress(match) {
+		return nil, fmt.Errorf("no review in progress")
+	}
+	review := match.Data.Review
+
+	switch p.Verdict {
+	case VerdictUpheld:
+		if review.Team == "A" {
+			match.Data.ReviewsUsed.TeamA++
+		} else {
+			match.Data.ReviewsUsed.TeamB++
+		}
+		match.Data.Review = models.ReviewState{}
+		return []Event{{Type: EventReviewUpheld, RaidNumber: review.RaidNumber, Team: review.Team}}, nil
+
+	case VerdictOverturned:
+		i := reviewedRaidIndex(match, review.RaidNumber)
+		if i < 0 {
+			return nil, fmt.Errorf("raid %d not found in the raid log", review.RaidNumber)
+		}
+		log := cloneRaidLog(match.Data.RaidLog)
+		corrected := Clone(*match)
+		Rebuild(&corrected, log[:i])
+		if corrected.Data.RaidNumber != review.RaidNumber {
+			return nil, fmt.Errorf("raid %d cannot be re-scored from the raid log", review.RaidNumber)
+		}
+		if err := validateRaidPlayers(p.Correction, &corrected); err != nil {
+			return nil, fmt.Errorf("invalid correction: %v", err)
+		}
+		applyRaid(&corrected, p.Correction)
+		for _, entry := range log[i+1:] {
+			if err := validateLogEntry(entry, &corrected, validateRaidPlayers); err != nil {
+				return nil, fmt.Errorf("the correction does not fit what was logged after it: %v", err)
+			}
+			applyRaidLogEntry(&corrected, entry)
+		}
+		corrected.Data.Review = models.ReviewState{}
+		corrected.Data.RedoLog = nil
+		*match = corrected
+		return []Event{{Type: EventReviewOverturned, RaidNumber: review.RaidNumber, Team: review.Team}}, nil
+	}
+	return nil, fmt.Errorf("verdict must be upheld or overturned")
+}
+
+// reviewedRaidIndex returns the raid log index of the given raid, or -1. Only raids
+// can be reviewed, not lobby touches or referee decisions logged alongside them.
+func reviewedRaidIndex(match *models.EnhancedStatsMessage, raidNumber int) int {
+	for i, entry := range match.Data.RaidLog {
+		if entry.RaidNumber != raidNumber {
+			continue
+		}
+		if _, ok := raidPayloadFromLogEntry(entry); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// latestRaidIndex returns the raid log index of the most recent raid, or -1
+func latestRaidIndex(match *models.EnhancedStatsMessage) int {
+	for i := len(match.Data.RaidLog) - 1; i >= 0; i-- {
+		if _, ok := raidPayloadFromLogEntry(match.Data.RaidLog[i]); ok {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,163 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func reviewCmd(p ReviewPayload) Command {
+	return Command{Type: CommandReview, Review: p, At: testTime}
+}
+
+// newReviewMatch plays three raids under Pro rules: A scores on b1, B is tackled by
+// a2 and a3, then A's raid is empty.
+func newReviewMatch(t *testing.T) models.EnhancedStatsMessage {
+	t.Helper()
+	state := newTestMatch()
+	state.Data.Rules = models.DefaultRuleSet()
+	for _, cmd := range []Command{
+		raid("successful", "a1", false, "b1"),
+		raid("defense", "b2", false, "a2", "a3"),
+		raid("empty", "a4", false),
+	} {
+		var err error
+		if state, _, err = Apply(state, cmd); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+	return state
+}
+
+func TestReviewRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m *models.EnhancedStatsMessage)
+		payload ReviewPayload
+		wantErr bool
+	}{
+		{name: "review the latest raid", payload: ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 3}},
+		{name: "raid must exist", payload: ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 9}, wantErr: true},
+		{name: "only the latest raid", payload: ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 1}, wantErr: true},
+		{name: "raid from the first half", setup: func(m *models.EnhancedStatsMessage) { m.Data.Clock.SecondHalfStartRaid = 4 }, payload: ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 3}, wantErr: true},
+		{name: "team is required", payload: ReviewPayload{Action: ReviewRequest, RaidNumber: 3}, wantErr: true},
+		{name: "reviews used up", setup: func(m *models.EnhancedStatsMessage) { m.Data.ReviewsUsed.TeamB = 1 }, payload: ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 3}, wantErr: true},
+		{name: "rule set without reviews", setup: func(m *models.EnhancedStatsMessage) { m.Data.Rules, _ = models.RuleSetPreset(models.RuleSetAmateur) }, payload: ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 3}, wantErr: true},
+		{name: "verdict needs a review", payload: ReviewPayload{Action: ReviewVerdict, Verdict: VerdictUpheld}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newReviewMatch(t)
+			if tt.setup != nil {
+				tt.setup(&state)
+			}
+			next, _, err := Apply(state, reviewCmd(tt.payload))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next.Data.Review.Status != models.ReviewStatusInProgress || next.Data.Review.RaidNumber != tt.payload.RaidNumber {
+				t.Fatalf("review = %+v", next.Data.Review)
+			}
+			if _, _, err := Apply(next, raid("defense", "b3", false, "a1")); err == nil {
+				t.Errorf("expected raids to be frozen during a review")
+			}
+			if _, _, err := Apply(next, Command{Type: CommandUndo, At: testTime}); err == nil {
+				t.Errorf("expected undo to be frozen during a review")
+			}
+		})
+	}
+}
+
+func TestReviewUpheld(t *testing.T) {
+	state := newReviewMatch(t)
+	var err error
+	if state, _, err = Apply(state, reviewCmd(ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 3})); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	before := state
+	if state, _, err = Apply(state, reviewCmd(ReviewPayload{Action: ReviewVerdict, Verdict: VerdictUpheld})); err != nil {
+		t.Fatalf("verdict: %v", err)
+	}
+	if state.Data.ReviewsUsed.TeamB != 1 || state.Data.Review.Status != "" {
+		t.Fatalf("reviews used = %+v, review = %+v", state.Data.ReviewsUsed, state.Data.Review)
+	}
+	if state.Data.TeamA.Score != before.Data.TeamA.Score || state.Data.TeamB.Score != before.Data.TeamB.Score {
+		t.Errorf("upheld review changed the score")
+	}
+}
+
+func TestReviewOverturned(t *testing.T) {
+	state := newReviewMatch(t)
+	var err error
+	if state, _, err = Apply(state, reviewCmd(ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 3})); err != nil {
+		t.Fatalf("request: %v", err)
+	}
+
+	// The raider was actually tackled by b3 and b4
+	correction := RaidPayload{RaidType: "defense", RaiderID: "a4", DefenderIDs: []string{"b3", "b4"}}
+	if _, _, err := Apply(state, reviewCmd(ReviewPayload{Action: ReviewVerdict, Verdict: VerdictOverturned, Correction: RaidPayload{RaidType: "defense", RaiderID: "b3", DefenderIDs: []string{"a4"}}})); err == nil {
+		t.Fatalf("expected a correction by the wrong raiding team to be rejected")
+	}
+	got, events, err := Apply(state, reviewCmd(ReviewPayload{Action: ReviewVerdict, Verdict: VerdictOverturned, Correction: correction}))
+	if err != nil {
+		t.Fatalf("verdict: %v", err)
+	}
+	if types := eventTypes(events); types[0] != EventReviewOverturned {
+		t.Errorf("events = %v", types)
+	}
+
+	// Same as scoring the corrected raid in the first place
+	want := newTestMatch()
+	want.Data.Rules = models.DefaultRuleSet()
+	for _, cmd := range []Command{
+		raid("successful", "a1", false, "b1"),
+		raid("defense", "b2", false, "a2", "a3"),
+		{Type: CommandRaid, Raid: correction, At: testTime},
+	} {
+		if want, _, err = Apply(want, cmd); err != nil {
+			t.Fatalf("expected state: %v", err)
+		}
+	}
+	assertSameMatchState(t, "overturned", got, want)
+	if got.Data.ReviewsUsed.TeamB != 0 || got.Data.Review.Status != "" {
+		t.Errorf("successful review should be kept: reviews used = %+v, review = %+v", got.Data.ReviewsUsed, got.Data.Review)
+	}
+}
+
+func TestReviewOverturnedChecksLaterEntries(t *testing.T) {
+	state := newBenchMatch(t)
+	var err error
+	for _, cmd := range []Command{
+		raid("successful", "a1", false, "b1"),
+		subCmd("a1", "a8"),
+		reviewCmd(ReviewPayload{Action: ReviewRequest, Team: "B", RaidNumber: 1}),
+	} {
+		if state, _, err = Apply(state, cmd); err != nil {
+			t.Fatalf("setup: %v", err)
+		}
+	}
+
+	// a1 was tackled in raid 1, so could not have been substituted afterwards
+	tackled := RaidPayload{RaidType: "defense", RaiderID: "a1", DefenderIDs: []string{"b1", "b2"}}
+	_, _, err = Apply(state, reviewCmd(ReviewPayload{Action: ReviewVerdict, Verdict: VerdictOverturned, Correction: tackled}))
+	if err == nil || !strings.Contains(err.Error(), "substitution") {
+		t.Fatalf("error = %v, want the overturn refused naming the substitution", err)
+	}
+
+	touchedB4 := RaidPayload{RaidType: "successful", RaiderID: "a1", DefenderIDs: []string{"b4"}}
+	got, _, err := Apply(state, reviewCmd(ReviewPayload{Action: ReviewVerdict, Verdict: VerdictOverturned, Correction: touchedB4}))
+	if err != nil {
+		t.Fatalf("verdict: %v", err)
+	}
+	if got.Data.PlayerStats["b4"].Status != "out" || got.Data.PlayerStats["a8"].Status != "in" || got.Data.SubstitutionsUsed.TeamA != 1 {
+		t.Errorf("b4 %q, a8 %q, substitutions %+v", got.Data.PlayerStats["b4"].Status, got.Data.PlayerStats["a8"].Status, got.Data.SubstitutionsUsed)
+	}
+}
@@ -0,0 +1,92 @@
+package scoring
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func TestRevivalOrder(t *testing.T) {
+	m := newTestMatch()
+	var err error
+	for i, cmd := range []Command{
+		raid("successful", "a1", false, "b5", "b2"),
+		raid("empty", "b1", false),
+		raid("successful", "a3", false, "b4"),
+	} {
+		if m, _, err = Apply(m, cmd); err != nil {
+			t.Fatalf("raid %d: %v", i+1, err)
+		}
+	}
+	want := []models.OutPlayer{{PlayerID: "b5", OutAtRaid: 1}, {PlayerID: "b2", OutAtRaid: 1}, {PlayerID: "b4", OutAtRaid: 3}}
+	if !reflect.DeepEqual(m.Data.OutQueue.TeamB, want) {
+		t.Fatalf("team B out queue = %+v, want %+v", m.Data.OutQueue.TeamB, want)
+	}
+	beforeRevival := Clone(m)
+
+	// One raid point brings back b5, who has been out longest, not b2 who comes first in the roster
+	m, events, err := Apply(m, raid("successful", "b1", false, "a1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revived []string
+	for _, e := range events {
+		if e.Type == EventPlayersRevived {
+			revived = e.PlayerIDs
+		}
+	}
+	if !reflect.DeepEqual(revived, []string{"b5"}) {
+		t.Fatalf("revived %v, want [b5]", revived)
+	}
+	if got := m.Data.PlayerStats["b2"].Status; got != "out" {
+		t.Errorf("b2 status = %s, want out", got)
+	}
+	want = []models.OutPlayer{{PlayerID: "b2", OutAtRaid: 1}, {PlayerID: "b4", OutAtRaid: 3}}
+	if !reflect.DeepEqual(m.Data.OutQueue.TeamB, want) {
+		t.Errorf("team B out queue = %+v, want %+v", m.Data.OutQueue.TeamB, want)
+	}
+	if want := []models.OutPlayer{{PlayerID: "a1", OutAtRaid: 4}}; !reflect.DeepEqual(m.Data.OutQueue.TeamA, want) {
+		t.Errorf("team A out queue = %+v, want %+v", m.Data.OutQueue.TeamA, want)
+	}
+
+	undone, _, err := Apply(m, Command{Type: CommandUndo, At: testTime})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(undone.Data.OutQueue, beforeRevival.Data.OutQueue) {
+		t.Errorf("out queue after undo = %+v, want %+v", undone.Data.OutQueue, beforeRevival.Data.OutQueue)
+	}
+}
+
+func TestRevivalOrderWithoutQueue(t *testing.T) {
+	// States saved before the out queue existed fall back to roster order
+	m := newTestMatch()
+	setStatus(&m, "out", "b3", "b1")
+
+	m, _, err := Apply(m, raid("defense", "a1", false, "b2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Data.PlayerStats["b1"].Status; got != "in" {
+		t.Errorf("b1 status = %s, want in", got)
+	}
+	if want := []models.OutPlayer{{PlayerID: "b3"}}; !reflect.DeepEqual(m.Data.OutQueue.TeamB, want) {
+		t.Errorf("team B out queue = %+v, want %+v", m.Data.OutQueue.TeamB, want)
+	}
+}
+
+func TestOutQueueDropsCardedPlayers(t *testing.T) {
+	m := newTestMatch()
+	m, _, err := Apply(m, raid("successful", "a1", false, "b2", "b3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _, err = Apply(m, cardCmd("b2", CardRed, 0, 0, testTime))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []models.OutPlayer{{PlayerID: "b3", OutAtRaid: 1}}; !reflect.DeepEqual(m.Data.OutQueue.TeamB, want) {
+		t.Errorf("team B out queue = %+v, want %+v", m.Data.OutQueue.TeamB, want)
+	}
+}
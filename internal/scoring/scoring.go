@@ -0,0 +1,324 @@
+// Package scoring implements the kabaddi rules engine. It is pure: it never
+// touches Redis, Mongo or the network, so the same code path can be used by the
+// live scorer socket, the REST raid endpoint and event store replay.
+package scoring
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// Command types understood by Apply
+const (
+	CommandInitialState   = "initialState"
+	CommandRaid           = "raid"
+	CommandLobbyTouch     = "lobbyTouch"
+	CommandUndo           = "undo"
+	CommandRedo           = "redo"
+	CommandClock          = "clock"
+	CommandSubstitution   = "substitution"
+	CommandTechnicalPoint = "technicalPoint"
+	CommandCard           = "card"
+	CommandTieBreak       = "tieBreak" // start the knockout tie-break
+	CommandReview         = "review"
+	CommandFullState      = "fullState" // legacy full state overwrite
+)
+
+// Event types emitted by Apply describing what a command did to the match
+const (
+	EventStateReset       = "stateReset"
+	EventRaidSuccess      = "raidSuccess"
+	EventDefenseSuccess   = "defenseSuccess"
+	EventEmptyRaid        = "emptyRaid"
+	EventDoOrDieRaid      = "doOrDieRaid"
+	EventLobbyTouch       = "lobbyTouch"
+	EventSuperRaid        = "superRaid"
+	EventSuperTackle      = "superTackle"
+	EventAllOut           = "allOut"
+	EventPlayersOut       = "playersOut"
+	EventPlayersRevived   = "playersRevived"
+	EventRaidUndone       = "raidUndone"
+	EventRaidRedone       = "raidRedone"
+	EventHalfStarted      = "halfStarted"
+	EventClockPaused      = "clockPaused"
+	EventClockResumed     = "clockResumed"
+	EventTimeout          = "timeout"
+	EventHalfEnded        = "halfEnded"
+	EventRegulationEnded  = "regulationEnded"
+	EventSubstitution     = "substitution"
+	EventTechnicalPoint   = "technicalPoint"
+	EventCard             = "card"
+	EventPlayersReturned  = "playersReturned" // yellow card suspension over
+	EventTieBreakStarted  = "tieBreakStarted"
+	EventGoldenRaid       = "goldenRaid" // shootout ended level
+	EventTieBreakDecided  = "tieBreakDecided"
+	EventReviewRequested  = "reviewRequested"
+	EventReviewUpheld     = "reviewUpheld"
+	EventReviewOverturned = "reviewOverturned"
+)
+
+// CommandTypes lists the commands a scorer can send; the legacy fullState overwrite is not one of them
+var CommandTypes = []string{
+	CommandInitialState, CommandRaid, CommandLobbyTouch, CommandUndo, CommandRedo, CommandClock,
+	CommandSubstitution, CommandTechnicalPoint, CommandCard, CommandTieBreak, CommandReview,
+}
+
+// EventTypes lists every event type Apply can emit
+var EventTypes = []string{
+	EventStateReset, EventRaidSuccess, EventDefenseSuccess, EventEmptyRaid, EventDoOrDieRaid, EventLobbyTouch,
+	EventSuperRaid, EventSuperTackle, EventAllOut, EventPlayersOut, EventPlayersRevived, EventRaidUndone,
+	EventRaidRedone, EventHalfStarted, EventClockPaused, EventClockResumed, EventTimeout, EventHalfEnded,
+	EventRegulationEnded, EventSubstitution, EventTechnicalPoint, EventCard, EventPlayersReturned,
+	EventTieBreakStarted, EventGoldenRaid, EventTieBreakDecided, EventReviewRequested, EventReviewUpheld,
+	EventReviewOverturned,
+}
+
+// RaidPayload represents the payload expected from frontend when submitting a raid
+// Frontend sends ONLY raw data - backend determines everything else
+type RaidPayload struct {
+	RaidType    string   `json:"raidType"`
+	RaiderID    string   `json:"raiderId"`
+	DefenderIDs []string `json:"defenderIds"`
+	BonusTaken  bool     `json:"bonusTaken"`
+	// Note: RaidingTeam and EmptyRaidCounts removed - backend calculates these
+}
+
+// LobbyTouchPayload represents a lobby touch reported by the scorer during a raid
+type LobbyTouchPayload struct {
+	TouchedPlayerId string   `json:"touchedPlayerId"`
+	IsRaider        bool     `json:"isRaider"`
+	ScoringTeam     string   `json:"scoringTeam"`
+	RaiderId        string   `json:"raiderId"`
+	DefenderIds     []string `json:"defenderIds"`
+	RaidNumber      int      `json:"raidNumber"`
+}
+
+// Command is a single scorer instruction. Only the field matching Type is read.
+type Command struct {
+	Type   string
+	Raid   RaidPayload
+	Lobby  LobbyTouchPayload
+	Clock  ClockPayload
+	Sub    SubstitutionPayload
+	Tech   TechnicalPointPayload
+	Card   CardPayload
+	Review ReviewPayload
+	State  models.EnhancedStatsMessage // initialState / fullState
+	At     time.Time                   // when the command was issued; drives the match clock and LastScoreChangeAt
+}
+
+// Event describes one observable consequence of applying a command
+type Event struct {
+	Type       string   `json:"type"`
+	RaidNumber int      `json:"raidNumber,omitempty"`
+	Team       string   `json:"team,omitempty"` // "A" or "B"
+	PlayerIDs  []string `json:"playerIds,omitempty"`
+	Points     int      `json:"points,omitempty"`
+	Half       int      `json:"half,omitempty"` // clock events only
+}
+
+// Apply validates and applies cmd to state and returns the resulting state along
+// with the events it produced. The input state is never modified.
+func Apply(state models.EnhancedStatsMessage, cmd Command) (models.EnhancedStatsMessage, []Event, error) {
+	if cmd.Type == CommandInitialState || cmd.Type == CommandFullState {
+		next := Clone(cmd.State)
+		if cmd.Type == CommandFullState && next.Data.Rules.IsZero() {
+			// Legacy clients do not know about rule sets; keep the ones already in force
+			next.Data.Rules = state.Data.Rules
+		}
+		if cmd.Type == CommandInitialState {
+			if err := ValidateInitialState(&next); err != nil {
+				return state, nil, err
+			}
+			assignStartingLineups(&next)
+		}
+		syncOutQueues(&next)
+		if next.Data.LastScoreChangeAt == 0 {
+			next.Data.LastScoreChangeAt = cmd.At.Unix()
+		}
+		return next, []Event{{Type: EventStateReset, RaidNumber: next.Data.RaidNumber}}, nil
+	}
+
+	if reviewInProgress(&state) && cmd.Type != CommandReview && cmd.Type != CommandClock {
+		return state, nil, fmt.Errorf("raid %d is under review; give the verdict first", state.Data.Review.RaidNumber)
+	}
+
+	match := Clone(state)
+	prevTeamAScore := match.Data.TeamA.Score
+	prevTeamBScore := match.Data.TeamB.Score
+
+	var events []Event
+	switch cmd.Type {
+	case CommandRaid:
+		if err := ValidateRaid(cmd.Raid, &match); err != nil {
+			return state, nil, err
+		}
+		events = applyRaid(&match, cmd.Raid)
+		// A fresh raid invalidates anything that was undone before it
+		match.Data.RedoLog = nil
+
+	case CommandLobbyTouch:
+		if err := checkClockAllowsPlay(&match); err != nil {
+			return state, nil, err
+		}
+		events = applyLobbyTouch(&match, cmd.Lobby)
+		match.Data.RedoLog = nil
+
+	case CommandClock:
+		ev, err := applyClock(&match, cmd.Clock, cmd.At)
+		if err != nil {
+			return state, nil, err
+		}
+		events = ev
+
+	case CommandSubstitution:
+		team, err := ValidateSubstitution(cmd.Sub, &match)
+		if err != nil {
+			return state, nil, err
+		}
+		events = applySubstitution(&match, cmd.Sub, team)
+		match.Data.RedoLog = nil
+
+	case CommandTechnicalPoint:
+		tp := cmd.Tech
+		if err := ValidateTechnicalPoint(&tp, &match); err != nil {
+			return state, nil, err
+		}
+		events = applyTechnicalPoint(&match, tp)
+		match.Data.RedoLog = nil
+
+	case CommandCard:
+		entry, err := ValidateCard(cmd.Card, &match, cmd.At)
+		if err != nil {
+			return state, nil, err
+		}
+		events = applyCard(&match, entry)
+		match.Data.RedoLog = nil
+
+	case CommandTieBreak:
+		if err := validateTieBreakStart(&match); err != nil {
+			return state, nil, err
+		}
+		events = startTieBreak(&match)
+		match.Data.RedoLog = nil
+
+	case CommandReview:
+		ev, err := applyReview(&match, cmd.Review)
+		if err != nil {
+			return state, nil, err
+		}
+		events = ev
+
+	case CommandUndo:
+		ev, err := undoLastRaid(&match)
+		if err != nil {
+			return state, nil, err
+		}
+		events = ev
+
+	case CommandRedo:
+		ev, err := redoLastRaid(&match)
+		if err != nil {
+			return state, nil, err
+		}
+		events = ev
+
+	default:
+		return state, nil, fmt.Errorf("unknown command type: %s", cmd.Type)
+	}
+
+	events = append(events, releaseSuspensions(&match, matchSecondsPlayed(match.Data.Clock, cmd.At))...)
+	syncOutQueues(&match)
+	match.Data.Awards = ComputeAwards(match.Data.PlayerStats)
+	if match.Data.TeamA.Score != prevTeamAScore || match.Data.TeamB.Score != prevTeamBScore {
+		match.Data.LastScoreChangeAt = cmd.At.Unix()
+	}
+	return match, events, nil
+}
+
+// applyRaid dispatches a validated raid payload to the matching processor
+func applyRaid(match *models.EnhancedStatsMessage, raid RaidPayload) []Event {
+	process := func() []Event {
+		switch raid.RaidType {
+		case "successful":
+			return processSuccessfulRaid(match, raid)
+		case "defense":
+			return processDefenseSuccess(match, raid)
+		case "empty":
+			return processEmptyRaid(match, raid)
+		}
+		return nil
+	}
+	if tieBreakActive(match) {
+		return scoreTieBreak(match, TeamOf(match, raid.RaiderID), process)
+	}
+	return process()
+}
+
+// applyLobbyTouch processes a lobby touch, crediting tie-break points separately
+func applyLobbyTouch(match *models.EnhancedStatsMessage, lobby LobbyTouchPayload) []Event {
+	process := func() []Event { return processLobbyTouch(match, lobby) }
+	if tieBreakActive(match) {
+		return scoreTieBreak(match, "", process)
+	}
+	return process()
+}
+
+// Clone returns a deep copy of the match state so callers can mutate it freely
+func Clone(state models.EnhancedStatsMessage) models.EnhancedStatsMessage {
+	out := state
+	if state.Data.PlayerStats != nil {
+		out.Data.PlayerStats = make(map[string]models.PlayerStat, len(state.Data.PlayerStats))
+		for id, p := range state.Data.PlayerStats {
+			out.Data.PlayerStats[id] = p
+		}
+	}
+	out.Data.TeamAPlayerIDs = cloneStrings(state.Data.TeamAPlayerIDs)
+	out.Data.TeamBPlayerIDs = cloneStrings(state.Data.TeamBPlayerIDs)
+	out.Data.StartingBench = cloneStrings(state.Data.StartingBench)
+	out.Data.RaidLog = cloneRaidLog(state.Data.RaidLog)
+	out.Data.RedoLog = cloneRaidLog(state.Data.RedoLog)
+	out.Data.OutQueue.TeamA = cloneOutPlayers(state.Data.OutQueue.TeamA)
+	out.Data.OutQueue.TeamB = cloneOutPlayers(state.Data.OutQueue.TeamB)
+	out.Data.PendingLobby.Events = cloneLobbyEvents(state.Data.PendingLobby.Events)
+	out.Data.RaidDetails.Defenders = cloneStrings(state.Data.RaidDetails.Defenders)
+	out.Data.RaidDetails.LobbyDefenders = cloneStrings(state.Data.RaidDetails.LobbyDefenders)
+	return out
+}
+
+// cloneStrings copies a slice while preserving the nil/empty distinction used in JSON
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(s)), s...)
+}
+
+func cloneLobbyEvents(events []models.LobbyEvent) []models.LobbyEvent {
+	if events == nil {
+		return nil
+	}
+	return append(make([]models.LobbyEvent, 0, len(events)), events...)
+}
+
+func cloneOutPlayers(queue []models.OutPlayer) []models.OutPlayer {
+	if queue == nil {
+		return nil
+	}
+	return append(make([]models.OutPlayer, 0, len(queue)), queue...)
+}
+
+func cloneRaidLog(entries []models.RaidLogEntry) []models.RaidLogEntry {
+	if entries == nil {
+		return nil
+	}
+	out := make([]models.RaidLogEntry, len(entries))
+	for i, e := range entries {
+		e.DefenderIds = cloneStrings(e.DefenderIds)
+		e.LobbyEvents = cloneLobbyEvents(e.LobbyEvents)
+		out[i] = e
+	}
+	return out
+}
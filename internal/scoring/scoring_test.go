@@ -0,0 +1,536 @@
+package scoring
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+var testTime = time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+// newTestMatch returns a 7-a-side match where team A raids first at raid 1
+func newTestMatch() models.EnhancedStatsMessage {
+	var m models.EnhancedStatsMessage
+	m.Type = "enhancedStats"
+	m.Data.TeamA = models.TeamStat{Name: "Team A"}
+	m.Data.TeamB = models.TeamStat{Name: "Team B"}
+	m.Data.PlayerStats = map[string]models.PlayerStat{}
+	for _, id := range []string{"a1", "a2", "a3", "a4", "a5", "a6", "a7"} {
+		m.Data.TeamAPlayerIDs = append(m.Data.TeamAPlayerIDs, id)
+		m.Data.PlayerStats[id] = models.PlayerStat{ID: id, Name: id, Status: "in"}
+	}
+	for _, id := range []string{"b1", "b2", "b3", "b4", "b5", "b6", "b7"} {
+		m.Data.TeamBPlayerIDs = append(m.Data.TeamBPlayerIDs, id)
+		m.Data.PlayerStats[id] = models.PlayerStat{ID: id, Name: id, Status: "in"}
+	}
+	m.Data.FirstRaidingTeam = "teamA"
+	m.Data.RaidNumber = 1
+	return m
+}
+
+// addBench appends n extra players to each roster (a8.., b8..)
+func addBench(m *models.EnhancedStatsMessage, n int) {
+	for i := 8; i < 8+n; i++ {
+		for _, team := range []string{"a", "b"} {
+			id := fmt.Sprintf("%s%d", team, i)
+			if team == "a" {
+				m.Data.TeamAPlayerIDs = append(m.Data.TeamAPlayerIDs, id)
+			} else {
+				m.Data.TeamBPlayerIDs = append(m.Data.TeamBPlayerIDs, id)
+			}
+			m.Data.PlayerStats[id] = models.PlayerStat{ID: id, Name: id, Status: "in"}
+		}
+	}
+}
+
+func setStatus(m *models.EnhancedStatsMessage, status string, ids ...string) {
+	for _, id := range ids {
+		p := m.Data.PlayerStats[id]
+		p.Status = status
+		m.Data.PlayerStats[id] = p
+	}
+}
+
+func raid(raidType, raider string, bonus bool, defenders ...string) Command {
+	if defenders == nil {
+		defenders = []string{}
+	}
+	return Command{
+		Type: CommandRaid,
+		Raid: RaidPayload{RaidType: raidType, RaiderID: raider, DefenderIDs: defenders, BonusTaken: bonus},
+		At:   testTime,
+	}
+}
+
+func eventTypes(events []Event) []string {
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		out = append(out, e.Type)
+	}
+	return out
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name         string
+		setup        func(m *models.EnhancedStatsMessage)
+		cmd          Command
+		wantErr      bool
+		wantScoreA   int
+		wantScoreB   int
+		wantStatus   map[string]string
+		wantEvents   []string
+		wantRaidNum  int
+		wantEmptyA   int
+		wantLogEntry *models.RaidLogEntry
+	}{
+		{
+			name:        "successful raid with one touch",
+			cmd:         raid("successful", "a1", false, "b1"),
+			wantScoreA:  1,
+			wantStatus:  map[string]string{"a1": "in", "b1": "out"},
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut},
+			wantRaidNum: 2,
+		},
+		{
+			name:        "super raid with three touches",
+			cmd:         raid("successful", "a1", false, "b1", "b2", "b3"),
+			wantScoreA:  3,
+			wantStatus:  map[string]string{"b1": "out", "b2": "out", "b3": "out"},
+			wantEvents:  []string{EventRaidSuccess, EventSuperRaid, EventPlayersOut},
+			wantRaidNum: 2,
+			wantLogEntry: &models.RaidLogEntry{
+				RaidNumber: 1, RaidingTeam: "A", RaiderId: "a1", DefenderIds: []string{"b1", "b2", "b3"},
+				Result: "raidSuccess", Points: 3, SuperRaid: true,
+			},
+		},
+		{
+			name:        "bonus does not count towards a super raid",
+			cmd:         raid("successful", "a1", true, "b1", "b2"),
+			wantScoreA:  3,
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut},
+			wantRaidNum: 2,
+		},
+		{
+			name: "touch points revive out raiding players",
+			setup: func(m *models.EnhancedStatsMessage) {
+				setStatus(m, "out", "a6", "a7")
+			},
+			cmd:         raid("successful", "a1", false, "b1"),
+			wantScoreA:  1,
+			wantStatus:  map[string]string{"a6": "in", "a7": "out"},
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut, EventPlayersRevived},
+			wantRaidNum: 2,
+		},
+		{
+			name:        "normal tackle",
+			cmd:         raid("defense", "a1", false, "b1", "b2"),
+			wantScoreB:  1,
+			wantStatus:  map[string]string{"a1": "out"},
+			wantEvents:  []string{EventDefenseSuccess, EventPlayersOut},
+			wantRaidNum: 2,
+		},
+		{
+			name: "super tackle with three defenders on the mat",
+			setup: func(m *models.EnhancedStatsMessage) {
+				setStatus(m, "out", "b4", "b5", "b6", "b7")
+			},
+			cmd:         raid("defense", "a1", false, "b1"),
+			wantScoreB:  2,
+			wantStatus:  map[string]string{"a1": "out", "b4": "in", "b5": "out"},
+			wantEvents:  []string{EventDefenseSuccess, EventSuperTackle, EventPlayersOut, EventPlayersRevived},
+			wantRaidNum: 2,
+		},
+		{
+			name: "bonus before a super tackle keeps the bonus and cancels the super tackle",
+			setup: func(m *models.EnhancedStatsMessage) {
+				setStatus(m, "out", "b4", "b5", "b6", "b7")
+			},
+			cmd:         raid("defense", "a1", true, "b1"),
+			wantScoreA:  1,
+			wantScoreB:  1,
+			wantEvents:  []string{EventDefenseSuccess, EventPlayersOut, EventPlayersRevived},
+			wantRaidNum: 2,
+		},
+		{
+			name:        "empty raid increments the empty raid count",
+			cmd:         raid("empty", "a1", false),
+			wantEvents:  []string{EventEmptyRaid},
+			wantRaidNum: 2,
+			wantEmptyA:  1,
+		},
+		{
+			name:        "bonus on an empty raid scores one",
+			cmd:         raid("empty", "a1", true),
+			wantScoreA:  1,
+			wantEvents:  []string{EventEmptyRaid},
+			wantRaidNum: 2,
+			wantEmptyA:  1,
+		},
+		{
+			name: "failed do-or-die raid puts the raider out",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.EmptyRaidCounts.TeamA = 2
+				setStatus(m, "out", "b7")
+			},
+			cmd:         raid("empty", "a1", false),
+			wantScoreB:  1,
+			wantStatus:  map[string]string{"a1": "out", "b7": "in"},
+			wantEvents:  []string{EventDoOrDieRaid, EventPlayersOut, EventPlayersRevived},
+			wantRaidNum: 2,
+			wantEmptyA:  0,
+		},
+		{
+			name: "bonus on a do-or-die raid keeps the raider safe",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.EmptyRaidCounts.TeamA = 2
+			},
+			cmd:         raid("empty", "a1", true),
+			wantScoreA:  1,
+			wantStatus:  map[string]string{"a1": "in"},
+			wantEvents:  []string{EventDoOrDieRaid},
+			wantRaidNum: 2,
+			wantEmptyA:  0,
+		},
+		{
+			name: "successful do-or-die raid is flagged and resets the count",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.EmptyRaidCounts.TeamA = 2
+			},
+			cmd:         raid("successful", "a1", false, "b1"),
+			wantScoreA:  1,
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut},
+			wantRaidNum: 2,
+			wantEmptyA:  0,
+			wantLogEntry: &models.RaidLogEntry{
+				RaidNumber: 1, RaidingTeam: "A", RaiderId: "a1", DefenderIds: []string{"b1"},
+				Result: "raidSuccess", Points: 1, DoOrDie: true,
+			},
+		},
+		{
+			name: "all out awards two extra points and revives the team",
+			setup: func(m *models.EnhancedStatsMessage) {
+				setStatus(m, "out", "b2", "b3", "b4", "b5", "b6", "b7")
+			},
+			cmd:         raid("successful", "a1", false, "b1"),
+			wantScoreA:  3,
+			wantStatus:  map[string]string{"b1": "in", "b7": "in"},
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut, EventAllOut},
+			wantRaidNum: 2,
+		},
+		{
+			name: "defender lobby touch scores for the raiding team",
+			cmd: Command{
+				Type:  CommandLobbyTouch,
+				Lobby: LobbyTouchPayload{TouchedPlayerId: "b3", ScoringTeam: "A", RaiderId: "a1", RaidNumber: 1},
+				At:    testTime,
+			},
+			wantScoreA:  1,
+			wantStatus:  map[string]string{"b3": "out"},
+			wantEvents:  []string{EventLobbyTouch, EventPlayersOut},
+			wantRaidNum: 2,
+			wantLogEntry: &models.RaidLogEntry{
+				RaidNumber: 1, RaidingTeam: "A", RaiderId: "a1", Result: "lobbyTouch", Points: 1,
+				LobbyEvents: []models.LobbyEvent{{TouchedPlayerId: "b3", ScoringTeam: "A", RaidNumber: 1}},
+			},
+		},
+		{
+			name: "raider lobby touch scores for the defending team",
+			cmd: Command{
+				Type:  CommandLobbyTouch,
+				Lobby: LobbyTouchPayload{TouchedPlayerId: "a1", IsRaider: true, ScoringTeam: "B", RaiderId: "a1", RaidNumber: 1},
+				At:    testTime,
+			},
+			wantScoreB:  1,
+			wantStatus:  map[string]string{"a1": "out"},
+			wantEvents:  []string{EventLobbyTouch, EventPlayersOut},
+			wantRaidNum: 2,
+		},
+		{
+			name: "pending lobby events are attached to the raid",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.PendingLobby.Events = []models.LobbyEvent{{TouchedPlayerId: "b2", ScoringTeam: "A", RaidNumber: 1}}
+			},
+			cmd:         raid("successful", "a1", false, "b1"),
+			wantScoreA:  1,
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut},
+			wantRaidNum: 2,
+			wantLogEntry: &models.RaidLogEntry{
+				RaidNumber: 1, RaidingTeam: "A", RaiderId: "a1", DefenderIds: []string{"b1"}, Result: "raidSuccess", Points: 1,
+				LobbyEvents: []models.LobbyEvent{{TouchedPlayerId: "b2", ScoringTeam: "A", RaidNumber: 1}},
+			},
+		},
+		{
+			name: "all out bonus comes from the rule set",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.Rules = models.RuleSet{Name: "League", SquadSize: 7, AllOutBonus: 3, SuperTackleMaxDefenders: 3, DoOrDieEmptyRaids: 2, SuperRaidPoints: 3}
+				setStatus(m, "out", "b2", "b3", "b4", "b5", "b6", "b7")
+			},
+			cmd:         raid("successful", "a1", false, "b1"),
+			wantScoreA:  4,
+			wantEvents:  []string{EventRaidSuccess, EventPlayersOut, EventAllOut},
+			wantRaidNum: 2,
+		},
+		{
+			name: "super raid threshold comes from the rule set",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.Rules = models.RuleSet{Name: "League", SquadSize: 7, AllOutBonus: 2, SuperTackleMaxDefenders: 3, DoOrDieEmptyRaids: 2, SuperRaidPoints: 2}
+			},
+			cmd:         raid("successful", "a1", false, "b1", "b2"),
+			wantScoreA:  2,
+			wantEvents:  []string{EventRaidSuccess, EventSuperRaid, EventPlayersOut},
+			wantRaidNum: 2,
+		},
+		{
+			name: "super tackle threshold comes from the rule set",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.Rules, _ = models.RuleSetPreset(models.RuleSetYouth)
+				setStatus(m, "out", "b4", "b5", "b6", "b7")
+			},
+			cmd:         raid("defense", "a1", false, "b1"),
+			wantScoreB:  1,
+			wantEvents:  []string{EventDefenseSuccess, EventPlayersOut, EventPlayersRevived},
+			wantRaidNum: 2,
+		},
+		{
+			name: "amateur rules have no do-or-die raids",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.Rules, _ = models.RuleSetPreset(models.RuleSetAmateur)
+				m.Data.EmptyRaidCounts.TeamA = 2
+			},
+			cmd:         raid("empty", "a1", false),
+			wantStatus:  map[string]string{"a1": "in"},
+			wantEvents:  []string{EventEmptyRaid},
+			wantRaidNum: 2,
+			wantEmptyA:  3,
+		},
+		{
+			name: "do-or-die after a custom number of empty raids",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.Rules = models.RuleSet{Name: "League", SquadSize: 7, AllOutBonus: 2, SuperTackleMaxDefenders: 3, DoOrDieEmptyRaids: 1, SuperRaidPoints: 3}
+				m.Data.EmptyRaidCounts.TeamA = 1
+			},
+			cmd:         raid("empty", "a1", false),
+			wantScoreB:  1,
+			wantStatus:  map[string]string{"a1": "out"},
+			wantEvents:  []string{EventDoOrDieRaid, EventPlayersOut},
+			wantRaidNum: 2,
+		},
+		{
+			name:    "wrong raiding team is rejected",
+			cmd:     raid("successful", "b1", false, "a1"),
+			wantErr: true,
+		},
+		{
+			name: "out raider is rejected",
+			setup: func(m *models.EnhancedStatsMessage) {
+				setStatus(m, "out", "a1")
+			},
+			cmd:     raid("empty", "a1", false),
+			wantErr: true,
+		},
+		{
+			name:    "successful raid needs defenders",
+			cmd:     raid("successful", "a1", false),
+			wantErr: true,
+		},
+		{
+			name:    "unknown raid type is rejected",
+			cmd:     raid("technical", "a1", false),
+			wantErr: true,
+		},
+		{
+			name:    "nothing to undo",
+			cmd:     Command{Type: CommandUndo, At: testTime},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestMatch()
+			if tt.setup != nil {
+				tt.setup(&state)
+			}
+			before := Clone(state)
+
+			got, events, err := Apply(state, tt.cmd)
+			if !reflect.DeepEqual(state, before) {
+				t.Fatalf("Apply modified its input state")
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Data.TeamA.Score != tt.wantScoreA || got.Data.TeamB.Score != tt.wantScoreB {
+				t.Errorf("score = %d-%d, want %d-%d", got.Data.TeamA.Score, got.Data.TeamB.Score, tt.wantScoreA, tt.wantScoreB)
+			}
+			for id, want := range tt.wantStatus {
+				if status := got.Data.PlayerStats[id].Status; status != want {
+					t.Errorf("player %s status = %q, want %q", id, status, want)
+				}
+			}
+			if types := eventTypes(events); !reflect.DeepEqual(types, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", types, tt.wantEvents)
+			}
+			if got.Data.RaidNumber != tt.wantRaidNum {
+				t.Errorf("raid number = %d, want %d", got.Data.RaidNumber, tt.wantRaidNum)
+			}
+			if got.Data.EmptyRaidCounts.TeamA != tt.wantEmptyA {
+				t.Errorf("team A empty raids = %d, want %d", got.Data.EmptyRaidCounts.TeamA, tt.wantEmptyA)
+			}
+			if tt.wantLogEntry != nil {
+				if len(got.Data.RaidLog) == 0 {
+					t.Fatalf("raid log is empty")
+				}
+				last := got.Data.RaidLog[len(got.Data.RaidLog)-1]
+				if !reflect.DeepEqual(last, *tt.wantLogEntry) {
+					t.Errorf("raid log entry = %+v, want %+v", last, *tt.wantLogEntry)
+				}
+			}
+			if got.Data.LastScoreChangeAt != 0 && got.Data.LastScoreChangeAt != testTime.Unix() {
+				t.Errorf("lastScoreChangeAt = %d, want %d", got.Data.LastScoreChangeAt, testTime.Unix())
+			}
+		})
+	}
+}
+
+func TestInitialStateSquadSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		bench     int // players added to each roster beyond the seven
+		wantErr   bool
+		wantBench []string
+	}{
+		{name: "default rules accept seven players", preset: ""},
+		{name: "pro rules accept seven players", preset: models.RuleSetPro},
+		{name: "pro rules bench players beyond seven", preset: models.RuleSetPro, bench: 2, wantBench: []string{"a8", "a9", "b8", "b9"}},
+		{name: "pro rules reject an oversized bench", preset: models.RuleSetPro, bench: 6, wantErr: true},
+		{name: "youth rules bench players beyond five", preset: models.RuleSetYouth, wantBench: []string{"a6", "a7", "b6", "b7"}},
+		{name: "youth rules reject an oversized bench", preset: models.RuleSetYouth, bench: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestMatch()
+			addBench(&state, tt.bench)
+			if tt.preset != "" {
+				state.Data.Rules, _ = models.RuleSetPreset(tt.preset)
+			}
+			next, _, err := Apply(models.EnhancedStatsMessage{}, Command{Type: CommandInitialState, State: state, At: testTime})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(next.Data.StartingBench, tt.wantBench) {
+				t.Errorf("starting bench = %v, want %v", next.Data.StartingBench, tt.wantBench)
+			}
+			for _, id := range tt.wantBench {
+				if got := next.Data.PlayerStats[id].Status; got != "bench" {
+					t.Errorf("%s status = %q, want bench", id, got)
+				}
+			}
+		})
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	commands := []Command{
+		raid("successful", "a1", true, "b1", "b2", "b3"),
+		{Type: CommandLobbyTouch, Lobby: LobbyTouchPayload{TouchedPlayerId: "a2", IsRaider: true, ScoringTeam: "B", RaidNumber: 2}, At: testTime},
+		raid("empty", "a3", false),
+		raid("defense", "b4", false, "a1"),
+		raid("empty", "a4", false),
+	}
+
+	// Initial state is taken as sent, so awards are only filled in once a raid is applied
+	initial := newTestMatch()
+	initial.Data.Awards = ComputeAwards(initial.Data.PlayerStats)
+	states := []models.EnhancedStatsMessage{initial}
+	for i, cmd := range commands {
+		next, _, err := Apply(states[len(states)-1], cmd)
+		if err != nil {
+			t.Fatalf("command %d: %v", i, err)
+		}
+		states = append(states, next)
+	}
+
+	current := states[len(states)-1]
+	for i := len(commands) - 1; i >= 0; i-- {
+		var err error
+		current, _, err = Apply(current, Command{Type: CommandUndo, At: testTime})
+		if err != nil {
+			t.Fatalf("undo to %d: %v", i, err)
+		}
+		assertSameMatchState(t, "undo", current, states[i])
+		if len(current.Data.RedoLog) != len(commands)-i {
+			t.Fatalf("redo log length = %d, want %d", len(current.Data.RedoLog), len(commands)-i)
+		}
+	}
+
+	for i := 1; i <= len(commands); i++ {
+		var err error
+		current, _, err = Apply(current, Command{Type: CommandRedo, At: testTime})
+		if err != nil {
+			t.Fatalf("redo to %d: %v", i, err)
+		}
+		assertSameMatchState(t, "redo", current, states[i])
+	}
+
+	if _, _, err := Apply(current, Command{Type: CommandRedo, At: testTime}); err == nil {
+		t.Fatalf("expected error when redo log is empty")
+	}
+
+	// A new raid after an undo discards the redo log
+	undone, _, _ := Apply(current, Command{Type: CommandUndo, At: testTime})
+	fresh, _, err := Apply(undone, raid("empty", "a5", false))
+	if err != nil {
+		t.Fatalf("raid after undo: %v", err)
+	}
+	if len(fresh.Data.RedoLog) != 0 {
+		t.Errorf("redo log not cleared by new raid")
+	}
+}
+
+func assertSameMatchState(t *testing.T, op string, got, want models.EnhancedStatsMessage) {
+	t.Helper()
+	if got.Data.TeamA.Score != want.Data.TeamA.Score || got.Data.TeamB.Score != want.Data.TeamB.Score {
+		t.Fatalf("%s: score = %d-%d, want %d-%d", op, got.Data.TeamA.Score, got.Data.TeamB.Score, want.Data.TeamA.Score, want.Data.TeamB.Score)
+	}
+	if !reflect.DeepEqual(got.Data.PlayerStats, want.Data.PlayerStats) {
+		t.Fatalf("%s: player stats differ\n got: %+v\nwant: %+v", op, got.Data.PlayerStats, want.Data.PlayerStats)
+	}
+	if got.Data.EmptyRaidCounts != want.Data.EmptyRaidCounts {
+		t.Fatalf("%s: empty raid counts = %+v, want %+v", op, got.Data.EmptyRaidCounts, want.Data.EmptyRaidCounts)
+	}
+	if got.Data.RaidNumber != want.Data.RaidNumber {
+		t.Fatalf("%s: raid number = %d, want %d", op, got.Data.RaidNumber, want.Data.RaidNumber)
+	}
+	if len(got.Data.RaidLog) != len(want.Data.RaidLog) {
+		t.Fatalf("%s: raid log length = %d, want %d", op, len(got.Data.RaidLog), len(want.Data.RaidLog))
+	}
+	if got.Data.Awards != want.Data.Awards {
+		t.Fatalf("%s: awards = %+v, want %+v", op, got.Data.Awards, want.Data.Awards)
+	}
+}
+
+func TestCommandTypesAreKnown(t *testing.T) {
+	for _, cmdType := range CommandTypes {
+		_, _, err := Apply(newTestMatch(), Command{Type: cmdType, At: testTime})
+		if err != nil && err.Error() == "unknown command type: "+cmdType {
+			t.Errorf("%s is listed in CommandTypes but Apply does not know it", cmdType)
+		}
+	}
+}
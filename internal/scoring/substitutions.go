@@ -0,0 +1,97 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// SubstitutionPayload swaps a player on the mat for one on the bench. The team is
+// worked out from the rosters.
+type SubstitutionPayload struct {
+	OutPlayerID string `json:"outPlayerId"`
+	InPlayerID  string `json:"inPlayerId"`
+}
+
+// assignStartingLineups puts the first SquadSize players of each roster on the mat
+// and the rest on the bench, remembering the bench so Rebuild can restore it.
+func assignStartingLineups(match *models.EnhancedStatsMessage) {
+	squadSize := rulesOf(match).SquadSize
+	match.Data.StartingBench = nil
+	match.Data.SubstitutionsUsed = models.TeamCounts{}
+	for _, team := range []string{"A", "B"} {
+		for i, pid := range teamPlayerIDs(match, team) {
+			if i < squadSize {
+				continue
+			}
+			p, _ := ensurePlayerStat(match, pid)
+			p.Status = "bench"
+			match.Data.PlayerStats[pid] = p
+			match.Data.StartingBench = append(match.Data.StartingBench, pid)
+		}
+	}
+}
+
+// ValidateSubstitution checks a substitution against the rosters, player statuses
+// and the rule set's substitution limit, returning the team making it.
+func ValidateSubstitution(sub SubstitutionPayload, match *models.EnhancedStatsMessage) (string, error) {
+	if err := checkMatchNotOver(match); err != nil {
+		return "", err
+	}
+	if sub.OutPlayerID == "" || sub.InPlayerID == "" {
+		return "", fmt.Errorf("outPlayerId and inPlayerId are required")
+	}
+	team := TeamOf(match, sub.OutPlayerID)
+	if team == "" {
+		return "", fmt.Errorf("player not found: %s", sub.OutPlayerID)
+	}
+	if TeamOf(match, sub.InPlayerID) != team {
+		return "", fmt.Errorf("player %s is not on team %s", sub.InPlayerID, team)
+	}
+
+	out, _ := ensurePlayerStat(match, sub.OutPlayerID)
+	switch out.Status {
+	case "in":
+	case "out":
+		return "", fmt.Errorf("player %s is out and cannot be substituted", sub.OutPlayerID)
+	default:
+		return "", fmt.Errorf("player %s is not on the mat", sub.OutPlayerID)
+	}
+	if in, _ := ensurePlayerStat(match, sub.InPlayerID); in.Status != "bench" {
+		return "", fmt.Errorf("player %s is not on the bench", sub.InPlayerID)
+	}
+
+	if used := *substitutionCount(match, team); used >= rulesOf(match).SubstitutionsPerTeam {
+		return "", fmt.Errorf("team %s has no substitutions left", team)
+	}
+	return team, nil
+}
+
+// applySubstitution swaps the two players' statuses and logs the substitution in the
+// raid log so undo/redo and replays see it in order. The raid number does not move.
+func applySubstitution(match *models.EnhancedStatsMessage, sub SubstitutionPayload, team string) []Event {
+	out := match.Data.PlayerStats[sub.OutPlayerID]
+	out.Status = "bench"
+	match.Data.PlayerStats[sub.OutPlayerID] = out
+
+	in, _ := ensurePlayerStat(match, sub.InPlayerID)
+	in.Status = "in"
+	match.Data.PlayerStats[sub.InPlayerID] = in
+
+	*substitutionCount(match, team)++
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{
+		RaidNumber:  match.Data.RaidNumber,
+		RaidingTeam: team,
+		Result:      "substitution",
+		SubOutId:    sub.OutPlayerID,
+		SubInId:     sub.InPlayerID,
+	})
+	return []Event{{Type: EventSubstitution, RaidNumber: match.Data.RaidNumber, Team: team, PlayerIDs: []string{sub.OutPlayerID, sub.InPlayerID}}}
+}
+
+func substitutionCount(match *models.EnhancedStatsMessage, team string) *int {
+	if team == "A" {
+		return &match.Data.SubstitutionsUsed.TeamA
+	}
+	return &match.Data.SubstitutionsUsed.TeamB
+}
@@ -0,0 +1,129 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func subCmd(out, in string) Command {
+	return Command{Type: CommandSubstitution, Sub: SubstitutionPayload{OutPlayerID: out, InPlayerID: in}, At: testTime}
+}
+
+// newBenchMatch starts a Pro rules match with two substitutes per team
+func newBenchMatch(t *testing.T) models.EnhancedStatsMessage {
+	t.Helper()
+	state := newTestMatch()
+	addBench(&state, 2)
+	state.Data.Rules = models.DefaultRuleSet()
+	started, _, err := Apply(models.EnhancedStatsMessage{}, Command{Type: CommandInitialState, State: state, At: testTime})
+	if err != nil {
+		t.Fatalf("initial state: %v", err)
+	}
+	return started
+}
+
+func TestSubstitution(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m *models.EnhancedStatsMessage)
+		cmd     Command
+		wantErr bool
+	}{
+		{name: "bench player replaces an on-mat player", cmd: subCmd("a1", "a8")},
+		{name: "out players cannot be substituted", setup: func(m *models.EnhancedStatsMessage) { setStatus(m, "out", "a1") }, cmd: subCmd("a1", "a8"), wantErr: true},
+		{name: "incoming player must be on the bench", cmd: subCmd("a1", "a2"), wantErr: true},
+		{name: "players must be on the same team", cmd: subCmd("a1", "b8"), wantErr: true},
+		{name: "limit is enforced", setup: func(m *models.EnhancedStatsMessage) { m.Data.SubstitutionsUsed.TeamA = 5 }, cmd: subCmd("a1", "a8"), wantErr: true},
+		{name: "no substitutions after full time", setup: func(m *models.EnhancedStatsMessage) { m.Data.Clock.Status = models.ClockStatusFullTime }, cmd: subCmd("a1", "a8"), wantErr: true},
+		{name: "allowed at half time", setup: func(m *models.EnhancedStatsMessage) { m.Data.Clock.Status = models.ClockStatusHalfTime }, cmd: subCmd("a1", "a8")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newBenchMatch(t)
+			if tt.setup != nil {
+				tt.setup(&state)
+			}
+			next, events, err := Apply(state, tt.cmd)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := next.Data.PlayerStats["a1"].Status; got != "bench" {
+				t.Errorf("a1 status = %q, want bench", got)
+			}
+			if got := next.Data.PlayerStats["a8"].Status; got != "in" {
+				t.Errorf("a8 status = %q, want in", got)
+			}
+			if next.Data.SubstitutionsUsed.TeamA != 1 || next.Data.RaidNumber != state.Data.RaidNumber {
+				t.Errorf("substitutions used = %d, raid number = %d", next.Data.SubstitutionsUsed.TeamA, next.Data.RaidNumber)
+			}
+			if len(events) != 1 || events[0].Type != EventSubstitution {
+				t.Errorf("events = %v", eventTypes(events))
+			}
+			if last := next.Data.RaidLog[len(next.Data.RaidLog)-1]; last.Result != "substitution" || last.SubOutId != "a1" || last.SubInId != "a8" {
+				t.Errorf("raid log entry = %+v", last)
+			}
+		})
+	}
+}
+
+func TestAllOutIgnoresBench(t *testing.T) {
+	state := newBenchMatch(t)
+	setStatus(&state, "out", "b2", "b3", "b4", "b5", "b6", "b7")
+
+	next, events, err := Apply(state, raid("successful", "a1", false, "b1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if types := eventTypes(events); types[len(types)-1] != EventAllOut {
+		t.Fatalf("expected an all out with only substitutes left, got %v", eventTypes(events))
+	}
+	for _, id := range []string{"b8", "b9"} {
+		if got := next.Data.PlayerStats[id].Status; got != "bench" {
+			t.Errorf("%s status = %q, want bench", id, got)
+		}
+	}
+	if got := activePlayers(&next, "B"); got != 7 {
+		t.Errorf("team B on the mat after all out = %d, want 7", got)
+	}
+}
+
+func TestUndoRedoSubstitution(t *testing.T) {
+	state := newBenchMatch(t)
+	steps := []Command{subCmd("a1", "a8"), raid("successful", "a8", false, "b1")}
+	for _, cmd := range steps {
+		var err error
+		if state, _, err = Apply(state, cmd); err != nil {
+			t.Fatalf("%s: %v", cmd.Type, err)
+		}
+	}
+	afterSub := state
+
+	undone, _, err := Apply(state, Command{Type: CommandUndo, At: testTime})
+	if err != nil {
+		t.Fatalf("undo raid: %v", err)
+	}
+	undone, _, err = Apply(undone, Command{Type: CommandUndo, At: testTime})
+	if err != nil {
+		t.Fatalf("undo substitution: %v", err)
+	}
+	if undone.Data.PlayerStats["a1"].Status != "in" || undone.Data.PlayerStats["a8"].Status != "bench" || undone.Data.SubstitutionsUsed.TeamA != 0 {
+		t.Fatalf("undo did not restore the starting line-up")
+	}
+
+	redone, _, err := Apply(undone, Command{Type: CommandRedo, At: testTime})
+	if err == nil {
+		redone, _, err = Apply(redone, Command{Type: CommandRedo, At: testTime})
+	}
+	if err != nil {
+		t.Fatalf("redo: %v", err)
+	}
+	assertSameMatchState(t, "redo", redone, afterSub)
+}
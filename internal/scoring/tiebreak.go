@@ -0,0 +1,92 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// TieBreakRaidsEach is the number of shootout raids each team takes before a golden raid
+const TieBreakRaidsEach = 5
+
+// validateTieBreakStart checks that a level match has finished regulation time
+func validateTieBreakStart(match *models.EnhancedStatsMessage) error {
+	if match.Data.TieBreak.Phase != "" {
+		return fmt.Errorf("the tie-break has already started")
+	}
+	if match.Data.TeamA.Score != match.Data.TeamB.Score {
+		return fmt.Errorf("a tie-break is only played when the scores are level")
+	}
+	// Matches scored without the clock can go straight to a tie-break
+	if match.Data.Clock.Half > 0 && match.Data.Clock.Status != models.ClockStatusFullTime {
+		return fmt.Errorf("end regulation time before starting the tie-break")
+	}
+	return nil
+}
+
+// startTieBreak moves a level match into the shootout. Out players come back for
+// it and the do-or-die count starts again; the start is logged so undo can remove it.
+func startTieBreak(match *models.EnhancedStatsMessage) []Event {
+	match.Data.TieBreak = models.TieBreakState{Phase: models.TieBreakShootout, StartRaid: match.Data.RaidNumber}
+	for _, team := range []string{"A", "B"} {
+		for _, pid := range teamPlayerIDs(match, team) {
+			if p, ok := match.Data.PlayerStats[pid]; ok && p.Status == "out" {
+				p.Status = "in"
+				match.Data.PlayerStats[pid] = p
+			}
+		}
+	}
+	match.Data.EmptyRaidCounts.TeamA = 0
+	match.Data.EmptyRaidCounts.TeamB = 0
+	match.Data.PendingLobby = models.LobbyState{}
+	match.Data.RaidLog = append(match.Data.RaidLog, models.RaidLogEntry{RaidNumber: match.Data.RaidNumber, Result: "tieBreak"})
+	return []Event{{Type: EventTieBreakStarted, RaidNumber: match.Data.RaidNumber}}
+}
+
+// tieBreakActive reports whether tie-break raids are being played
+func tieBreakActive(match *models.EnhancedStatsMessage) bool {
+	phase := match.Data.TieBreak.Phase
+	return phase == models.TieBreakShootout || phase == models.TieBreakGoldenRaid
+}
+
+// scoreTieBreak runs process and moves any points it scored from the team scores to
+// the tie-break score. raidingTeam is "" for lobby touches, which are not raids.
+func scoreTieBreak(match *models.EnhancedStatsMessage, raidingTeam string, process func() []Event) []Event {
+	beforeA, beforeB := match.Data.TeamA.Score, match.Data.TeamB.Score
+	events := process()
+
+	tb := &match.Data.TieBreak
+	tb.Score.TeamA += match.Data.TeamA.Score - beforeA
+	tb.Score.TeamB += match.Data.TeamB.Score - beforeB
+	match.Data.TeamA.Score, match.Data.TeamB.Score = beforeA, beforeB
+	if raidingTeam == "" {
+		return events
+	}
+	if raidingTeam == "A" {
+		tb.RaidsTaken.TeamA++
+	} else {
+		tb.RaidsTaken.TeamB++
+	}
+
+	switch tb.Phase {
+	case models.TieBreakShootout:
+		if tb.RaidsTaken.TeamA < TieBreakRaidsEach || tb.RaidsTaken.TeamB < TieBreakRaidsEach {
+			return events
+		}
+		if tb.Score.TeamA == tb.Score.TeamB {
+			tb.Phase = models.TieBreakGoldenRaid
+			return append(events, Event{Type: EventGoldenRaid, RaidNumber: match.Data.RaidNumber})
+		}
+	case models.TieBreakGoldenRaid:
+		if tb.Score.TeamA == tb.Score.TeamB {
+			return events
+		}
+	}
+
+	tb.Phase = models.TieBreakDecided
+	tb.Winner = "A"
+	if tb.Score.TeamB > tb.Score.TeamA {
+		tb.Winner = "B"
+	}
+	return append(events, Event{Type: EventTieBreakDecided, RaidNumber: match.Data.RaidNumber, Team: tb.Winner})
+}
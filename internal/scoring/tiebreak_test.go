@@ -0,0 +1,163 @@
+package scoring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// newLevelMatch returns a match that has ended level after regulation time. Amateur
+// rules keep do-or-die raids out of the way of the shootout.
+func newLevelMatch(t *testing.T) models.EnhancedStatsMessage {
+	t.Helper()
+	state := newTestMatch()
+	state.Data.Rules, _ = models.RuleSetPreset(models.RuleSetAmateur)
+	state.Data.TeamA.Score = 20
+	state.Data.TeamB.Score = 20
+	state.Data.RaidNumber = 41
+	state.Data.Clock = models.MatchClock{Status: models.ClockStatusFullTime, Half: 2, HalfDurationSec: 1200, SecondHalfStartRaid: 20}
+	return state
+}
+
+func TestTieBreakStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m *models.EnhancedStatsMessage)
+		wantErr bool
+	}{
+		{name: "level at full time"},
+		{name: "clock never used", setup: func(m *models.EnhancedStatsMessage) { m.Data.Clock = models.MatchClock{} }},
+		{name: "scores not level", setup: func(m *models.EnhancedStatsMessage) { m.Data.TeamA.Score++ }, wantErr: true},
+		{name: "regulation still running", setup: func(m *models.EnhancedStatsMessage) { m.Data.Clock.Status = models.ClockStatusRunning }, wantErr: true},
+		{name: "already started", setup: func(m *models.EnhancedStatsMessage) { m.Data.TieBreak.Phase = models.TieBreakShootout }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newLevelMatch(t)
+			if tt.setup != nil {
+				tt.setup(&state)
+			}
+			next, _, err := Apply(state, Command{Type: CommandTieBreak, At: testTime})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if next.Data.TieBreak.Phase != models.TieBreakShootout || next.Data.TieBreak.StartRaid != 41 {
+				t.Errorf("tie-break = %+v", next.Data.TieBreak)
+			}
+			if got := ExpectedRaidingTeam(&next); got != "A" {
+				t.Errorf("tie-break opened by team %s, want A", got)
+			}
+		})
+	}
+}
+
+func TestTieBreakShootoutAndGoldenRaid(t *testing.T) {
+	state := newLevelMatch(t)
+	apply := func(cmd Command) []Event {
+		t.Helper()
+		next, events, err := Apply(state, cmd)
+		if err != nil {
+			t.Fatalf("%s raid %d: %v", cmd.Type, state.Data.RaidNumber, err)
+		}
+		state = next
+		return events
+	}
+
+	apply(Command{Type: CommandTieBreak, At: testTime})
+	// Each team scores once with its first raid, then every raid is empty
+	apply(raid("successful", "a1", false, "b1"))
+	apply(raid("successful", "b2", false, "a2"))
+	for i := 0; i < 4; i++ {
+		apply(raid("empty", "a3", false))
+		apply(raid("empty", "b3", false))
+	}
+	if state.Data.TieBreak.Phase != models.TieBreakGoldenRaid {
+		t.Fatalf("phase after the shootout = %q, want goldenRaid", state.Data.TieBreak.Phase)
+	}
+	if state.Data.TeamA.Score != 20 || state.Data.TeamB.Score != 20 {
+		t.Fatalf("regulation score changed to %d-%d", state.Data.TeamA.Score, state.Data.TeamB.Score)
+	}
+
+	apply(raid("empty", "a3", false))
+	events := apply(raid("successful", "b3", false, "a3"))
+	if types := eventTypes(events); types[len(types)-1] != EventTieBreakDecided {
+		t.Fatalf("events = %v, want tieBreakDecided last", types)
+	}
+	tb := state.Data.TieBreak
+	if tb.Phase != models.TieBreakDecided || tb.Winner != "B" || tb.Score.TeamA != 1 || tb.Score.TeamB != 2 {
+		t.Fatalf("tie-break = %+v", tb)
+	}
+	if _, _, err := Apply(state, raid("empty", "a4", false)); err == nil {
+		t.Fatalf("expected raids to be rejected once the tie-break is decided")
+	}
+
+	// Undo reopens the golden raid
+	apply(Command{Type: CommandUndo, At: testTime})
+	if state.Data.TieBreak.Phase != models.TieBreakGoldenRaid || state.Data.TieBreak.Winner != "" {
+		t.Fatalf("tie-break after undo = %+v", state.Data.TieBreak)
+	}
+}
+
+func TestTieBreakShootoutDecides(t *testing.T) {
+	state := newLevelMatch(t)
+	var err error
+	if state, _, err = Apply(state, Command{Type: CommandTieBreak, At: testTime}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for i := 0; i < TieBreakRaidsEach; i++ {
+		defender := fmt.Sprintf("b%d", i+3)
+		if state, _, err = Apply(state, raid("successful", "a1", false, defender)); err != nil {
+			t.Fatalf("raid A %d: %v", i, err)
+		}
+		if i == TieBreakRaidsEach-1 {
+			break
+		}
+		if state.Data.TieBreak.Phase != models.TieBreakShootout {
+			t.Fatalf("decided before both teams took five raids")
+		}
+		if state, _, err = Apply(state, raid("empty", "b2", false)); err != nil {
+			t.Fatalf("raid B %d: %v", i, err)
+		}
+	}
+	if state, _, err = Apply(state, raid("empty", "b2", false)); err != nil {
+		t.Fatalf("last raid: %v", err)
+	}
+	if state.Data.TieBreak.Winner != "A" {
+		t.Fatalf("tie-break = %+v, want A to win", state.Data.TieBreak)
+	}
+}
+
+func TestTechnicalPointInTieBreak(t *testing.T) {
+	state := newLevelMatch(t)
+	var err error
+	for _, cmd := range []Command{
+		{Type: CommandTieBreak, At: testTime},
+		{Type: CommandTechnicalPoint, Tech: TechnicalPointPayload{Team: "B"}, At: testTime},
+	} {
+		if state, _, err = Apply(state, cmd); err != nil {
+			t.Fatalf("%s: %v", cmd.Type, err)
+		}
+	}
+	if state.Data.TeamA.Score != 20 || state.Data.TeamB.Score != 20 {
+		t.Errorf("regulation score changed to %d-%d", state.Data.TeamA.Score, state.Data.TeamB.Score)
+	}
+	tb := state.Data.TieBreak
+	if tb.Score.TeamB != 1 || tb.RaidsTaken.TeamA+tb.RaidsTaken.TeamB != 0 || tb.Phase != models.TieBreakShootout {
+		t.Errorf("tie-break = %+v, want one point to B and no raid taken", tb)
+	}
+
+	// Replaying the log puts the point in the same place
+	replayed := Clone(state)
+	Rebuild(&replayed, state.Data.RaidLog)
+	if replayed.Data.TieBreak.Score != tb.Score {
+		t.Errorf("tie-break after rebuild = %+v", replayed.Data.TieBreak)
+	}
+}
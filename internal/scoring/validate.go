@@ -0,0 +1,171 @@
+package scoring
+
+import (
+	"fmt"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// ValidateRaid performs server-side validation of incoming raid data.
+// Backend automatically determines which team the raider belongs to.
+// Players listed in a team roster but missing from PlayerStats are given a
+// default stat entry so validation can proceed.
+func ValidateRaid(raid RaidPayload, match *models.EnhancedStatsMessage) error {
+	// raid type
+	if raid.RaidType != "successful" && raid.RaidType != "defense" && raid.RaidType != "empty" {
+		return fmt.Errorf("invalid raidType: %s", raid.RaidType)
+	}
+
+	if err := checkClockAllowsPlay(match); err != nil {
+		return err
+	}
+	return validateRaidPlayers(raid, match)
+}
+
+// validateRaidPlayers checks the raider and defenders of a raid against the match
+// state, without regard to the clock. Review corrections use it directly because
+// they re-score a raid that was played earlier.
+func validateRaidPlayers(raid RaidPayload, match *models.EnhancedStatsMessage) error {
+	if raid.RaidType != "successful" && raid.RaidType != "defense" && raid.RaidType != "empty" {
+		return fmt.Errorf("invalid raidType: %s", raid.RaidType)
+	}
+
+	// raider exists
+	if raid.RaiderID == "" {
+		return fmt.Errorf("missing raiderId")
+	}
+	raider, ok := ensurePlayerStat(match, raid.RaiderID)
+	if !ok {
+		return fmt.Errorf("raider not found: %s", raid.RaiderID)
+	}
+	if raider.Status != "in" {
+		return fmt.Errorf("raider is not active: %s", raid.RaiderID)
+	}
+
+	// Determine which team the raider belongs to (backend logic, not frontend)
+	raiderTeam := TeamOf(match, raid.RaiderID)
+	if raiderTeam == "" {
+		return fmt.Errorf("raider does not belong to any team: %s", raid.RaiderID)
+	}
+
+	// Verify alternating raid rule - backend determines expected team based on toss
+	expectedRaidingTeam := ExpectedRaidingTeam(match)
+	if raiderTeam != expectedRaidingTeam {
+		return fmt.Errorf("incorrect raiding team. Expected team %s to raid, but raider belongs to team %s", expectedRaidingTeam, raiderTeam)
+	}
+
+	// defenders validation for types that require defenders
+	if raid.RaidType == "successful" || raid.RaidType == "defense" {
+		if len(raid.DefenderIDs) == 0 {
+			return fmt.Errorf("defenderIds required for raidType %s", raid.RaidType)
+		}
+		for _, defID := range raid.DefenderIDs {
+			if defID == raid.RaiderID {
+				return fmt.Errorf("defenderId equals raiderId: %s", defID)
+			}
+			d, ok := ensurePlayerStat(match, defID)
+			if !ok {
+				return fmt.Errorf("defender not found: %s", defID)
+			}
+			if d.Status != "in" {
+				return fmt.Errorf("defender is not active: %s", defID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateInitialState checks a new match against its rule set before scoring starts.
+// Each roster needs a full squad and may name up to BenchSize substitutes on top.
+// Rosters that have not been sent yet are not checked.
+func ValidateInitialState(match *models.EnhancedStatsMessage) error {
+	rules := match.Data.Rules.OrDefault()
+	if err := rules.Validate(); err != nil {
+		return fmt.Errorf("invalid rule set: %v", err)
+	}
+	for _, team := range []string{"A", "B"} {
+		roster := teamPlayerIDs(match, team)
+		if len(roster) == 0 {
+			continue
+		}
+		if len(roster) < rules.SquadSize {
+			return fmt.Errorf("team %s must field %d players under %s rules, got %d", team, rules.SquadSize, rules.Name, len(roster))
+		}
+		if len(roster) > rules.SquadSize+rules.BenchSize {
+			return fmt.Errorf("team %s may name at most %d substitutes under %s rules, got %d", team, rules.BenchSize, rules.Name, len(roster)-rules.SquadSize)
+		}
+	}
+	return nil
+}
+
+// ensurePlayerStat returns the stat entry for a player, initializing a default
+// one if the player is on a roster but missing from PlayerStats (possible if the
+// client initialized player lists but didn't populate stats).
+func ensurePlayerStat(match *models.EnhancedStatsMessage, playerID string) (models.PlayerStat, bool) {
+	if p, ok := match.Data.PlayerStats[playerID]; ok {
+		return p, true
+	}
+	if TeamOf(match, playerID) == "" {
+		return models.PlayerStat{}, false
+	}
+	ps := models.PlayerStat{
+		Name:   playerID,
+		ID:     playerID,
+		Status: "in",
+	}
+	if match.Data.PlayerStats == nil {
+		match.Data.PlayerStats = make(map[string]models.PlayerStat)
+	}
+	match.Data.PlayerStats[playerID] = ps
+	return ps, true
+}
+
+// TeamOf returns the team ("A" or "B") a player is rostered on, or "" if neither
+func TeamOf(match *models.EnhancedStatsMessage, playerID string) string {
+	for _, pid := range match.Data.TeamAPlayerIDs {
+		if pid == playerID {
+			return "A"
+		}
+	}
+	for _, pid := range match.Data.TeamBPlayerIDs {
+		if pid == playerID {
+			return "B"
+		}
+	}
+	return ""
+}
+
+// ExpectedRaidingTeam returns the team ("A" or "B") due to raid at the current
+// raid number, based on the toss result. In the first half odd raids belong to the
+// first raiding team; the second half is opened by the other team and alternates
+// from the raid number it started at. The tie-break is opened by the team that
+// raided first in the match.
+func ExpectedRaidingTeam(match *models.EnhancedStatsMessage) string {
+	firstRaider := "A"
+	if match.Data.FirstRaidingTeam == "teamB" {
+		firstRaider = "B"
+	}
+	// "teamA" is also the fallback for legacy matches without a toss result
+
+	raidsIntoHalf := match.Data.RaidNumber - 1
+	if start := match.Data.TieBreak.StartRaid; start > 0 && match.Data.RaidNumber >= start {
+		raidsIntoHalf = match.Data.RaidNumber - start
+	} else if start := match.Data.Clock.SecondHalfStartRaid; start > 0 && match.Data.RaidNumber >= start {
+		firstRaider = opponent(firstRaider)
+		raidsIntoHalf = match.Data.RaidNumber - start
+	}
+
+	if raidsIntoHalf%2 == 0 {
+		return firstRaider
+	}
+	return opponent(firstRaider)
+}
+
+// opponent returns the other team label
+func opponent(team string) string {
+	if team == "A" {
+		return "B"
+	}
+	return "A"
+}
@@ -0,0 +1,198 @@
+package scoring
+
+import (
+	"math"
+	"time"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+// shootoutRaids is how many raids a tie-break shootout has, both teams together
+const shootoutRaids = 10
+
+// minWinProbSamples is how many historical match states a fit needs; with fewer
+// the model it was given is kept
+const minWinProbSamples = 200
+
+// WinProbSample is one historical match state: its model features and whether
+// team A went on to win (1), lose (0) or draw (0.5)
+type WinProbSample struct {
+	Features [3]float64
+	Outcome  float64
+}
+
+// WinProbability estimates each team's chance of winning from the current state:
+// the score margin, how much of the match is left, players on the mat and a
+// pending do-or-die raid. at is used to read a running clock.
+func WinProbability(match *models.EnhancedStatsMessage, model models.WinProbModel, at time.Time) models.WinProbability {
+	d := match.Data
+	if d.TieBreak.Phase == models.TieBreakDecided {
+		return certainWin(d.TieBreak.Winner)
+	}
+	if d.Clock.Status == models.ClockStatusFullTime && d.TieBreak.Phase == "" && d.TeamA.Score != d.TeamB.Score {
+		return certainWin(ternaryString(d.TeamA.Score > d.TeamB.Score, "A", "B"))
+	}
+	f := winProbFeatures(match, remainingFraction(match, model, at))
+	p := sigmoid(model.Margin*f[0] + model.Mat*f[1] + model.DoOrDie*f[2])
+	return models.WinProbability{TeamA: p, TeamB: 1 - p}
+}
+
+func certainWin(team string) models.WinProbability {
+	if team == "A" {
+		return models.WinProbability{TeamA: 1}
+	}
+	return models.WinProbability{TeamB: 1}
+}
+
+// remainingFraction is the share of the match still to play, from the clock when
+// it is in use and from the raid number otherwise. A tie-break counts its raids.
+func remainingFraction(match *models.EnhancedStatsMessage, model models.WinProbModel, at time.Time) float64 {
+	d := match.Data
+	switch d.TieBreak.Phase {
+	case models.TieBreakShootout:
+		taken := d.TieBreak.RaidsTaken.TeamA + d.TieBreak.RaidsTaken.TeamB
+		return clamp01(float64(shootoutRaids-taken) / shootoutRaids)
+	case models.TieBreakGoldenRaid:
+		return 0
+	}
+
+	clock := d.Clock
+	if clock.Half > 0 && clock.HalfDurationSec > 0 {
+		if clock.Status == models.ClockStatusFullTime {
+			return 0
+		}
+		elapsed := ClockElapsed(clock, at)
+		if elapsed > clock.HalfDurationSec {
+			elapsed = clock.HalfDurationSec
+		}
+		played := (clock.Half-1)*clock.HalfDurationSec + elapsed
+		return clamp01(1 - float64(played)/float64(2*clock.HalfDurationSec))
+	}
+
+	// Without the clock the end of the match is unknown, so never treat it as over
+	expected := model.RaidsPerMinute * float64(2*rulesOf(match).HalfMinutes)
+	if expected <= 0 {
+		return 1
+	}
+	return math.Max(0.05, clamp01(1-float64(d.RaidNumber-1)/expected))
+}
+
+// winProbFeatures returns the model inputs, all from team A's side: the lead
+// scaled up as time runs out, the difference in players on the mat, and +1 or -1
+// when team B or team A raids next on a do-or-die raid.
+func winProbFeatures(match *models.EnhancedStatsMessage, remaining float64) [3]float64 {
+	d := match.Data
+	margin := d.TeamA.Score - d.TeamB.Score
+	if d.TieBreak.Phase != "" {
+		margin = d.TieBreak.Score.TeamA - d.TieBreak.Score.TeamB
+	}
+
+	doOrDie := 0.0
+	if next := ExpectedRaidingTeam(match); isDoOrDie(match, next) {
+		doOrDie = ternaryFloat(next == "A", -1, 1)
+	}
+	return [3]float64{
+		float64(margin) / math.Sqrt(remaining+0.02),
+		float64(activePlayers(match, "A") - activePlayers(match, "B")),
+		doOrDie,
+	}
+}
+
+// WinProbSamples replays a finished match's raid log and returns the state after
+// each entry along with the final result. Time left is measured in raids, since
+// old matches do not record when each raid happened.
+func WinProbSamples(match models.EnhancedStatsMessage) []WinProbSample {
+	d := match.Data
+	entries := cloneRaidLog(d.RaidLog)
+	if len(entries) == 0 || len(d.PlayerStats) == 0 {
+		return nil
+	}
+
+	outcome := 0.5
+	switch {
+	case d.TeamA.Score > d.TeamB.Score:
+		outcome = 1
+	case d.TeamA.Score < d.TeamB.Score:
+		outcome = 0
+	case d.TieBreak.Winner != "":
+		outcome = ternaryFloat(d.TieBreak.Winner == "A", 1, 0)
+	}
+
+	state := Clone(match)
+	Rebuild(&state, nil)
+	samples := make([]WinProbSample, 0, len(entries))
+	for i, entry := range entries {
+		applyRaidLogEntry(&state, entry)
+		remaining := 1 - float64(i+1)/float64(len(entries))
+		samples = append(samples, WinProbSample{Features: winProbFeatures(&state, remaining), Outcome: outcome})
+	}
+	return samples
+}
+
+// FitWinProbModel fits the model weights to historical samples by logistic
+// regression, starting from base. RaidsPerMinute is left to the caller.
+func FitWinProbModel(samples []WinProbSample, base models.WinProbModel) models.WinProbModel {
+	if len(samples) < minWinProbSamples {
+		return base
+	}
+
+	// Scale each feature to unit size so one learning rate suits all three
+	var scale [3]float64
+	for _, s := range samples {
+		for j, x := range s.Features {
+			scale[j] += x * x
+		}
+	}
+	for j := range scale {
+		scale[j] = math.Sqrt(scale[j] / float64(len(samples)))
+		if scale[j] == 0 {
+			scale[j] = 1
+		}
+	}
+
+	w := [3]float64{base.Margin * scale[0], base.Mat * scale[1], base.DoOrDie * scale[2]}
+	const (
+		iterations = 500
+		rate       = 0.5
+		ridge      = 0.001
+	)
+	n := float64(len(samples))
+	for it := 0; it < iterations; it++ {
+		var grad [3]float64
+		for _, s := range samples {
+			z := 0.0
+			for j := range w {
+				z += w[j] * s.Features[j] / scale[j]
+			}
+			diff := sigmoid(z) - s.Outcome
+			for j := range grad {
+				grad[j] += diff * s.Features[j] / scale[j]
+			}
+		}
+		for j := range w {
+			w[j] -= rate * (grad[j]/n + ridge*w[j])
+		}
+	}
+
+	fitted := base
+	fitted.Margin = w[0] / scale[0]
+	fitted.Mat = w[1] / scale[1]
+	fitted.DoOrDie = w[2] / scale[2]
+	fitted.Samples = len(samples)
+	return fitted
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+func clamp01(x float64) float64 {
+	return math.Min(1, math.Max(0, x))
+}
+
+func ternaryFloat(cond bool, a, b float64) float64 {
+	if cond {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,131 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mhatrejeets/RaidX/internal/models"
+)
+
+func TestWinProbability(t *testing.T) {
+	model := models.DefaultWinProbModel()
+	tests := []struct {
+		name  string
+		setup func(m *models.EnhancedStatsMessage)
+		want  func(p float64) bool // team A's chance
+	}{
+		{name: "level at kick-off", want: func(p float64) bool { return math.Abs(p-0.5) < 1e-9 }},
+		{
+			name:  "leading",
+			setup: func(m *models.EnhancedStatsMessage) { m.Data.TeamA.Score = 6 },
+			want:  func(p float64) bool { return p > 0.6 },
+		},
+		{
+			name:  "more players on the mat",
+			setup: func(m *models.EnhancedStatsMessage) { setStatus(m, "out", "b1", "b2", "b3") },
+			want:  func(p float64) bool { return p > 0.55 },
+		},
+		{
+			name:  "team A raids next on a do-or-die raid",
+			setup: func(m *models.EnhancedStatsMessage) { m.Data.EmptyRaidCounts.TeamA = 2 },
+			want:  func(p float64) bool { return p < 0.5 },
+		},
+		{
+			name: "full time",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.TeamB.Score = 1
+				m.Data.Clock = models.MatchClock{Status: models.ClockStatusFullTime, Half: 2, HalfDurationSec: 1200}
+			},
+			want: func(p float64) bool { return p == 0 },
+		},
+		{
+			name: "tie-break decided",
+			setup: func(m *models.EnhancedStatsMessage) {
+				m.Data.TieBreak = models.TieBreakState{Phase: models.TieBreakDecided, Winner: "A"}
+			},
+			want: func(p float64) bool { return p == 1 },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newTestMatch()
+			if tt.setup != nil {
+				tt.setup(&state)
+			}
+			got := WinProbability(&state, model, testTime)
+			if !tt.want(got.TeamA) {
+				t.Errorf("team A chance %.3f not as expected", got.TeamA)
+			}
+			if math.Abs(got.TeamA+got.TeamB-1) > 1e-9 {
+				t.Errorf("chances %.3f and %.3f do not add up to 1", got.TeamA, got.TeamB)
+			}
+		})
+	}
+}
+
+func TestWinProbabilityLeadCountsMoreLate(t *testing.T) {
+	model := models.DefaultWinProbModel()
+	early := newTestMatch()
+	early.Data.TeamA.Score = 3
+	late := Clone(early)
+	late.Data.RaidNumber = 70
+
+	if pe, pl := WinProbability(&early, model, testTime).TeamA, WinProbability(&late, model, testTime).TeamA; pl <= pe {
+		t.Errorf("a 3 point lead is worth %.3f at raid 70 and %.3f at raid 1, want more late", pl, pe)
+	}
+}
+
+func TestWinProbSamples(t *testing.T) {
+	state := newTestMatch()
+	for _, cmd := range []Command{
+		raid("successful", "a1", false, "b1"),
+		raid("defense", "b2", false, "a3"),
+		raid("successful", "a2", true, "b4", "b5"),
+	} {
+		var err error
+		if state, _, err = Apply(state, cmd); err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+	}
+
+	samples := WinProbSamples(state)
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, want one per raid", len(samples))
+	}
+	for i, s := range samples {
+		if s.Outcome != 1 {
+			t.Errorf("sample %d: outcome %.1f, want team A's win", i, s.Outcome)
+		}
+	}
+	if samples[2].Features[0] <= samples[0].Features[0] {
+		t.Errorf("margin feature did not grow with the lead: %v", samples)
+	}
+}
+
+func TestFitWinProbModel(t *testing.T) {
+	base := models.DefaultWinProbModel()
+	if got := FitWinProbModel(nil, base); got != base {
+		t.Errorf("fit without samples changed the model: %+v", got)
+	}
+
+	// Leaders win, whatever else is going on
+	var samples []WinProbSample
+	for i := 0; i < 400; i++ {
+		margin := float64(i%11 - 5)
+		outcome := 0.5
+		if margin > 0 {
+			outcome = 1
+		} else if margin < 0 {
+			outcome = 0
+		}
+		samples = append(samples, WinProbSample{Features: [3]float64{margin, float64(i%3 - 1), 0}, Outcome: outcome})
+	}
+	fitted := FitWinProbModel(samples, base)
+	if fitted.Margin <= base.Margin {
+		t.Errorf("margin weight %.3f, want more than %.3f when leaders always win", fitted.Margin, base.Margin)
+	}
+	if fitted.Samples != len(samples) {
+		t.Errorf("samples %d, want %d", fitted.Samples, len(samples))
+	}
+}
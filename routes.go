@@ -62,6 +62,12 @@ func setupPublicRoutes(app *fiber.App) {
 	app.Get("/api/public/championships/:id/fixtures", handlers.GetChampionshipFixturesHandler)
 	app.Get("/api/public/championships/:id/stats", handlers.GetChampionshipStatsHandler)
 	app.Get("/api/public/team/:id", handlers.GetPublicTeamByIDHandler)
+	// Live match updates as server-sent events, for viewers that cannot use websockets
+	app.Get("/api/public/match/:id/stream", handlers.MatchStreamHandler)
+	// Generated text commentary, live and after the match
+	app.Get("/api/public/match/:id/commentary", handlers.GetMatchCommentaryHandler)
+	// Win probability after each raid, for the win-probability graph
+	app.Get("/api/public/match/:id/winprobability", handlers.GetMatchWinProbabilityHandler)
 
 	// Public invite link pages (anyone can visit)
 	app.Get("/invite/team/:token", func(c *fiber.Ctx) error {
@@ -106,6 +112,13 @@ func setupProtectedRoutes(app *fiber.App) {
 	app.Post("/api/organizer/events/:id/continue-match", middleware.RoleRequired(models.RoleOrganizer), handlers.ContinueOrganizerMatchHandler)
 	app.Post("/api/organizer/events/:id/restart-match", middleware.RoleRequired(models.RoleOrganizer), handlers.RestartOrganizerMatchHandler)
 
+	// RBAC: Organizer webhooks
+	app.Post("/api/organizer/events/:id/webhooks", middleware.RoleRequired(models.RoleOrganizer), handlers.CreateWebhookHandler)
+	app.Get("/api/organizer/events/:id/webhooks", middleware.RoleRequired(models.RoleOrganizer), handlers.ListWebhooksHandler)
+	app.Delete("/api/organizer/events/:id/webhooks/:webhookId", middleware.RoleRequired(models.RoleOrganizer), handlers.DeleteWebhookHandler)
+	app.Get("/api/organizer/events/:id/webhooks/:webhookId/deliveries", middleware.RoleRequired(models.RoleOrganizer), handlers.ListWebhookDeliveriesHandler)
+	app.Post("/api/organizer/events/:id/webhooks/:webhookId/deliveries/:deliveryId/replay", middleware.RoleRequired(models.RoleOrganizer), handlers.ReplayWebhookDeliveryHandler)
+
 	// RBAC: Tournament APIs
 	app.Post("/api/tournaments/initialize/:id", middleware.RoleRequired(models.RoleOrganizer), handlers.InitializeTournamentHandler)
 	app.Get("/api/tournaments/:id/fixtures", middleware.RoleRequired(models.RoleOrganizer), handlers.GetTournamentFixturesHandler)
@@ -207,7 +220,13 @@ func setupProtectedRoutes(app *fiber.App) {
 	// RBAC: Shared Match APIs
 	app.Get("/api/matches", middleware.RoleRequired(models.RoleTeamOwner, models.RoleOrganizer), handlers.GetAllMatches)
 	app.Get("/api/matches/:id", middleware.RoleRequired(models.RoleTeamOwner, models.RoleOrganizer), handlers.GetMatchByID)
-	app.Post("/api/matches/raid", middleware.RoleRequired(models.RoleTeamOwner, models.RoleOrganizer), handlers.ProcessRaidResult)
+	app.Post("/api/matches/:id/commands", middleware.RoleRequired(models.RoleOrganizer), handlers.PostMatchCommandHandler)
+	app.Get("/api/matches/:id/events", middleware.RoleRequired(models.RoleOrganizer), handlers.GetMatchEventsHandler)
+	app.Post("/api/matches/:id/rebuild", middleware.RoleRequired(models.RoleOrganizer), handlers.RebuildMatchStateHandler)
+	app.Get("/api/matches/:id/rules", middleware.RoleRequired(models.RoleTeamOwner, models.RoleOrganizer), handlers.GetMatchRulesHandler)
+	app.Get("/api/matches/:id/scorers", middleware.RoleRequired(models.RoleOrganizer), handlers.GetScorerRoomHandler)
+	app.Post("/api/matches/:id/scorer/handoff", middleware.RoleRequired(models.RoleOrganizer), handlers.ScorerHandoffHandler)
+	app.Get("/api/rulesets/presets", middleware.AuthRequired, handlers.GetRuleSetPresetsHandler)
 	app.Get("/endgame", middleware.AuthRequired, handlers.EndGameHandler)
 	app.Get("/api/endgame", middleware.AuthRequired, handlers.EndGameHandler)
 